@@ -1,24 +1,46 @@
 package dsco
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"reflect"
+	"time"
 
 	"github.com/byte4ever/dsco/internal/cmdline"
 	"github.com/byte4ever/dsco/internal/env"
+	"github.com/byte4ever/dsco/internal/file"
 	"github.com/byte4ever/dsco/internal/ierror"
+	"github.com/byte4ever/dsco/internal/kfile"
+	"github.com/byte4ever/dsco/internal/remote"
+	"github.com/byte4ever/dsco/svalue"
 )
 
 type layerBuilder struct {
+	ctx      context.Context
+	model    ModelInterface
 	idDedup  map[string]int
 	builders []constraintLayerPolicy
 }
 
 type Layers []Layer
 
-func (layers Layers) GetPolicies() (
+func (layers Layers) GetPolicies(model ModelInterface) (
+	//nolint:revive // need refactoring
+	constraintLayerPolicies,
+	error,
+) {
+	return layers.GetPoliciesContext(context.Background(), model)
+}
+
+// GetPoliciesContext implements PoliciesGetter. A context layer reports the
+// ctx error when ctx is done before its source is read.
+func (layers Layers) GetPoliciesContext(
+	ctx context.Context,
+	model ModelInterface,
+) (
 	//nolint:revive // need refactoring
 	constraintLayerPolicies,
 	error,
@@ -26,14 +48,21 @@ func (layers Layers) GetPolicies() (
 	var errs LayerErrors
 
 	bo := newLayerBuilder(len(layers))
+	bo.ctx = ctx
+	bo.model = model
 
 	for index, layer := range layers {
-		err := layer.register(bo)
+		err := ctx.Err()
+		if _, ok := layer.(ContextLayer); !ok || err == nil {
+			err = layer.register(bo)
+		}
+
 		if err != nil {
 			errs.Add(
 				ierror.IError{
 					Index: index,
 					Info:  "layer",
+					Name:  layerSetupName(layer),
 					Err:   err,
 				},
 			)
@@ -47,18 +76,80 @@ func (layers Layers) GetPolicies() (
 	return nil, errs
 }
 
+// layerSetupName returns the name of layer, known before it is registered,
+// like layerName returns it once registered, empty for unknown layers.
+//
+//nolint:cyclop // one case per layer type
+func layerSetupName(layer Layer) string {
+	switch l := layer.(type) {
+	case *CmdlineLayer, *StrictCmdlineLayer:
+		return "cmdline"
+	case *EnvLayer:
+		return "env:" + l.prefix
+	case *StrictEnvLayer:
+		return "env:" + l.prefix
+	case *FileLayer:
+		return "file:" + filepath.Clean(l.path)
+	case *StrictFileLayer:
+		return "file:" + filepath.Clean(l.path)
+	case *KFileLayer:
+		return "kfile:" + filepath.Clean(l.dir)
+	case *StrictKFileLayer:
+		return "kfile:" + filepath.Clean(l.dir)
+	case *HTTPLayer:
+		return "http:" + l.fetcher.RedactedURL()
+	case *StrictHTTPLayer:
+		return "http:" + l.fetcher.RedactedURL()
+	case *SecretLayer:
+		return "secret:" + l.id
+	case *StrictSecretLayer:
+		return "secret:" + l.id
+	case *StructLayer:
+		return "struct:" + l.id
+	case *StrictStructLayer:
+		return "struct:" + l.id
+	case *StringProviderLayer:
+		return l.provider.GetName()
+	case *StrictStringProviderLayer:
+		return l.provider.GetName()
+	case *ContextProviderLayer:
+		return l.provider.GetName()
+	default:
+		return ""
+	}
+}
+
 // Layer defines a configuration layer.
 type Layer interface {
 	register(to *layerBuilder) error
 }
 
+// ContextLayer is a layer reading its source with the fill context, so
+// FillContext can bound or cancel it: file, kfile, HTTP, secret and context
+// provider layers.
+type ContextLayer interface {
+	Layer
+	contextLayer()
+}
+
+func (*FileLayer) contextLayer()            {}
+func (*StrictFileLayer) contextLayer()      {}
+func (*KFileLayer) contextLayer()           {}
+func (*StrictKFileLayer) contextLayer()     {}
+func (*HTTPLayer) contextLayer()            {}
+func (*StrictHTTPLayer) contextLayer()      {}
+func (*SecretLayer) contextLayer()          {}
+func (*ContextProviderLayer) contextLayer() {}
+
 // StrictCmdlineLayer is a strict command line layer.
 type StrictCmdlineLayer struct {
+	scope   *cmdlineScope
 	options []Option
 }
 
 // CmdlineLayer is a command line layer.
 type CmdlineLayer struct {
+	scope   *cmdlineScope
 	options []Option
 }
 
@@ -86,6 +177,55 @@ type StructLayer struct {
 	id    string
 }
 
+// StrictFileLayer is a strict configuration file layer.
+type StrictFileLayer struct {
+	path    string
+	options []Option
+}
+
+// FileLayer is a configuration file layer.
+type FileLayer struct {
+	path    string
+	options []Option
+}
+
+// StrictKFileLayer is a strict kfile directory layer.
+type StrictKFileLayer struct {
+	dir     string
+	options []Option
+}
+
+// KFileLayer is a kfile directory layer.
+type KFileLayer struct {
+	dir     string
+	options []Option
+}
+
+// StrictHTTPLayer is a strict HTTP configuration document layer.
+type StrictHTTPLayer struct {
+	fetcher *remote.Fetcher
+	options []Option
+}
+
+// HTTPLayer is an HTTP configuration document layer.
+type HTTPLayer struct {
+	fetcher *remote.Fetcher
+	options []Option
+}
+
+// StrictSecretLayer is a strict secret store layer.
+type StrictSecretLayer struct {
+	SecretLayer
+}
+
+// SecretLayer is a secret store layer.
+type SecretLayer struct {
+	client  SecretClient
+	lease   *secretLease
+	id      string
+	options []Option
+}
+
 // CmdLine builds a command line manager.
 func CmdLine(options ...Option) (
 	*StringBasedBuilder,
@@ -116,6 +256,7 @@ func Env(prefix string, options ...Option) (
 
 func newLayerBuilder(l int) *layerBuilder {
 	return &layerBuilder{
+		ctx: context.Background(),
 		builders: make(
 			constraintLayerPolicies,
 			0,
@@ -147,6 +288,7 @@ func wrapCmdlineBuild(
 	to *layerBuilder,
 	wrap func(FieldValuesGetter) constraintLayerPolicy,
 	options []Option,
+	scope *cmdlineScope,
 ) error {
 	if idx := to.dedupId("cmdLine"); idx != nil {
 		return CmdlineAlreadyUsedError{
@@ -154,20 +296,53 @@ func wrapCmdlineBuild(
 		}
 	}
 
-	cmdLine, err := cmdline.NewEntriesProvider(os.Args[1:])
+	args, options := cmdlineArgs(options)
+
+	formatter := newCmdlineKeyFormatter()
+
+	options, taggedKeys, err := keyTagAliases(
+		to.model,
+		flagKeyTag(),
+		formatter,
+		options,
+	)
+	if err != nil {
+		return err
+	}
+
+	cmdlineOptions, builderOptions := splitCmdlineOptions(options)
+	cmdlineOptions = append(
+		cmdlineOptions,
+		cmdlineKeyKinds(to.model, builderOptions)...,
+	)
+	cmdlineOptions = append(cmdlineOptions, cmdline.WithStrictPositionals())
+
+	if scope != nil {
+		if scope.args != nil {
+			args = scope.args
+		}
+
+		cmdlineOptions = append(cmdlineOptions, scope.options()...)
+	}
+
+	cmdLine, err := cmdline.NewEntriesProvider(args, cmdlineOptions...)
 	if err != nil {
 		return fmt.Errorf("cmdline builder: %w", err)
 	}
 
 	builder, err := newStringBasedBuilderWithFormatter(
 		cmdLine,
-		newCmdlineKeyFormatter(),
-		options...,
+		formatter,
+		builderOptions...,
 	)
 	if err != nil {
 		return err
 	}
 
+	builder.taggedKeys = taggedKeys
+	builder.helpRequested = cmdLine.HelpRequested()
+	builder.scope = scope
+
 	to.builders = append(to.builders, wrap(builder))
 
 	return nil
@@ -178,6 +353,7 @@ func (o *StrictCmdlineLayer) register(to *layerBuilder) error {
 		to,
 		newStrictLayer,
 		o.options,
+		o.scope,
 	)
 }
 
@@ -194,6 +370,7 @@ func (o *CmdlineLayer) register(to *layerBuilder) error {
 		to,
 		newNormalLayer,
 		o.options,
+		o.scope,
 	)
 }
 
@@ -221,21 +398,55 @@ func wrapEnvBuild(
 		}
 	}
 
-	envProvider, err := env.NewEntriesProvider(prefix)
+	formatter := newEnvKeyFormatterWithNaming(prefix, envNaming(options))
+
+	bound, err := envKeyTag(formatter).bind(to.model)
+	if err != nil {
+		return err
+	}
+
+	taggedKeys := make(map[string]string, len(bound))
+
+	for name, path := range bound {
+		taggedKeys[path] = name
+	}
+
+	envOptions, builderOptions := splitEnvOptions(options)
+	names, builderOptions := envTagAliases(bound, builderOptions)
+	envOptions = append(envOptions, env.WithNames(names))
+
+	envProvider, err := env.NewEntriesProvider(prefix, envOptions...)
 	if err != nil {
 		return fmt.Errorf("env builder: %w", err)
 	}
 
 	builder, err := newStringBasedBuilderWithFormatter(
 		envProvider,
-		newEnvKeyFormatter(prefix),
-		options...,
+		formatter,
+		builderOptions...,
 	)
 	if err != nil {
 		return err
 	}
 
-	to.addBuilder(wrap(builder))
+	builder.taggedKeys = taggedKeys
+
+	if builder.environ == nil {
+		builder.environ = envEnviron(options)
+	}
+
+	policy := wrap(builder)
+
+	// without prefix, variables that don't match the model are only
+	// reported by strict env layers.
+	builder.ignoreUnbounded = prefix == "" && !policy.isStrict()
+
+	// so are the generated variables of the fields named by an env tag.
+	if !policy.isStrict() {
+		builder.dropShadowed()
+	}
+
+	to.addBuilder(policy)
 
 	return nil
 }
@@ -249,7 +460,9 @@ func (o *StrictEnvLayer) register(to *layerBuilder) error {
 	)
 }
 
-// WithStrictEnvLayer creates a new strict environment layer.
+// WithStrictEnvLayer creates a new strict environment layer. Without
+// prefix, every variable whose name is a valid key must be bound to the
+// structure.
 func WithStrictEnvLayer(prefix string, options ...Option) *StrictEnvLayer {
 	return &StrictEnvLayer{
 		options: options,
@@ -266,7 +479,9 @@ func (o *EnvLayer) register(to *layerBuilder) error {
 	)
 }
 
-// WithEnvLayer creates an environment variable layer.
+// WithEnvLayer creates an environment variable layer. Without prefix,
+// every variable whose name is a valid key is read and the ones that are not
+// bound to the structure are ignored.
 func WithEnvLayer(prefix string, options ...Option) *EnvLayer {
 	return &EnvLayer{
 		options: options,
@@ -351,6 +566,396 @@ func WithStructLayer(input any, id string) *StructLayer {
 	}
 }
 
+// ///////////////////////////////////////////////////////////////////.
+
+func wrapFileBuild(
+	to *layerBuilder,
+	wrap func(FieldValuesGetter) constraintLayerPolicy,
+	path string,
+	options []Option,
+) error {
+	fileProvider, err := file.NewEntriesProvider(path)
+	if err != nil {
+		return fmt.Errorf("file builder: %w", err)
+	}
+
+	if idx := to.dedupId(
+		fmt.Sprintf("file(%s)", fileProvider.GetPath()),
+	); idx != nil {
+		return DuplicateFileError{
+			Index: *idx,
+			Path:  fileProvider.GetPath(),
+		}
+	}
+
+	formatter := newFileKeyFormatter(fileProvider.GetPath())
+
+	options, taggedKeys, err := keyTagAliases(
+		to.model,
+		fileKeyTag(),
+		formatter,
+		options,
+	)
+	if err != nil {
+		return err
+	}
+
+	builder, err := newStringBasedBuilderWithFormatter(
+		fileProvider,
+		formatter,
+		options...,
+	)
+	if err != nil {
+		return err
+	}
+
+	builder.taggedKeys = taggedKeys
+
+	policy := wrap(builder)
+
+	// keys that don't match the model are only reported by strict file
+	// layers.
+	builder.ignoreUnbounded = !policy.isStrict()
+
+	to.addBuilder(policy)
+
+	return nil
+}
+
+func (o *StrictFileLayer) register(to *layerBuilder) error {
+	return wrapFileBuild(
+		to,
+		newStrictLayer,
+		o.path,
+		o.options,
+	)
+}
+
+// WithStrictFileLayer creates a strict configuration file layer. The file
+// format (YAML, JSON or TOML) is deduced from the path extension. Keys that
+// are not bound to the structure are reported as UnboundedLocationError.
+func WithStrictFileLayer(path string, options ...Option) *StrictFileLayer {
+	return &StrictFileLayer{
+		path:    path,
+		options: options,
+	}
+}
+
+func (o *FileLayer) register(to *layerBuilder) error {
+	return wrapFileBuild(
+		to,
+		newNormalLayer,
+		o.path,
+		o.options,
+	)
+}
+
+// WithFileLayer creates a configuration file layer. The file format (YAML,
+// JSON or TOML) is deduced from the path extension. Keys that are not bound
+// to the structure are ignored.
+func WithFileLayer(path string, options ...Option) *FileLayer {
+	return &FileLayer{
+		path:    path,
+		options: options,
+	}
+}
+
+// ///////////////////////////////////////////////////////////////////.
+
+func wrapKFileBuild(
+	to *layerBuilder,
+	wrap func(FieldValuesGetter) constraintLayerPolicy,
+	dir string,
+	options []Option,
+) error {
+	cleanDir := filepath.Clean(dir)
+
+	if idx := to.dedupId(
+		fmt.Sprintf("kfile(%s)", cleanDir),
+	); idx != nil {
+		return DuplicateKFileError{
+			Index: *idx,
+			Dir:   cleanDir,
+		}
+	}
+
+	kfileOptions, builderOptions := splitKFileOptions(options)
+	kfileOptions = append(kfileOptions, kfile.WithContext(to.ctx))
+
+	kfileProvider, err := kfile.NewEntriesProvider(cleanDir, kfileOptions...)
+	if err != nil {
+		return fmt.Errorf("kfile builder: %w", err)
+	}
+
+	builder, err := newStringBasedBuilderWithFormatter(
+		kfileProvider,
+		newKFileKeyFormatter(cleanDir),
+		builderOptions...,
+	)
+	if err != nil {
+		return err
+	}
+
+	policy := wrap(builder)
+
+	// files that don't match the model are only reported by strict kfile
+	// layers.
+	builder.ignoreUnbounded = !policy.isStrict()
+
+	to.addBuilder(policy)
+
+	return nil
+}
+
+func (o *StrictKFileLayer) register(to *layerBuilder) error {
+	return wrapKFileBuild(
+		to,
+		newStrictLayer,
+		o.dir,
+		o.options,
+	)
+}
+
+// WithStrictKFileLayer creates a strict kfile layer reading one value per
+// file in the dir directory tree (i.e. Kubernetes secret or config map
+// mounts). Files that are not bound to the structure are reported as
+// UnboundedLocationError.
+func WithStrictKFileLayer(dir string, options ...Option) *StrictKFileLayer {
+	return &StrictKFileLayer{
+		dir:     dir,
+		options: options,
+	}
+}
+
+func (o *KFileLayer) register(to *layerBuilder) error {
+	return wrapKFileBuild(
+		to,
+		newNormalLayer,
+		o.dir,
+		o.options,
+	)
+}
+
+// WithKFileLayer creates a kfile layer reading one value per file in the dir
+// directory tree (i.e. Kubernetes secret or config map mounts). Files that
+// are not bound to the structure are ignored.
+func WithKFileLayer(dir string, options ...Option) *KFileLayer {
+	return &KFileLayer{
+		dir:     dir,
+		options: options,
+	}
+}
+
+func wrapHTTPBuild(
+	to *layerBuilder,
+	wrap func(FieldValuesGetter) constraintLayerPolicy,
+	fetcher *remote.Fetcher,
+	options []Option,
+) error {
+	httpProvider, err := fetcher.Fetch(to.ctx)
+	if err != nil {
+		return fmt.Errorf("http builder: %w", err)
+	}
+
+	if idx := to.dedupId(httpProvider.GetName()); idx != nil {
+		return DuplicateHTTPError{
+			Index: *idx,
+			URL:   httpProvider.GetURL(),
+		}
+	}
+
+	formatter := newHTTPKeyFormatter(httpProvider.GetURL())
+
+	options, taggedKeys, err := keyTagAliases(
+		to.model,
+		fileKeyTag(),
+		formatter,
+		options,
+	)
+	if err != nil {
+		return err
+	}
+
+	builder, err := newStringBasedBuilderWithFormatter(
+		httpProvider,
+		formatter,
+		options...,
+	)
+	if err != nil {
+		return err
+	}
+
+	builder.taggedKeys = taggedKeys
+	builder.stale = httpProvider.Stale()
+
+	policy := wrap(builder)
+
+	// keys that don't match the model are only reported by strict http
+	// layers.
+	builder.ignoreUnbounded = !policy.isStrict()
+
+	to.addBuilder(policy)
+
+	return nil
+}
+
+func (o *StrictHTTPLayer) register(to *layerBuilder) error {
+	return wrapHTTPBuild(
+		to,
+		newStrictLayer,
+		o.fetcher,
+		o.options,
+	)
+}
+
+// WithStrictHTTPLayer creates a strict layer fetching a JSON, YAML or TOML
+// configuration document over HTTP. Keys that are not bound to the
+// structure are reported as UnboundedLocationError.
+func WithStrictHTTPLayer(url string, options ...Option) *StrictHTTPLayer {
+	httpOptions, builderOptions := splitHTTPOptions(options)
+
+	return &StrictHTTPLayer{
+		fetcher: remote.NewFetcher(url, httpOptions...),
+		options: builderOptions,
+	}
+}
+
+func (o *HTTPLayer) register(to *layerBuilder) error {
+	return wrapHTTPBuild(
+		to,
+		newNormalLayer,
+		o.fetcher,
+		o.options,
+	)
+}
+
+// Stale returns the fetch error when the layer values of the last fill come
+// from its cache file, the fetch having failed, nil otherwise.
+func (o *StrictHTTPLayer) Stale() error {
+	return o.fetcher.Stale()
+}
+
+// Stale returns the fetch error when the layer values of the last fill come
+// from its cache file, the fetch having failed, nil otherwise. The fill
+// succeeds with the cached values, so this is the way to tell them stale.
+func (o *HTTPLayer) Stale() error {
+	return o.fetcher.Stale()
+}
+
+// WithHTTPLayer creates a layer fetching a JSON, YAML or TOML configuration
+// document over HTTP, the format being given by the response content type.
+// The layer keeps the fetched document, so the next fills of the layer send
+// conditional requests. Keys that are not bound to the structure are
+// ignored.
+func WithHTTPLayer(url string, options ...Option) *HTTPLayer {
+	httpOptions, builderOptions := splitHTTPOptions(options)
+
+	return &HTTPLayer{
+		fetcher: remote.NewFetcher(url, httpOptions...),
+		options: builderOptions,
+	}
+}
+
+// ///////////////////////////////////////////////////////////////////.
+
+func wrapSecretBuild(
+	to *layerBuilder,
+	wrap func(FieldValuesGetter) constraintLayerPolicy,
+	layer *SecretLayer,
+) error {
+	if idx := to.dedupId(fmt.Sprintf("secret(%s)", layer.id)); idx != nil {
+		return DuplicateSecretIDError{
+			Index: *idx,
+			ID:    layer.id,
+		}
+	}
+
+	optionRefs, builderOptions := splitSecretOptions(layer.options)
+
+	refs, err := secretRefs(to.model, optionRefs)
+	if err != nil {
+		return err
+	}
+
+	secretProvider, refreshAt, err := readSecrets(
+		to.ctx,
+		layer.client,
+		layer.id,
+		refs,
+	)
+	if err != nil {
+		layer.lease.fail(time.Now())
+
+		return fmt.Errorf("secret builder: %w", err)
+	}
+
+	builder, err := newStringBasedBuilderWithFormatter(
+		secretProvider,
+		newSecretKeyFormatter(layer.id),
+		builderOptions...,
+	)
+	if err != nil {
+		return err
+	}
+
+	builder.taggedKeys = refs
+	builder.secret = true
+
+	layer.lease.set(refreshAt)
+
+	to.addBuilder(wrap(builder))
+
+	return nil
+}
+
+// RefreshAt returns the time the lease of the first secret read by the last
+// fill expires, zero when no secret is leased. Watch reloads the
+// configuration then. When the read of an expired lease fails, it is the
+// time of the next attempt, backing off from one second up to one minute.
+func (o *SecretLayer) RefreshAt() time.Time {
+	return o.lease.get()
+}
+
+func (o *StrictSecretLayer) register(to *layerBuilder) error {
+	return wrapSecretBuild(to, newStrictLayer, &o.SecretLayer)
+}
+
+// WithStrictSecretLayer creates a strict layer reading the fields mapped to
+// a secret, by a secret struct tag or WithSecretPaths, from a secret store.
+// The layer values are always redacted.
+func WithStrictSecretLayer(
+	client SecretClient,
+	id string,
+	options ...Option,
+) *StrictSecretLayer {
+	return &StrictSecretLayer{
+		SecretLayer: *WithSecretLayer(client, id, options...),
+	}
+}
+
+func (o *SecretLayer) register(to *layerBuilder) error {
+	return wrapSecretBuild(to, newNormalLayer, o)
+}
+
+// WithSecretLayer creates a layer reading the fields mapped to a secret, by
+// a secret:"path#field" struct tag or WithSecretPaths, from a secret store.
+// Every secret is read in a single batch, and the layer values are always
+// redacted.
+func WithSecretLayer(
+	client SecretClient,
+	id string,
+	options ...Option,
+) *SecretLayer {
+	return &SecretLayer{
+		client:  client,
+		lease:   &secretLease{},
+		id:      id,
+		options: options,
+	}
+}
+
+// ///////////////////////////////////////////////////////////////////.
+
 type StringProviderLayer struct {
 	provider NamedStringValuesProvider
 	options  []Option
@@ -441,3 +1046,71 @@ func WithStrictStringValueProvider(
 		},
 	}
 }
+
+// ContextProviderLayer is a layer of a ContextProvider.
+type ContextProviderLayer struct {
+	provider ContextProvider
+	options  []Option
+	strict   bool
+}
+
+func (c *ContextProviderLayer) register(to *layerBuilder) error {
+	values, err := c.provider.GetStringValuesContext(to.ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", c.provider.GetName(), err)
+	}
+
+	wrap := newNormalLayer
+	if c.strict {
+		wrap = newStrictLayer
+	}
+
+	return wrapStringProviderBuild(
+		to,
+		wrap,
+		&namedValues{
+			values: values,
+			name:   c.provider.GetName(),
+		},
+		c.options,
+	)
+}
+
+// WithContextProvider creates a layer of a provider reading its values with
+// the fill context.
+func WithContextProvider(
+	provider ContextProvider,
+	options ...Option,
+) *ContextProviderLayer {
+	return &ContextProviderLayer{
+		provider: provider,
+		options:  options,
+	}
+}
+
+// WithStrictContextProvider creates a strict layer of a provider reading
+// its values with the fill context.
+func WithStrictContextProvider(
+	provider ContextProvider,
+	options ...Option,
+) *ContextProviderLayer {
+	return &ContextProviderLayer{
+		provider: provider,
+		options:  options,
+		strict:   true,
+	}
+}
+
+// namedValues is a named provider of values read once.
+type namedValues struct {
+	values svalue.Values
+	name   string
+}
+
+func (p *namedValues) GetName() string {
+	return p.name
+}
+
+func (p *namedValues) GetStringValues() svalue.Values {
+	return p.values
+}
@@ -0,0 +1,162 @@
+package dsco
+
+import (
+	"errors"
+	"os"
+	"reflect"
+
+	"github.com/byte4ever/dsco/internal/cmdline"
+	"github.com/byte4ever/dsco/internal/fvalue"
+)
+
+// ErrCmdlineOnlyOption represents an error where a command line option is
+// used by a layer that is not a command line layer.
+var ErrCmdlineOnlyOption = errors.New(
+	"option is only supported by cmdline layers",
+)
+
+// CmdlineOption is a processing option only supported by command line
+// layers.
+type CmdlineOption interface {
+	Option
+	cmdlineOption() cmdline.Option
+}
+
+type cmdlineOption cmdline.Option
+
+func (cmdlineOption) apply(*internalOpts) error {
+	return ErrCmdlineOnlyOption
+}
+
+func (o cmdlineOption) cmdlineOption() cmdline.Option {
+	return cmdline.Option(o)
+}
+
+// WithHelp makes the command line layer accept --help and -h: Fill then
+// prints the usage text generated from the model and the layers to the
+// standard output and exits.
+func WithHelp() CmdlineOption {
+	return cmdlineOption(cmdline.WithHelp())
+}
+
+// WithPositionalArgs stores in dst the command line arguments that are not
+// options, i.e. file names or the arguments following "--".
+func WithPositionalArgs(dst *[]string) CmdlineOption {
+	return cmdlineOption(cmdline.WithPositionals(dst))
+}
+
+// argsOption is the WithArgs option. It is not a CmdlineOption as it
+// replaces the arguments the command line provider parses.
+type argsOption []string
+
+func (argsOption) apply(*internalOpts) error {
+	return ErrCmdlineOnlyOption
+}
+
+// WithArgs makes the command line layer parse args, without the program
+// name, instead of the arguments of the process.
+func WithArgs(args ...string) Option {
+	return argsOption(args)
+}
+
+// cmdlineArgs returns the arguments set by the last WithArgs option, or the
+// arguments of the process, and the other options.
+func cmdlineArgs(options []Option) ([]string, []Option) {
+	args := os.Args[1:]
+	others := make([]Option, 0, len(options))
+
+	for _, option := range options {
+		if ao, ok := option.(argsOption); ok {
+			args = ao
+			continue
+		}
+
+		others = append(others, option)
+	}
+
+	return args, others
+}
+
+// keyKindRecorder implements internal.ValueGetter to capture the keys of
+// the fields of the model, true for bool fields. No values are produced.
+type keyKindRecorder struct {
+	keys map[string]bool
+}
+
+// Get records the key of the field and returns (nil, nil) so the model
+// treats the field as unfilled.
+func (r *keyKindRecorder) Get(
+	path string,
+	fieldType reflect.Type,
+) (*fvalue.Value, error) {
+	if fieldType.Kind() == reflect.Pointer {
+		fieldType = fieldType.Elem()
+	}
+
+	r.keys[convert(path)] = fieldType.Kind() == reflect.Bool
+
+	return nil, nil //nolint:nilnil // matches StringBasedBuilder.Get when nothing is found
+}
+
+// cmdlineKeyKinds returns the provider options telling which command line
+// keys, or their aliases, are bool fields of the model, and which ones are
+// other fields, requiring a value. The model is nil when unknown, then no
+// key is a bool one nor requires a value.
+func cmdlineKeyKinds(model ModelInterface, options []Option) []cmdline.Option {
+	rec := &keyKindRecorder{keys: make(map[string]bool)}
+
+	if model != nil {
+		_, _ = model.ApplyOn(rec) //nolint:errcheck // recorder never errors
+	}
+
+	// invalid options are reported when building the layer
+	var opts internalOpts
+	_ = opts.applyOptions(options)
+
+	kind := func(key string) (bool, bool) {
+		if target, found := opts.aliases[key]; found {
+			key = target
+		}
+
+		isBool, found := rec.keys[key]
+
+		return isBool, found
+	}
+
+	return []cmdline.Option{
+		cmdline.WithBoolKeys(
+			func(key string) bool {
+				isBool, _ := kind(key)
+
+				return isBool
+			},
+		),
+		cmdline.WithValueKeys(
+			func(key string) bool {
+				isBool, found := kind(key)
+
+				return found && !isBool
+			},
+		),
+	}
+}
+
+// splitCmdlineOptions separates command line provider options from string
+// based builder options.
+func splitCmdlineOptions(options []Option) ([]cmdline.Option, []Option) {
+	var (
+		cmdlineOptions []cmdline.Option
+		builderOptions []Option
+	)
+
+	for _, option := range options {
+		if co, ok := option.(CmdlineOption); ok {
+			cmdlineOptions = append(cmdlineOptions, co.cmdlineOption())
+			continue
+		}
+
+		builderOptions = append(builderOptions, option)
+	}
+
+	return cmdlineOptions, builderOptions
+}
@@ -0,0 +1,198 @@
+package dsco
+
+import (
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/byte4ever/dsco/internal/cmdline"
+)
+
+func TestCmdlineOption_apply(t *testing.T) {
+	t.Parallel()
+
+	var positionals []string
+
+	for _, option := range []CmdlineOption{
+		WithHelp(),
+		WithPositionalArgs(&positionals),
+	} {
+		require.ErrorIs(
+			t,
+			option.apply(&internalOpts{}),
+			ErrCmdlineOnlyOption,
+		)
+		require.NotNil(t, option.cmdlineOption())
+	}
+}
+
+func Test_splitCmdlineOptions(t *testing.T) {
+	t.Parallel()
+
+	aliases := WithAliases(map[string]string{"a": "b"})
+
+	cmdlineOptions, builderOptions := splitCmdlineOptions(
+		[]Option{WithHelp(), aliases},
+	)
+
+	require.Len(t, cmdlineOptions, 1)
+	require.Equal(t, []Option{aliases}, builderOptions)
+}
+
+//nolint:paralleltest // uses os.Args
+func TestFill_posixCmdline(t *testing.T) {
+	type Server struct {
+		Port *int
+		TLS  *bool
+	}
+
+	type Root struct {
+		Server  *Server
+		Verbose *bool
+		Debug   *bool
+		Name    *string
+	}
+
+	os.Args = []string{
+		"app",
+		"--server-port", "8080",
+		"-v",
+		"--no-server-tls",
+		"--debug", "input.txt",
+		"-n", "api",
+		"--",
+		"--name=ignored",
+	}
+
+	var (
+		pp          *Root
+		positionals []string
+	)
+
+	locations, err := Fill(
+		&pp,
+		WithStrictCmdlineLayer(
+			WithPositionalArgs(&positionals),
+			WithAliases(map[string]string{"v": "verbose", "n": "name"}),
+		),
+	)
+	require.NoError(t, err)
+
+	require.Equal(t, 8080, *pp.Server.Port)
+	require.False(t, *pp.Server.TLS)
+	require.True(t, *pp.Verbose)
+	require.True(t, *pp.Debug)
+	require.Equal(t, "api", *pp.Name)
+	require.Equal(t, []string{"input.txt", "--name=ignored"}, positionals)
+	require.Equal(
+		t,
+		"cmdline[--no-server-tls]",
+		locationOf(t, locations, "Server.TLS").Location,
+	)
+	require.Equal(t, "cmdline[-v]", locationOf(t, locations, "Verbose").Location)
+}
+
+func TestFill_cmdlineErrors(t *testing.T) {
+	t.Parallel()
+
+	type Root struct {
+		Port    *int
+		Verbose *bool
+	}
+
+	fill := func(layer Layer) error {
+		var cfg *Root
+
+		_, err := Fill(&cfg, layer)
+
+		return err
+	}
+
+	// a non-bool key requires a value
+	err := fill(WithCmdlineLayer(WithArgs("--verbose", "--port")))
+	require.ErrorContains(
+		t,
+		err,
+		`arg "--port": value missing: `+cmdline.ErrInvalidFormat.Error(),
+	)
+
+	// positionals must be collected
+	err = fill(WithCmdlineLayer(WithArgs("--port=1", "input.txt")))
+	require.ErrorContains(
+		t,
+		err,
+		`arg "input.txt": `+cmdline.ErrUnexpectedPositional.Error(),
+	)
+
+	var positionals []string
+
+	require.NoError(
+		t,
+		fill(
+			WithCmdlineLayer(
+				WithArgs("--port=1", "--verbose", "input.txt"),
+				WithPositionalArgs(&positionals),
+			),
+		),
+	)
+	require.Equal(t, []string{"input.txt"}, positionals)
+}
+
+func Test_cmdlineArgs(t *testing.T) {
+	t.Parallel()
+
+	aliases := WithAliases(map[string]string{"a": "b"})
+
+	args, others := cmdlineArgs(
+		[]Option{WithArgs("--a=1"), aliases, WithArgs("--b=2", "-c")},
+	)
+
+	require.Equal(t, []string{"--b=2", "-c"}, args)
+	require.Equal(t, []Option{aliases}, others)
+
+	args, others = cmdlineArgs([]Option{aliases})
+	require.Equal(t, os.Args[1:], args)
+	require.Equal(t, []Option{aliases}, others)
+}
+
+func TestFill_withArgs(t *testing.T) {
+	t.Parallel()
+
+	type Root struct {
+		Port    *int
+		Verbose *bool
+	}
+
+	for _, port := range []int{8080, 9090} {
+		port := port
+
+		t.Run(
+			strconv.Itoa(port), func(t *testing.T) {
+				t.Parallel()
+
+				var cfg *Root
+
+				locations, err := Fill(
+					&cfg,
+					WithCmdlineLayer(
+						WithArgs("--port", strconv.Itoa(port), "--verbose"),
+					),
+				)
+				require.NoError(t, err)
+				require.Equal(t, port, *cfg.Port)
+				require.True(t, *cfg.Verbose)
+				require.Equal(
+					t,
+					"cmdline[--port]",
+					locationOf(t, locations, "Port").Location,
+				)
+			},
+		)
+	}
+
+	err := WithEnvLayer("API", WithArgs("--port=1")).
+		register(newLayerBuilder(1))
+	require.ErrorIs(t, err, ErrCmdlineOnlyOption)
+}
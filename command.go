@@ -0,0 +1,266 @@
+package dsco
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/byte4ever/dsco/internal/cmdline"
+	"github.com/byte4ever/dsco/internal/plocation"
+)
+
+// ErrNoCommand represents an error where the command line selects no
+// command.
+var ErrNoCommand = errors.New("no command")
+
+// ErrDispatchOption represents an error where an option other than
+// WithArgs is given to Dispatch.
+var ErrDispatchOption = errors.New("option is not supported by Dispatch")
+
+// ErrUnknownCommand represents an error where the command line selects a
+// command that is not defined.
+var ErrUnknownCommand = errors.New("unknown command")
+
+// UnknownCommandError represents an error where the command line selects
+// the undefined command Name.
+type UnknownCommandError struct {
+	Name string
+}
+
+func (a UnknownCommandError) Error() string {
+	return fmt.Sprintf("unknown command %q", a.Name)
+}
+
+func (UnknownCommandError) Is(err error) bool {
+	return errors.Is(err, ErrUnknownCommand)
+}
+
+// Command is a subcommand of Dispatch, with its own configuration and
+// layers.
+type Command struct {
+	// Config is the configuration of the command, a **T like for Fill, or
+	// nil when the command has none.
+	Config any
+
+	// Name selects the command on the command line.
+	Name string
+
+	// Description is shown in the usage text of the parent.
+	Description string
+
+	// Layers fill Config. Their command line layer only reads the
+	// arguments following the command name.
+	Layers []Layer
+}
+
+// Selection is the command selected by Dispatch.
+type Selection struct {
+	Command *Command
+
+	// ParentLocations is the fill report of the parent configuration.
+	ParentLocations plocation.Locations
+
+	// Locations is the fill report of the command configuration.
+	Locations plocation.Locations
+}
+
+// cmdlineScope restricts a command line layer to the arguments of a
+// command.
+type cmdlineScope struct {
+	// rest receives the first positional argument, the command name, and
+	// the following ones. Parsing stops there when set.
+	rest *[]string
+
+	program string
+	// args replaces the arguments of the layer, when not nil.
+	args     []string
+	commands []*Command
+}
+
+// options returns the command line provider options of the scope.
+func (s *cmdlineScope) options() []cmdline.Option {
+	if s.rest == nil {
+		return nil
+	}
+
+	return []cmdline.Option{
+		cmdline.WithStopAtPositional(),
+		cmdline.WithPositionals(s.rest),
+	}
+}
+
+// scopeLayers returns layers with their command line layer restricted to
+// scope, and whether there is one.
+func scopeLayers(layers []Layer, scope *cmdlineScope) ([]Layer, bool) {
+	scoped := make([]Layer, len(layers))
+	found := false
+
+	for i, layer := range layers {
+		switch l := layer.(type) {
+		case *CmdlineLayer:
+			scoped[i] = &CmdlineLayer{options: l.options, scope: scope}
+			found = true
+		case *StrictCmdlineLayer:
+			scoped[i] = &StrictCmdlineLayer{options: l.options, scope: scope}
+			found = true
+		default:
+			scoped[i] = layer
+		}
+	}
+
+	return scoped, found
+}
+
+// DispatchInventoryLayers returns layers, the parent layers of commands,
+// with their command line layer scoped like Dispatch does and reading no
+// argument, for the inventory of the parent configuration:
+//
+//	report, err := inventory.Compute(
+//		&global,
+//		dsco.DispatchInventoryLayers(layers, commands...)...,
+//	)
+func DispatchInventoryLayers(layers []Layer, commands ...*Command) []Layer {
+	var rest []string
+
+	scoped, _ := scopeLayers(
+		layers,
+		&cmdlineScope{
+			rest:     &rest,
+			program:  programName(),
+			args:     []string{},
+			commands: commands,
+		},
+	)
+
+	return scoped
+}
+
+// InventoryLayers returns the layers of the command with their command line
+// layer scoped to the command like Dispatch does and reading no argument,
+// for the inventory of the command configuration:
+//
+//	report, err := inventory.Compute(serve.Config, serve.InventoryLayers()...)
+func (c *Command) InventoryLayers() []Layer {
+	scoped, _ := scopeLayers(
+		c.Layers,
+		&cmdlineScope{
+			program: programName() + " " + c.Name,
+			args:    []string{},
+		},
+	)
+
+	return scoped
+}
+
+// Dispatch selects one of the commands from the command line, like
+// "app --verbose serve --port=8080".
+//
+// The options preceding the command name fill config, a **T shared by all
+// the commands (it can be nil), with layers. The command configuration is
+// then filled with the command layers, whose command line layer only reads
+// the arguments following the command name. With WithHelp, --help shows the
+// parent usage with the list of commands before the command name, and the
+// command usage after it.
+//
+// The arguments are the ones of the process, or the ones set by the
+// WithArgs option, which replace the arguments of the parent command line
+// layer and are read even when the parent has none. Other options fail
+// with ErrDispatchOption.
+func Dispatch(
+	config any,
+	layers []Layer,
+	commands []*Command,
+	options ...Option,
+) (*Selection, error) {
+	args, err := dispatchArgs(options)
+	if err != nil {
+		return nil, err
+	}
+
+	program := programName()
+
+	var rest []string
+
+	parentLayers, found := scopeLayers(
+		layers,
+		&cmdlineScope{
+			rest:     &rest,
+			program:  program,
+			args:     args,
+			commands: commands,
+		},
+	)
+	if !found {
+		rest = args
+		if rest == nil {
+			rest = os.Args[1:]
+		}
+	}
+
+	if config == nil {
+		var none *struct{}
+
+		config = &none
+	}
+
+	selection := &Selection{}
+
+	locations, err := Fill(config, parentLayers...)
+	if err != nil {
+		return nil, err
+	}
+
+	selection.ParentLocations = locations
+
+	if len(rest) == 0 {
+		return nil, ErrNoCommand
+	}
+
+	for _, command := range commands {
+		if command.Name == rest[0] {
+			selection.Command = command
+			break
+		}
+	}
+
+	command := selection.Command
+	if command == nil {
+		return nil, UnknownCommandError{Name: rest[0]}
+	}
+
+	if command.Config == nil {
+		return selection, nil
+	}
+
+	commandLayers, _ := scopeLayers(
+		command.Layers,
+		&cmdlineScope{
+			program: program + " " + command.Name,
+			args:    rest[1:],
+		},
+	)
+
+	selection.Locations, err = Fill(command.Config, commandLayers...)
+	if err != nil {
+		return nil, fmt.Errorf("command %s: %w", command.Name, err)
+	}
+
+	return selection, nil
+}
+
+// dispatchArgs returns the arguments set by the last WithArgs option, nil
+// when there is none.
+func dispatchArgs(options []Option) ([]string, error) {
+	var args []string
+
+	for _, option := range options {
+		ao, ok := option.(argsOption)
+		if !ok {
+			return nil, ErrDispatchOption
+		}
+
+		args = append([]string{}, ao...)
+	}
+
+	return args, nil
+}
@@ -0,0 +1,319 @@
+package dsco
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type commandGlobal struct {
+	Verbose *bool `dsco:"optional" desc:"Verbose output"`
+}
+
+type commandServe struct {
+	Port *int `desc:"Listening port"`
+	Host *string
+}
+
+type commandMigrate struct {
+	Steps *int
+}
+
+func commandTree(
+	serve **commandServe,
+	migrate **commandMigrate,
+) ([]Layer, []*Command) {
+	layers := []Layer{
+		WithCmdlineLayer(WithHelp()),
+	}
+
+	return layers, []*Command{
+		{
+			Name:        "serve",
+			Description: "Start the server",
+			Config:      serve,
+			Layers: []Layer{
+				WithCmdlineLayer(WithHelp()),
+				WithStructLayer(
+					&commandServe{
+						Port: R(8080),
+						Host: R("localhost"),
+					},
+					"defaults",
+				),
+			},
+		},
+		{
+			Name:        "migrate",
+			Description: "Migrate the database",
+			Config:      migrate,
+			Layers: []Layer{
+				WithCmdlineLayer(),
+			},
+		},
+		{
+			Name: "version",
+		},
+	}
+}
+
+//nolint:paralleltest // uses os.Args
+func TestDispatch(t *testing.T) {
+	var (
+		global  *commandGlobal
+		serve   *commandServe
+		migrate *commandMigrate
+	)
+
+	layers, commands := commandTree(&serve, &migrate)
+
+	os.Args = []string{"api", "--verbose", "serve", "--port", "9000"}
+
+	selection, err := Dispatch(&global, layers, commands)
+	require.NoError(t, err)
+	require.Same(t, commands[0], selection.Command)
+	require.True(t, *global.Verbose)
+	require.Equal(t, 9000, *serve.Port)
+	require.Equal(t, "localhost", *serve.Host)
+	require.Nil(t, migrate)
+
+	require.Equal(
+		t,
+		"cmdline[--verbose]",
+		locationOf(t, selection.ParentLocations, "Verbose").Location,
+	)
+	require.Equal(
+		t,
+		"cmdline[--port]",
+		locationOf(t, selection.Locations, "Port").Location,
+	)
+
+	global, serve = nil, nil
+	os.Args = []string{"api", "migrate", "--steps=3"}
+
+	selection, err = Dispatch(&global, layers, commands)
+	require.NoError(t, err)
+	require.Same(t, commands[1], selection.Command)
+	require.Nil(t, global.Verbose)
+	require.Equal(t, 3, *migrate.Steps)
+	require.Nil(t, serve)
+
+	os.Args = []string{"api", "version"}
+
+	selection, err = Dispatch(nil, layers, commands)
+	require.NoError(t, err)
+	require.Same(t, commands[2], selection.Command)
+	require.Nil(t, selection.Locations)
+}
+
+//nolint:paralleltest // uses os.Args
+func TestDispatch_errors(t *testing.T) {
+	var (
+		global  *commandGlobal
+		serve   *commandServe
+		migrate *commandMigrate
+	)
+
+	layers, commands := commandTree(&serve, &migrate)
+
+	os.Args = []string{"api", "--verbose"}
+
+	_, err := Dispatch(&global, layers, commands)
+	require.ErrorIs(t, err, ErrNoCommand)
+
+	os.Args = []string{"api", "deploy", "--port=1"}
+
+	_, err = Dispatch(&global, layers, commands)
+
+	var unknownErr UnknownCommandError
+
+	require.ErrorAs(t, err, &unknownErr)
+	require.Equal(t, "deploy", unknownErr.Name)
+	require.ErrorIs(t, err, ErrUnknownCommand)
+
+	// parent options are not accepted after the command name
+	os.Args = []string{"api", "serve", "--verbose"}
+
+	_, err = Dispatch(&global, layers, commands)
+	require.ErrorContains(t, err, "command serve:")
+	require.ErrorContains(t, err, "unbounded location cmdline[--verbose]")
+
+	os.Args = []string{"api", "--port=1", "serve"}
+
+	_, err = Dispatch(&global, layers, commands)
+	require.ErrorContains(t, err, "unbounded location cmdline[--port]")
+}
+
+//nolint:paralleltest // uses os.Args
+func TestDispatch_withoutCmdlineLayer(t *testing.T) {
+	var (
+		global  *commandGlobal
+		serve   *commandServe
+		migrate *commandMigrate
+	)
+
+	_, commands := commandTree(&serve, &migrate)
+
+	os.Args = []string{"api", "migrate", "--steps=2"}
+
+	selection, err := Dispatch(&global, nil, commands)
+	require.NoError(t, err)
+	require.Same(t, commands[1], selection.Command)
+	require.Equal(t, 2, *migrate.Steps)
+}
+
+func TestDispatch_withArgs(t *testing.T) {
+	t.Parallel()
+
+	var (
+		global  *commandGlobal
+		serve   *commandServe
+		migrate *commandMigrate
+	)
+
+	layers, commands := commandTree(&serve, &migrate)
+
+	selection, err := Dispatch(
+		&global,
+		layers,
+		commands,
+		WithArgs("--verbose", "serve", "--port=9000"),
+	)
+	require.NoError(t, err)
+	require.Same(t, commands[0], selection.Command)
+	require.True(t, *global.Verbose)
+	require.Equal(t, 9000, *serve.Port)
+
+	global = nil
+
+	selection, err = Dispatch(
+		&global,
+		nil,
+		commands,
+		WithArgs("migrate", "--steps=2"),
+	)
+	require.NoError(t, err)
+	require.Same(t, commands[1], selection.Command)
+	require.Equal(t, 2, *migrate.Steps)
+
+	_, err = Dispatch(&global, layers, commands, WithHelp())
+	require.ErrorIs(t, err, ErrDispatchOption)
+}
+
+//nolint:paralleltest // uses os.Args
+func TestCommand_InventoryLayers(t *testing.T) {
+	var (
+		global  *commandGlobal
+		serve   *commandServe
+		migrate *commandMigrate
+	)
+
+	layers, commands := commandTree(&serve, &migrate)
+
+	// the arguments are not read, even when they hold another command
+	os.Args = []string{"api", "--verbose", "migrate", "--steps=3"}
+
+	report := func(cfg any, layers []Layer) []FieldProvision {
+		t.Helper()
+
+		walk, err := PrepareInventoryWalk(cfg, layers...)
+		require.NoError(t, err)
+
+		inv, err := walk.Reporters[0].ReportInventory(walk.Model)
+		require.NoError(t, err)
+		require.Equal(t, "cmdline", inv.Name)
+
+		return inv.Provides
+	}
+
+	require.ElementsMatch(
+		t,
+		[]FieldProvision{
+			{FieldUID: "Port", Key: "--port="},
+			{FieldUID: "Host", Key: "--host="},
+		},
+		report(serve, commands[0].InventoryLayers()),
+	)
+	require.Equal(
+		t,
+		[]FieldProvision{{FieldUID: "Steps", Key: "--steps="}},
+		report(migrate, commands[1].InventoryLayers()),
+	)
+	require.Equal(
+		t,
+		[]FieldProvision{{FieldUID: "Verbose", Key: "--verbose="}},
+		report(global, DispatchInventoryLayers(layers, commands...)),
+	)
+	require.Empty(t, commands[2].InventoryLayers())
+
+	// the layers given to Dispatch are left unscoped
+	require.Nil(t, layers[0].(*CmdlineLayer).scope)
+}
+
+//nolint:paralleltest // replaces os.Args and the help output
+func TestDispatch_help(t *testing.T) {
+	var (
+		buf      bytes.Buffer
+		exitCode = -1
+	)
+
+	helpOutput, helpExit = &buf, func(code int) { exitCode = code }
+
+	t.Cleanup(
+		func() {
+			helpOutput, helpExit = os.Stdout, os.Exit
+		},
+	)
+
+	var (
+		global  *commandGlobal
+		serve   *commandServe
+		migrate *commandMigrate
+	)
+
+	layers, commands := commandTree(&serve, &migrate)
+
+	os.Args = []string{"api", "--help"}
+
+	_, _ = Dispatch(&global, layers, commands)
+
+	require.Equal(t, 0, exitCode)
+	require.Equal(
+		t,
+		`Usage of api:
+  --verbose=<bool>
+        Verbose output (optional)
+  -h, --help
+        Show this help and exit
+
+Commands:
+  serve     Start the server
+  migrate   Migrate the database
+  version
+`,
+		buf.String(),
+	)
+
+	buf.Reset()
+
+	exitCode = -1
+	os.Args = []string{"api", "serve", "-h"}
+
+	_, _ = Dispatch(&global, layers, commands)
+
+	require.Equal(t, 0, exitCode)
+	require.Equal(
+		t,
+		`Usage of api serve:
+  --host=<string>
+        (default: localhost)
+  --port=<int>
+        Listening port (default: 8080)
+  -h, --help
+        Show this help and exit
+`,
+		buf.String(),
+	)
+}
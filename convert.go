@@ -9,19 +9,65 @@ import (
 // convert transforms a dot-separated field path into a dash-separated
 // snake_case string suitable for environment variable or command line
 // argument naming. Each path segment is converted to snake_case and
-// segments are joined with dashes.
+// segments are joined with dashes. Map entry keys (i.e. "[eu]") are kept
+// verbatim.
 //
-// Example: "field.subField" becomes "field-sub_field"
+// Example: "field.subField" becomes "field-sub_field" and
+// "backends[eu].url" becomes "backends-eu-url".
 func convert(s string) string {
 	var sb strings.Builder
 
-	for i, s2 := range strings.Split(s, ".") {
+	for i, s2 := range splitPath(s) {
 		if i != 0 {
 			sb.WriteRune('-')
 		}
 
+		if key, found := strings.CutPrefix(s2, "["); found {
+			sb.WriteString(strings.TrimSuffix(key, "]"))
+			continue
+		}
+
 		sb.WriteString(utils.ToSnakeCase(s2))
 	}
 
 	return sb.String()
 }
+
+// splitPath splits a visible path into field names and bracketed entry
+// keys, dots inside entry keys being preserved.
+func splitPath(s string) []string {
+	var (
+		segments []string
+		start    int
+	)
+
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '.':
+			if i > start || i == 0 || s[i-1] != ']' {
+				segments = append(segments, s[start:i])
+			}
+
+			start = i + 1
+		case '[':
+			if i > start {
+				segments = append(segments, s[start:i])
+			}
+
+			end := strings.IndexByte(s[i:], ']')
+			if end < 0 {
+				end = len(s) - i - 1
+			}
+
+			segments = append(segments, s[i:i+end+1])
+			i += end
+			start = i + 1
+		}
+	}
+
+	if start < len(s) || len(segments) == 0 || s[len(s)-1] == '.' {
+		segments = append(segments, s[start:])
+	}
+
+	return segments
+}
@@ -46,6 +46,27 @@ func Test_convert(t *testing.T) {
 			},
 			want: "hello_world1-hello_world2",
 		},
+		{
+			name: "map entry",
+			args: args{
+				s: "Backends[eu].MaxConns",
+			},
+			want: "backends-eu-max_conns",
+		},
+		{
+			name: "nested map entries",
+			args: args{
+				s: "Zones[eu][app.kubernetes.io/name]",
+			},
+			want: "zones-eu-app.kubernetes.io/name",
+		},
+		{
+			name: "empty",
+			args: args{
+				s: "",
+			},
+			want: "",
+		},
 	}
 
 	for _, tt := range tests {
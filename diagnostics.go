@@ -0,0 +1,377 @@
+package dsco
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/byte4ever/dsco/internal/ierror"
+	"github.com/byte4ever/dsco/internal/model"
+)
+
+// DiagnosticKind is the category of a Diagnostic.
+type DiagnosticKind string
+
+const (
+	// DiagnosticInput is an invalid Fill target or command.
+	DiagnosticInput DiagnosticKind = "input"
+
+	// DiagnosticModel is an invalid configuration struct (type or tag).
+	DiagnosticModel DiagnosticKind = "model"
+
+	// DiagnosticLayer is a layer that can't be set up or read.
+	DiagnosticLayer DiagnosticKind = "layer"
+
+	// DiagnosticParse is a value that can't be parsed as its field type.
+	DiagnosticParse DiagnosticKind = "parse"
+
+	// DiagnosticInterpolation is a reference that can't be expanded.
+	DiagnosticInterpolation DiagnosticKind = "interpolation"
+
+	// DiagnosticUnboundKey is a strict layer key bound to no field.
+	DiagnosticUnboundKey DiagnosticKind = "unbound_key"
+
+	// DiagnosticUninitializedKey is a field no layer provides.
+	DiagnosticUninitializedKey DiagnosticKind = "uninitialized_key"
+
+	// DiagnosticOverriddenKey is a strict layer value overridden by another
+	// layer.
+	DiagnosticOverriddenKey DiagnosticKind = "overridden_key"
+
+	// DiagnosticValidation is a tag rule or Validate method failure.
+	DiagnosticValidation DiagnosticKind = "validation"
+
+	// DiagnosticOther is any other error.
+	DiagnosticOther DiagnosticKind = "other"
+)
+
+// DiagnosticCode is the stable code of a Diagnostic, safe to match on in
+// tooling. Codes are never reused for another error.
+type DiagnosticCode string
+
+const (
+	CodeUnknown            DiagnosticCode = "DSCO000"
+	CodeInvalidInput       DiagnosticCode = "DSCO001"
+	CodeUnknownCommand     DiagnosticCode = "DSCO002"
+	CodeInvalidModel       DiagnosticCode = "DSCO100"
+	CodeInvalidTag         DiagnosticCode = "DSCO101"
+	CodeUnsupportedType    DiagnosticCode = "DSCO102"
+	CodeFieldCollision     DiagnosticCode = "DSCO103"
+	CodeKeyTagCollision    DiagnosticCode = "DSCO104"
+	CodeInvalidLayer       DiagnosticCode = "DSCO200"
+	CodeCmdlineAlreadyUsed DiagnosticCode = "DSCO201"
+	CodeDuplicateLayer     DiagnosticCode = "DSCO202"
+	CodeInvalidSecretRef   DiagnosticCode = "DSCO203"
+	CodeLayerCancelled     DiagnosticCode = "DSCO204"
+	CodeParse              DiagnosticCode = "DSCO301"
+	CodeAliasCollision     DiagnosticCode = "DSCO302"
+	CodeInterpolation      DiagnosticCode = "DSCO303"
+	CodeUnboundKey         DiagnosticCode = "DSCO401"
+	CodeUninitializedKey   DiagnosticCode = "DSCO501"
+	CodeOverriddenKey      DiagnosticCode = "DSCO601"
+	CodeValidation         DiagnosticCode = "DSCO701"
+)
+
+// DiagnosticLayerRef identifies the layer a Diagnostic or a Warning comes
+// from.
+type DiagnosticLayerRef struct {
+	// Index is the position of the layer in the Fill arguments.
+	Index int `json:"index"`
+
+	// Name is the layer name (i.e. "env:MYAPP"), empty when the layer
+	// could not be set up.
+	Name string `json:"name,omitempty"`
+}
+
+// Diagnostic is a single error of a Fill error.
+type Diagnostic struct {
+	Code     DiagnosticCode      `json:"code"`
+	Kind     DiagnosticKind      `json:"kind"`
+	Path     string              `json:"path,omitempty"`
+	Layer    *DiagnosticLayerRef `json:"layer,omitempty"`
+	Location string              `json:"location,omitempty"`
+	Message  string              `json:"message"`
+
+	// Suggestions holds the closest valid keys of an unbound key.
+	Suggestions []string `json:"suggestions,omitempty"`
+}
+
+// DiagnosticList is the flat list of the errors of a Fill error.
+type DiagnosticList []Diagnostic
+
+// Diagnostics flattens err, an error returned by Fill, FillContext, Watch
+// or Dispatch, into one typed Diagnostic per error, in reporting order. It
+// returns nil when err is nil.
+func Diagnostics(err error) DiagnosticList {
+	if err == nil {
+		return nil
+	}
+
+	var list DiagnosticList
+
+	list.add(err, nil, false)
+
+	return list
+}
+
+// add appends the diagnostics of err, reported by layer (nil when unknown)
+// and, when inModel is true, by the model build.
+//
+//nolint:cyclop,funlen // one case per error type
+func (d *DiagnosticList) add(
+	err error,
+	layer *DiagnosticLayerRef,
+	inModel bool,
+) {
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		switch v := e.(type) {
+		case interface{ Errors() []error }:
+			_, isModel := v.(model.ModelError)
+
+			for _, child := range v.Errors() {
+				d.add(child, layer, inModel || isModel)
+			}
+
+			return
+
+		case ierror.IError:
+			if v.Info == "layer" {
+				d.add(
+					v.Err,
+					&DiagnosticLayerRef{Index: v.Index, Name: v.Name},
+					inModel,
+				)
+
+				return
+			}
+
+		case layerValuesError:
+			d.add(
+				v.Err,
+				&DiagnosticLayerRef{Index: v.Index, Name: v.Name},
+				inModel,
+			)
+
+			return
+		}
+	}
+
+	diagnostic := Diagnostic{
+		Code:    CodeUnknown,
+		Kind:    DiagnosticOther,
+		Layer:   layer,
+		Message: err.Error(),
+	}
+
+	if layer != nil {
+		diagnostic.Code, diagnostic.Kind = CodeInvalidLayer, DiagnosticLayer
+	}
+
+	if inModel {
+		diagnostic.Code, diagnostic.Kind = CodeInvalidModel, DiagnosticModel
+	}
+
+	diagnostic.classify(err)
+
+	*d = append(*d, diagnostic)
+}
+
+// classify sets the code, kind, path and location of the diagnostic of
+// err from its type.
+//
+//nolint:cyclop,funlen // one case per error type
+func (d *Diagnostic) classify(err error) {
+	var (
+		parseErr         ParseError
+		parsePtrErr      *ParseError
+		aliasErr         AliasCollisionError
+		aliasPtrErr      *AliasCollisionError
+		interpolationErr InterpolationError
+		unboundErr       UnboundedLocationError
+		uninitializedErr model.UninitializedKeyError
+		overriddenErr    OverriddenKeyError
+		validationErr    ValidationError
+		tagErr           model.InvalidTagError
+		typeErr          model.UnsupportedTypeError
+		collisionErr     model.FieldNameCollisionError
+		keyTagErr        KeyTagCollisionError
+		secretRefErr     InvalidSecretRefError
+		commandErr       UnknownCommandError
+	)
+
+	switch {
+	case errors.As(err, &parseErr):
+		d.set(CodeParse, DiagnosticParse, parseErr.Path, parseErr.Location)
+	case errors.As(err, &parsePtrErr):
+		d.set(
+			CodeParse,
+			DiagnosticParse,
+			parsePtrErr.Path,
+			parsePtrErr.Location,
+		)
+	case errors.As(err, &aliasErr):
+		d.set(CodeAliasCollision, DiagnosticParse, aliasErr.Path, "")
+	case errors.As(err, &aliasPtrErr):
+		d.set(CodeAliasCollision, DiagnosticParse, aliasPtrErr.Path, "")
+	case errors.As(err, &interpolationErr):
+		d.set(
+			CodeInterpolation,
+			DiagnosticInterpolation,
+			"",
+			interpolationErr.Location,
+		)
+	case errors.As(err, &unboundErr):
+		d.set(CodeUnboundKey, DiagnosticUnboundKey, "", unboundErr.Location)
+		d.Suggestions = unboundErr.Suggestions
+	case errors.As(err, &uninitializedErr):
+		d.set(
+			CodeUninitializedKey,
+			DiagnosticUninitializedKey,
+			uninitializedErr.Path,
+			"",
+		)
+	case errors.As(err, &overriddenErr):
+		d.set(
+			CodeOverriddenKey,
+			DiagnosticOverriddenKey,
+			overriddenErr.Path,
+			overriddenErr.Location,
+		)
+	case errors.As(err, &validationErr):
+		d.set(
+			CodeValidation,
+			DiagnosticValidation,
+			validationErr.Path,
+			validationErr.Location.Location,
+		)
+	case errors.As(err, &tagErr):
+		d.set(CodeInvalidTag, DiagnosticModel, tagErr.Path, "")
+	case errors.As(err, &typeErr):
+		d.set(CodeUnsupportedType, DiagnosticModel, typeErr.Path, "")
+	case errors.As(err, &collisionErr):
+		d.set(CodeFieldCollision, DiagnosticModel, collisionErr.Path1, "")
+	case errors.As(err, &keyTagErr):
+		d.set(CodeKeyTagCollision, DiagnosticModel, keyTagErr.Path, "")
+	case errors.As(err, &secretRefErr):
+		d.set(CodeInvalidSecretRef, DiagnosticLayer, secretRefErr.Path, "")
+	case errors.As(err, &commandErr):
+		d.set(CodeUnknownCommand, DiagnosticInput, "", "")
+	case errors.Is(err, ErrInvalidInput), errors.Is(err, ErrCfgMustBePointer):
+		d.set(CodeInvalidInput, DiagnosticInput, "", "")
+	case errors.Is(err, ErrCmdlineAlreadyUsed):
+		d.set(CodeCmdlineAlreadyUsed, DiagnosticLayer, "", "")
+	case errors.Is(err, ErrDuplicateEnvPrefix),
+		errors.Is(err, ErrDuplicateInputStruct),
+		errors.Is(err, ErrDuplicateStructID),
+		errors.Is(err, ErrDuplicateFile),
+		errors.Is(err, ErrDuplicateKFile),
+		errors.Is(err, ErrDuplicateHTTP),
+		errors.Is(err, ErrDuplicateSecretID),
+		errors.Is(err, ErrDuplicateStringProvider):
+		d.set(CodeDuplicateLayer, DiagnosticLayer, "", "")
+	case errors.Is(err, context.Canceled),
+		errors.Is(err, context.DeadlineExceeded):
+		d.set(CodeLayerCancelled, DiagnosticLayer, "", "")
+	}
+}
+
+func (d *Diagnostic) set(
+	code DiagnosticCode,
+	kind DiagnosticKind,
+	path, location string,
+) {
+	d.Code, d.Kind, d.Path, d.Location = code, kind, path, location
+}
+
+// WriteJSON writes the diagnostics to w as an indented JSON array.
+func (d DiagnosticList) WriteJSON(w io.Writer) error {
+	list := d
+	if list == nil {
+		list = DiagnosticList{}
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(list) //nolint:wrapcheck // writer error
+}
+
+const (
+	ansiReset = "\x1b[0m"
+	ansiBold  = "\x1b[1m"
+	ansiDim   = "\x1b[2m"
+	ansiRed   = "\x1b[31m"
+)
+
+// Render writes the diagnostics to w for a terminal, one block per
+// diagnostic, with ANSI colors when color is true.
+func (d DiagnosticList) Render(w io.Writer, color bool) error {
+	style := func(code, text string) string {
+		if !color || text == "" {
+			return text
+		}
+
+		return code + text + ansiReset
+	}
+
+	var sb strings.Builder
+
+	switch len(d) {
+	case 0:
+		return nil
+	case 1:
+		sb.WriteString(style(ansiBold, "1 configuration error"))
+	default:
+		sb.WriteString(
+			style(ansiBold, fmt.Sprintf("%d configuration errors", len(d))),
+		)
+	}
+
+	sb.WriteString("\n")
+
+	for _, diagnostic := range d {
+		sb.WriteString("\n  ")
+		sb.WriteString(
+			style(
+				ansiRed+ansiBold,
+				fmt.Sprintf("%s %s", diagnostic.Code, diagnostic.Kind),
+			),
+		)
+
+		if diagnostic.Path != "" {
+			sb.WriteString("  ")
+			sb.WriteString(style(ansiBold, diagnostic.Path))
+		}
+
+		sb.WriteString("\n")
+
+		var details []string
+
+		if diagnostic.Layer != nil {
+			details = append(details, layerLabel(*diagnostic.Layer))
+		}
+
+		if diagnostic.Location != "" {
+			details = append(details, diagnostic.Location)
+		}
+
+		if len(details) > 0 {
+			sb.WriteString("    ")
+			sb.WriteString(style(ansiDim, strings.Join(details, " · ")))
+			sb.WriteString("\n")
+		}
+
+		for _, line := range strings.Split(diagnostic.Message, "\n") {
+			sb.WriteString("    ")
+			sb.WriteString(strings.TrimSpace(line))
+			sb.WriteString("\n")
+		}
+	}
+
+	_, err := io.WriteString(w, sb.String())
+
+	return err //nolint:wrapcheck // writer error
+}
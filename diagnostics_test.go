@@ -0,0 +1,281 @@
+package dsco
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiagnostics(t *testing.T) {
+	t.Parallel()
+
+	type Root struct {
+		Port *int `dsco:"min=1"`
+		Name *string
+	}
+
+	type Invalid struct {
+		C chan int
+	}
+
+	fill := func(layers ...Layer) error {
+		var cfg *Root
+
+		_, err := Fill(&cfg, layers...)
+
+		return err
+	}
+
+	env := func(environ map[string]string) EnvOption {
+		return WithEnviron(environ)
+	}
+
+	tests := []struct {
+		name string
+		err  error
+		want DiagnosticList
+	}{
+		{
+			name: "nil",
+		},
+		{
+			name: "input",
+			err: func() error {
+				_, err := Fill(
+					&Root{},
+					WithEnvLayer("APP", env(map[string]string{})),
+				)
+
+				return err
+			}(),
+			want: DiagnosticList{
+				{Code: CodeInvalidInput, Kind: DiagnosticInput},
+			},
+		},
+		{
+			name: "model",
+			err: func() error {
+				var cfg *Invalid
+
+				_, err := Fill(
+					&cfg,
+					WithEnvLayer("APP", env(map[string]string{})),
+				)
+
+				return err
+			}(),
+			want: DiagnosticList{
+				{Code: CodeUnsupportedType, Kind: DiagnosticModel, Path: "C"},
+			},
+		},
+		{
+			name: "duplicate layer",
+			err: fill(
+				WithEnvLayer("APP", env(map[string]string{})),
+				WithEnvLayer("APP", env(map[string]string{})),
+			),
+			want: DiagnosticList{
+				{
+					Code:  CodeDuplicateLayer,
+					Kind:  DiagnosticLayer,
+					Layer: &DiagnosticLayerRef{Index: 1, Name: "env:APP"},
+				},
+			},
+		},
+		{
+			name: "layer setup",
+			err: fill(
+				WithCmdlineLayer(WithArgs("--port=1", "--name=a")),
+				WithFileLayer("/no/such/dir/../app.yaml"),
+			),
+			want: DiagnosticList{
+				{
+					Code: CodeInvalidLayer,
+					Kind: DiagnosticLayer,
+					Layer: &DiagnosticLayerRef{
+						Index: 1,
+						Name:  "file:/no/such/app.yaml",
+					},
+				},
+			},
+		},
+		{
+			name: "layer values",
+			err: fill(
+				WithCmdlineLayer(WithArgs("--port=eighty")),
+				WithStrictEnvLayer(
+					"APP",
+					env(map[string]string{"APP-NAME": "a", "APP-NAMES": "b"}),
+				),
+			),
+			want: DiagnosticList{
+				{
+					Code:     CodeParse,
+					Kind:     DiagnosticParse,
+					Path:     "Port",
+					Layer:    &DiagnosticLayerRef{Index: 0, Name: "cmdline"},
+					Location: "cmdline[--port]",
+				},
+				{
+					Code:        CodeUnboundKey,
+					Kind:        DiagnosticUnboundKey,
+					Layer:       &DiagnosticLayerRef{Index: 1, Name: "env:APP"},
+					Location:    "env[APP-NAMES]",
+					Suggestions: []string{"APP-NAME"},
+				},
+			},
+		},
+		{
+			name: "uninitialized",
+			err: fill(
+				WithEnvLayer("APP", env(map[string]string{"APP-PORT": "80"})),
+			),
+			want: DiagnosticList{
+				{
+					Code: CodeUninitializedKey,
+					Kind: DiagnosticUninitializedKey,
+					Path: "Name",
+				},
+			},
+		},
+		{
+			name: "validation",
+			err: fill(
+				WithEnvLayer(
+					"APP",
+					env(map[string]string{"APP-PORT": "0", "APP-NAME": "a"}),
+				),
+			),
+			want: DiagnosticList{
+				{
+					Code:     CodeValidation,
+					Kind:     DiagnosticValidation,
+					Path:     "Port",
+					Location: "env[APP-PORT]",
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(
+			tt.name, func(t *testing.T) {
+				t.Parallel()
+
+				got := Diagnostics(tt.err)
+
+				for i := range got {
+					require.NotEmpty(t, got[i].Message)
+					got[i].Message = ""
+				}
+
+				require.Equal(t, tt.want, got)
+			},
+		)
+	}
+}
+
+func TestDiagnostics_other(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(
+		t,
+		DiagnosticList{
+			{
+				Code:    CodeUnknown,
+				Kind:    DiagnosticOther,
+				Message: errMocked1.Error(),
+			},
+		},
+		Diagnostics(errMocked1),
+	)
+}
+
+//nolint:gochecknoglobals // test fixture
+var testDiagnostics = DiagnosticList{
+	{
+		Code:     CodeParse,
+		Kind:     DiagnosticParse,
+		Path:     "Port",
+		Layer:    &DiagnosticLayerRef{Index: 0, Name: "cmdline"},
+		Location: "cmdline[--port]",
+		Message:  "bad port",
+	},
+	{
+		Code:    CodeUninitializedKey,
+		Kind:    DiagnosticUninitializedKey,
+		Path:    "Name",
+		Message: "uninitialized",
+	},
+}
+
+func TestDiagnosticList_WriteJSON(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	require.NoError(t, testDiagnostics.WriteJSON(&buf))
+	require.JSONEq(
+		t,
+		`[
+			{
+				"code": "DSCO301",
+				"kind": "parse",
+				"path": "Port",
+				"layer": {"index": 0, "name": "cmdline"},
+				"location": "cmdline[--port]",
+				"message": "bad port"
+			},
+			{
+				"code": "DSCO501",
+				"kind": "uninitialized_key",
+				"path": "Name",
+				"message": "uninitialized"
+			}
+		]`,
+		buf.String(),
+	)
+
+	buf.Reset()
+	require.NoError(t, DiagnosticList(nil).WriteJSON(&buf))
+	require.Equal(t, "[]\n", buf.String())
+}
+
+func TestDiagnosticList_Render(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	require.NoError(t, testDiagnostics.Render(&buf, false))
+	require.Equal(
+		t,
+		"2 configuration errors\n"+
+			"\n"+
+			"  DSCO301 parse  Port\n"+
+			"    layer #0 cmdline · cmdline[--port]\n"+
+			"    bad port\n"+
+			"\n"+
+			"  DSCO501 uninitialized_key  Name\n"+
+			"    uninitialized\n",
+		buf.String(),
+	)
+
+	buf.Reset()
+	require.NoError(t, testDiagnostics[1:].Render(&buf, true))
+	require.Equal(
+		t,
+		"\x1b[1m1 configuration error\x1b[0m\n"+
+			"\n"+
+			"  \x1b[31m\x1b[1mDSCO501 uninitialized_key\x1b[0m  "+
+			"\x1b[1mName\x1b[0m\n"+
+			"    uninitialized\n",
+		buf.String(),
+	)
+
+	buf.Reset()
+	require.NoError(t, DiagnosticList(nil).Render(&buf, true))
+	require.Empty(t, buf.String())
+}
@@ -0,0 +1,120 @@
+package diff
+
+import (
+	"fmt"
+
+	"github.com/byte4ever/dsco"
+	"github.com/byte4ever/dsco/internal/plocation"
+)
+
+// Kind is the kind of change of a field.
+type Kind string
+
+const (
+	// KindAdded is a value present in the new configuration only.
+	KindAdded Kind = "added"
+	// KindRemoved is a value present in the old configuration only.
+	KindRemoved Kind = "removed"
+	// KindChanged is a value present in both configurations, with a
+	// different value or location.
+	KindChanged Kind = "changed"
+)
+
+type (
+	// Report is the difference between two filled configurations.
+	Report struct {
+		Type   string  `json:"type"   yaml:"type"`
+		Fields []Field `json:"fields" yaml:"fields"`
+	}
+
+	// Field describes the change of one leaf field. Old is nil for added
+	// fields and New is nil for removed ones.
+	Field struct {
+		Old  *Side  `json:"old,omitempty"  yaml:"old,omitempty"`
+		New  *Side  `json:"new,omitempty"  yaml:"new,omitempty"`
+		Path string `json:"path"           yaml:"path"`
+		Kind Kind   `json:"kind"           yaml:"kind"`
+		// Secret is true when the field values are redacted.
+		Secret bool `json:"secret,omitempty" yaml:"secret,omitempty"`
+	}
+
+	// Side is the value of a field in one configuration and the location it
+	// was filled from. Values of secret fields are dsco.SecretMask.
+	Side struct {
+		Value    any    `json:"value"              yaml:"value"`
+		Location string `json:"location,omitempty" yaml:"location,omitempty"`
+	}
+)
+
+// Compute returns the changes from oldCfg to newCfg, both *T filled by
+// dsco.Fill. Locations are the reports returned by Fill, used to tell
+// where every value comes from; they can be nil to compare values only.
+// Fields are sorted by path.
+//
+// Pattern: Factory — assembles a Report from two filled configurations.
+func Compute(
+	oldCfg any,
+	oldLocations plocation.Locations,
+	newCfg any,
+	newLocations plocation.Locations,
+) (*Report, error) {
+	const errCtx = "computing diff"
+
+	walk, err := dsco.PrepareDiffWalk(
+		oldCfg,
+		oldLocations,
+		newCfg,
+		newLocations,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", errCtx, err)
+	}
+
+	return reduce(walk), nil
+}
+
+// reduce converts the changes of walk into report fields, redacting the
+// values of secret fields.
+func reduce(walk *dsco.DiffWalk) *Report {
+	fields := make([]Field, 0, len(walk.Changes))
+
+	for _, change := range walk.Changes {
+		field := Field{
+			Path: change.Path,
+			Kind: KindChanged,
+			Secret: walk.Model.IsSecret(change.Path) ||
+				change.Old.Secret ||
+				change.New.Secret,
+		}
+
+		if value, found := walk.OldValues[change.Path]; found {
+			field.Old = newSide(value, change.Old, field.Secret)
+		} else {
+			field.Kind = KindAdded
+		}
+
+		if value, found := walk.NewValues[change.Path]; found {
+			field.New = newSide(value, change.New, field.Secret)
+		} else {
+			field.Kind = KindRemoved
+		}
+
+		fields = append(fields, field)
+	}
+
+	return &Report{
+		Type:   walk.Model.TypeName(),
+		Fields: fields,
+	}
+}
+
+func newSide(value any, location plocation.Location, secret bool) *Side {
+	if secret {
+		value = dsco.SecretMask
+	}
+
+	return &Side{
+		Value:    value,
+		Location: location.Location,
+	}
+}
@@ -0,0 +1,251 @@
+package diff_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/byte4ever/dsco"
+	"github.com/byte4ever/dsco/diff"
+	"github.com/byte4ever/dsco/internal/plocation"
+)
+
+type backend struct {
+	URL *string `yaml:"url"`
+}
+
+type config struct {
+	Host     *string             `yaml:"host"`
+	Port     *int                `yaml:"port"`
+	Timeout  *time.Duration      `yaml:"timeout"`
+	Password *string             `yaml:"password" dsco:"secret"`
+	Backends map[string]*backend `yaml:"backends" dsco:"optional"`
+}
+
+func fill(t *testing.T, input *config) (*config, plocation.Locations) {
+	t.Helper()
+
+	var cfg *config
+
+	locations, err := dsco.Fill(&cfg, dsco.WithStructLayer(input, "input"))
+	require.NoError(t, err)
+
+	return cfg, locations
+}
+
+// TestCompute verifies changed, added and removed fields are reported with
+// their locations and secret values are redacted.
+func TestCompute(t *testing.T) {
+	t.Parallel()
+
+	oldCfg, oldLocations := fill(
+		t, &config{
+			Host:     dsco.R("a.local"),
+			Port:     dsco.R(80),
+			Timeout:  dsco.R(time.Second),
+			Password: dsco.R("old-secret"),
+			Backends: map[string]*backend{
+				"eu": {URL: dsco.R("http://eu")},
+			},
+		},
+	)
+
+	newCfg, newLocations := fill(
+		t, &config{
+			Host:     dsco.R("a.local"),
+			Port:     dsco.R(81),
+			Timeout:  dsco.R(time.Second),
+			Password: dsco.R("new-secret"),
+			Backends: map[string]*backend{
+				"us": {URL: dsco.R("http://us")},
+			},
+		},
+	)
+
+	report, err := diff.Compute(oldCfg, oldLocations, newCfg, newLocations)
+	require.NoError(t, err)
+
+	require.Equal(
+		t,
+		[]diff.Field{
+			{
+				Path: "Backends[eu].URL",
+				Kind: diff.KindRemoved,
+				Old: &diff.Side{
+					Value:    "http://eu",
+					Location: "struct[input]:Backends[eu].URL",
+				},
+			},
+			{
+				Path: "Backends[us].URL",
+				Kind: diff.KindAdded,
+				New: &diff.Side{
+					Value:    "http://us",
+					Location: "struct[input]:Backends[us].URL",
+				},
+			},
+			{
+				Path:   "Password",
+				Kind:   diff.KindChanged,
+				Secret: true,
+				Old: &diff.Side{
+					Value:    dsco.SecretMask,
+					Location: "struct[input]:Password",
+				},
+				New: &diff.Side{
+					Value:    dsco.SecretMask,
+					Location: "struct[input]:Password",
+				},
+			},
+			{
+				Path: "Port",
+				Kind: diff.KindChanged,
+				Old: &diff.Side{
+					Value:    80,
+					Location: "struct[input]:Port",
+				},
+				New: &diff.Side{
+					Value:    81,
+					Location: "struct[input]:Port",
+				},
+			},
+		},
+		report.Fields,
+	)
+	assert.Contains(t, report.Type, "config")
+}
+
+// TestComputeWithoutLocations verifies values are compared when no
+// locations are given.
+func TestComputeWithoutLocations(t *testing.T) {
+	t.Parallel()
+
+	oldCfg, _ := fill(
+		t, &config{
+			Host:     dsco.R("a.local"),
+			Port:     dsco.R(80),
+			Timeout:  dsco.R(time.Second),
+			Password: dsco.R("secret"),
+		},
+	)
+
+	newCfg, _ := fill(
+		t, &config{
+			Host:     dsco.R("a.local"),
+			Port:     dsco.R(80),
+			Timeout:  dsco.R(time.Minute),
+			Password: dsco.R("secret"),
+		},
+	)
+
+	report, err := diff.Compute(oldCfg, nil, newCfg, nil)
+	require.NoError(t, err)
+
+	require.Equal(
+		t,
+		[]diff.Field{
+			{
+				Path: "Timeout",
+				Kind: diff.KindChanged,
+				Old:  &diff.Side{Value: time.Second},
+				New:  &diff.Side{Value: time.Minute},
+			},
+		},
+		report.Fields,
+	)
+}
+
+// TestComputeWithoutLocations_elementSecrets verifies secret fields of
+// collection elements are redacted without locations.
+func TestComputeWithoutLocations_elementSecrets(t *testing.T) {
+	t.Parallel()
+
+	type node struct {
+		User     *string `yaml:"user"`
+		Password *string `yaml:"password" dsco:"secret"`
+	}
+
+	type root struct {
+		Backends map[string]*node `yaml:"backends"`
+		List     []*node          `yaml:"list"`
+	}
+
+	fillRoot := func(password string) *root {
+		var cfg *root
+
+		_, err := dsco.Fill(
+			&cfg, dsco.WithStructLayer(
+				&root{
+					Backends: map[string]*node{
+						"eu": {User: dsco.R("app"), Password: dsco.R(password)},
+					},
+					List: []*node{
+						{User: dsco.R("app"), Password: dsco.R(password)},
+					},
+				}, "input",
+			),
+		)
+		require.NoError(t, err)
+
+		return cfg
+	}
+
+	report, err := diff.Compute(fillRoot("old"), nil, fillRoot("new"), nil)
+	require.NoError(t, err)
+
+	require.Equal(
+		t,
+		[]diff.Field{
+			{
+				Path:   "Backends[eu].Password",
+				Kind:   diff.KindChanged,
+				Secret: true,
+				Old:    &diff.Side{Value: dsco.SecretMask},
+				New:    &diff.Side{Value: dsco.SecretMask},
+			},
+			{
+				Path:   "List[0].Password",
+				Kind:   diff.KindChanged,
+				Secret: true,
+				Old:    &diff.Side{Value: dsco.SecretMask},
+				New:    &diff.Side{Value: dsco.SecretMask},
+			},
+		},
+		report.Fields,
+	)
+}
+
+// TestComputeSameConfig verifies identical configurations have no field.
+func TestComputeSameConfig(t *testing.T) {
+	t.Parallel()
+
+	cfg, locations := fill(
+		t, &config{
+			Host:     dsco.R("a.local"),
+			Port:     dsco.R(80),
+			Timeout:  dsco.R(time.Second),
+			Password: dsco.R("secret"),
+		},
+	)
+
+	report, err := diff.Compute(cfg, locations, cfg, locations)
+	require.NoError(t, err)
+	require.Empty(t, report.Fields)
+}
+
+// TestComputeErrors verifies mismatching and invalid configurations fail.
+func TestComputeErrors(t *testing.T) {
+	t.Parallel()
+
+	type other struct {
+		Host *string
+	}
+
+	_, err := diff.Compute(&config{}, nil, &other{}, nil)
+	require.ErrorIs(t, err, dsco.ErrDiffTypeMismatch)
+
+	_, err = diff.Compute(config{}, nil, config{}, nil)
+	require.ErrorIs(t, err, dsco.ErrCfgMustBePointer)
+}
@@ -0,0 +1,6 @@
+// Package diff computes the structured difference between two filled
+// configurations of the same dsco-managed struct, field path by field path,
+// with the source location of every old and new value.
+//
+// See https://github.com/byte4ever/dsco for the parent project.
+package diff
@@ -0,0 +1,125 @@
+package diff_test
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/byte4ever/dsco/diff"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+// fixtureReport returns a deterministic Report covering the interesting
+// cases (added, removed, changed, secret, no location).
+func fixtureReport() *diff.Report {
+	return &diff.Report{
+		Type: "github.com/example/myapp.Config",
+		Fields: []diff.Field{
+			{
+				Path: "Backends[eu].URL",
+				Kind: diff.KindRemoved,
+				Old: &diff.Side{
+					Value:    "http://eu.local",
+					Location: "file[/etc/myapp/config.yaml]:4:5",
+				},
+			},
+			{
+				Path: "Backends[us].URL",
+				Kind: diff.KindAdded,
+				New: &diff.Side{
+					Value:    "http://us.local",
+					Location: "env[MYAPP-BACKENDS-US-URL]",
+				},
+			},
+			{
+				Path:   "Database.Password",
+				Kind:   diff.KindChanged,
+				Secret: true,
+				Old: &diff.Side{
+					Value:    "******",
+					Location: "kfile[/var/run/secrets]:DATABASE-PASSWORD",
+				},
+				New: &diff.Side{
+					Value:    "******",
+					Location: "kfile[/var/run/secrets]:DATABASE-PASSWORD",
+				},
+			},
+			{
+				Path: "Server.Port",
+				Kind: diff.KindChanged,
+				Old: &diff.Side{
+					Value:    8080,
+					Location: "struct[defaults]:Server.Port",
+				},
+				New: &diff.Side{
+					Value:    9090,
+					Location: "cmdline[--server-port]",
+				},
+			},
+			{
+				Path: "Server.Timeout",
+				Kind: diff.KindChanged,
+				Old:  &diff.Side{Value: 30 * time.Second},
+				New:  &diff.Side{Value: time.Minute},
+			},
+		},
+	}
+}
+
+// TestWriteJSONMatchesGolden verifies JSON output is byte-stable.
+func TestWriteJSONMatchesGolden(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	require.NoError(t, fixtureReport().WriteJSON(&buf))
+
+	checkGolden(t, "testdata/sample.json", buf.Bytes())
+}
+
+// TestWriteYAMLMatchesGolden verifies YAML output is byte-stable.
+func TestWriteYAMLMatchesGolden(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	require.NoError(t, fixtureReport().WriteYAML(&buf))
+
+	checkGolden(t, "testdata/sample.yaml", buf.Bytes())
+}
+
+// checkGolden compares got to the contents of path; with -update, writes
+// got to path instead.
+func checkGolden(t *testing.T, path string, got []byte) {
+	t.Helper()
+	if *update {
+		require.NoError(t, os.WriteFile(path, got, 0o644))
+		return
+	}
+	want, err := os.ReadFile(path)
+	require.NoError(t, err, "missing golden — run with -update to generate")
+	assert.Equal(t, string(want), string(got))
+}
+
+type errWriter struct{ err error }
+
+func (w errWriter) Write([]byte) (int, error) { return 0, w.err }
+
+// TestWriteJSONPropagatesWriterError covers the error branch when the
+// underlying io.Writer fails.
+func TestWriteJSONPropagatesWriterError(t *testing.T) {
+	t.Parallel()
+	err := fixtureReport().WriteJSON(errWriter{err: assert.AnError})
+	require.ErrorIs(t, err, assert.AnError)
+}
+
+// TestWriteYAMLPropagatesWriterError covers the same for WriteYAML.
+func TestWriteYAMLPropagatesWriterError(t *testing.T) {
+	t.Parallel()
+	err := fixtureReport().WriteYAML(errWriter{err: assert.AnError})
+	require.ErrorIs(t, err, assert.AnError)
+}
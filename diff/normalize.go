@@ -0,0 +1,123 @@
+package diff
+
+import (
+	"fmt"
+
+	gojson "github.com/goccy/go-json"
+	goyaml "github.com/goccy/go-yaml"
+)
+
+type (
+	// sideJSON is a helper struct for JSON marshaling of Side.
+	// It mirrors Side but holds the normalized value.
+	sideJSON struct {
+		Value    any    `json:"value"`
+		Location string `json:"location,omitempty"`
+	}
+
+	// sideYAML is a helper struct for YAML marshaling of Side.
+	// It mirrors Side but holds the normalized value.
+	sideYAML struct {
+		Value    any    `yaml:"value"`
+		Location string `yaml:"location,omitempty"`
+	}
+
+	// fieldJSON is a helper struct for JSON marshaling of Field.
+	// Fields are emitted in human-readable order: path, kind, secret, old,
+	// new.
+	//nolint:govet // fieldalignment: output field order takes priority over struct padding
+	fieldJSON struct {
+		Path   string `json:"path"`
+		Kind   Kind   `json:"kind"`
+		Secret bool   `json:"secret,omitempty"`
+		Old    *Side  `json:"old,omitempty"`
+		New    *Side  `json:"new,omitempty"`
+	}
+
+	// fieldYAML is a helper struct for YAML marshaling of Field.
+	// Fields are emitted in human-readable order: path, kind, secret, old,
+	// new.
+	//nolint:govet // fieldalignment: output field order takes priority over struct padding
+	fieldYAML struct {
+		Path   string `yaml:"path"`
+		Kind   Kind   `yaml:"kind"`
+		Secret bool   `yaml:"secret,omitempty"`
+		Old    *Side  `yaml:"old,omitempty"`
+		New    *Side  `yaml:"new,omitempty"`
+	}
+)
+
+// Compile-time interface assertions.
+var (
+	_ gojson.Marshaler          = Side{}
+	_ goyaml.InterfaceMarshaler = Side{}
+	_ gojson.Marshaler          = Field{}
+	_ goyaml.InterfaceMarshaler = Field{}
+)
+
+// normalizeValue converts fmt.Stringer values (time.Duration, time.Time,
+// *url.URL, …) to their String() form so JSON / YAML / text output stays
+// human-readable. Primitives and plain structs pass through unchanged.
+func normalizeValue(val any) any {
+	if val == nil {
+		return nil
+	}
+
+	if stringer, ok := val.(fmt.Stringer); ok {
+		return stringer.String()
+	}
+
+	return val
+}
+
+// MarshalJSON implements json.Marshaler so Side.Value is normalized before
+// serialization.
+func (s Side) MarshalJSON() ([]byte, error) {
+	raw, err := gojson.Marshal(sideJSON{
+		Value:    normalizeValue(s.Value),
+		Location: s.Location,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("marshaling side: %w", err)
+	}
+
+	return raw, nil
+}
+
+// MarshalYAML implements yaml.InterfaceMarshaler so Side.Value is
+// normalized before serialization.
+func (s Side) MarshalYAML() (any, error) {
+	return sideYAML{
+		Value:    normalizeValue(s.Value),
+		Location: s.Location,
+	}, nil
+}
+
+// MarshalJSON implements json.Marshaler so Field keys are emitted in
+// human-readable order: path, kind, secret, old, new.
+func (f Field) MarshalJSON() ([]byte, error) {
+	raw, err := gojson.Marshal(fieldJSON{
+		Path:   f.Path,
+		Kind:   f.Kind,
+		Secret: f.Secret,
+		Old:    f.Old,
+		New:    f.New,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("marshaling field: %w", err)
+	}
+
+	return raw, nil
+}
+
+// MarshalYAML implements yaml.InterfaceMarshaler so Field keys are emitted in
+// human-readable order: path, kind, secret, old, new.
+func (f Field) MarshalYAML() (any, error) {
+	return fieldYAML{
+		Path:   f.Path,
+		Kind:   f.Kind,
+		Secret: f.Secret,
+		Old:    f.Old,
+		New:    f.New,
+	}, nil
+}
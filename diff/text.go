@@ -0,0 +1,107 @@
+package diff
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	emDash             = "—"
+	textMinPath        = 20
+	textMinKind        = 7
+	textMinSide        = 20
+	textMaxValueLength = 40
+)
+
+// WriteText writes a human-readable, fixed-width-column diff to writer.
+// Columns: PATH | CHANGE | OLD | NEW. Value cells render as
+// "<value> (<location>)", missing sides as "—". Long values are truncated
+// with ellipsis. Output ends with a trailing newline.
+func (r *Report) WriteText(writer io.Writer) error {
+	const errCtx = "writing text diff"
+
+	rows := buildTextRows(r)
+
+	pathWidth := columnWidth(rows, 0, textMinPath)
+	kindWidth := columnWidth(rows, 1, textMinKind)
+	oldWidth := columnWidth(rows, 2, textMinSide)
+
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "TYPE: %s\n\n", r.Type)
+	fmt.Fprintf(
+		&buf,
+		"%-*s  %-*s  %-*s  %s\n",
+		pathWidth, "PATH",
+		kindWidth, "CHANGE",
+		oldWidth, "OLD",
+		"NEW",
+	)
+
+	for _, row := range rows {
+		fmt.Fprintf(
+			&buf,
+			"%-*s  %-*s  %-*s  %s\n",
+			pathWidth, row[0],
+			kindWidth, row[1],
+			oldWidth, row[2],
+			row[3],
+		)
+	}
+
+	if _, err := io.WriteString(writer, buf.String()); err != nil {
+		return fmt.Errorf("%s: %w", errCtx, err)
+	}
+
+	return nil
+}
+
+// buildTextRows converts r.Fields into the [path, kind, old, new]
+// quadruples used by WriteText.
+func buildTextRows(rep *Report) [][4]string {
+	rows := make([][4]string, 0, len(rep.Fields))
+
+	for _, fld := range rep.Fields {
+		rows = append(rows, [4]string{
+			fld.Path,
+			string(fld.Kind),
+			renderTextSide(fld.Old),
+			renderTextSide(fld.New),
+		})
+	}
+
+	return rows
+}
+
+// renderTextSide renders a Side as "<value> (<location>)" with truncation,
+// or em-dash when nil.
+func renderTextSide(side *Side) string {
+	if side == nil {
+		return emDash
+	}
+
+	val := fmt.Sprintf("%v", normalizeValue(side.Value))
+	if len(val) > textMaxValueLength {
+		val = val[:textMaxValueLength-1] + "…"
+	}
+
+	if side.Location == "" {
+		return val
+	}
+
+	return val + " (" + side.Location + ")"
+}
+
+// columnWidth returns max(len(rows[col]), minimum) — the width needed to
+// fit every value in the column.
+func columnWidth(rows [][4]string, col, minimum int) int {
+	width := minimum
+
+	for _, row := range rows {
+		if len(row[col]) > width {
+			width = len(row[col])
+		}
+	}
+
+	return width
+}
@@ -0,0 +1,53 @@
+package diff_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/byte4ever/dsco/diff"
+)
+
+// TestWriteTextMatchesGolden verifies the human-readable layout is stable.
+func TestWriteTextMatchesGolden(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	require.NoError(t, fixtureReport().WriteText(&buf))
+
+	checkGolden(t, "testdata/sample.txt", buf.Bytes())
+}
+
+// TestWriteTextTruncatesLongValues verifies values >40 chars get truncated
+// with ellipsis.
+func TestWriteTextTruncatesLongValues(t *testing.T) {
+	t.Parallel()
+
+	long := strings.Repeat("x", 80)
+	rep := &diff.Report{
+		Type: "Cfg",
+		Fields: []diff.Field{
+			{
+				Path: "X",
+				Kind: diff.KindAdded,
+				New:  &diff.Side{Value: long},
+			},
+		},
+	}
+	var buf bytes.Buffer
+	require.NoError(t, rep.WriteText(&buf))
+
+	assert.Contains(t, buf.String(), "…", "long values must end with ellipsis")
+	assert.NotContains(t, buf.String(), long, "full value must not appear")
+	assert.Contains(t, buf.String(), "—", "missing side must use em-dash")
+}
+
+// TestWriteTextPropagatesWriterError covers the error branch.
+func TestWriteTextPropagatesWriterError(t *testing.T) {
+	t.Parallel()
+	err := fixtureReport().WriteText(errWriter{err: assert.AnError})
+	require.ErrorIs(t, err, assert.AnError)
+}
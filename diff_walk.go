@@ -0,0 +1,70 @@
+package dsco
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/byte4ever/dsco/internal/plocation"
+)
+
+type (
+	// DiffWalk holds the leaf values and the changes between two filled
+	// configurations of the same model. It is used by the diff sub-package
+	// to compute a Report.
+	DiffWalk struct {
+		Model     ModelInterface
+		OldValues map[string]any
+		NewValues map[string]any
+		Changes   []Change
+	}
+)
+
+// ErrDiffTypeMismatch indicates that the two configurations of a diff do
+// not have the same type.
+var ErrDiffTypeMismatch = errors.New("configuration types mismatch")
+
+// PrepareDiffWalk builds the model of the filled configurations oldCfg and
+// newCfg, both *T, and computes their changes. Locations are the fill
+// reports of the configurations, they can be nil when unknown.
+//
+// Pattern: Factory — assembles the inputs needed for a diff.
+func PrepareDiffWalk(
+	oldCfg any,
+	oldLocations plocation.Locations,
+	newCfg any,
+	newLocations plocation.Locations,
+) (*DiffWalk, error) {
+	const errCtx = "preparing diff walk"
+
+	oldType, newType := reflect.TypeOf(oldCfg), reflect.TypeOf(newCfg)
+	if oldType != newType {
+		return nil, fmt.Errorf(
+			"%s: %v and %v: %w",
+			errCtx,
+			oldType,
+			newType,
+			ErrDiffTypeMismatch,
+		)
+	}
+
+	mdl, err := buildModel(oldCfg)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", errCtx, err)
+	}
+
+	walk := &DiffWalk{
+		Model:     mdl,
+		OldValues: leafValues(mdl, reflect.ValueOf(oldCfg)),
+		NewValues: leafValues(mdl, reflect.ValueOf(newCfg)),
+	}
+
+	walk.Changes = diffLeaves(
+		walk.OldValues,
+		oldLocations,
+		walk.NewValues,
+		newLocations,
+	)
+
+	return walk, nil
+}
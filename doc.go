@@ -42,9 +42,12 @@ The library supports multiple configuration sources:
 
 - Command line arguments (WithCmdlineLayer, WithStrictCmdlineLayer)
 - Environment variables (WithEnvLayer, WithStrictEnvLayer)
+- Configuration files (WithFileLayer, WithStrictFileLayer)
 - Go structs (WithStructLayer, WithStrictStructLayer)
 - Custom string providers (WithStringValueProvider, WithStrictStringValueProvider)
-- File-based providers (via external packages like kfile)
+- Secret directories (WithKFileLayer, WithStrictKFileLayer)
+- Remote documents over HTTP (WithHTTPLayer, WithStrictHTTPLayer)
+- Secret stores (WithSecretLayer, WithStrictSecretLayer)
 
 # Safety Design
 
@@ -56,6 +59,9 @@ dsco enforces explicit configuration through pointer-based fields:
 - Services fail fast with clear error messages
 - No hidden defaults or silent failures
 
+Fields tagged `dsco:"optional"` are the exception: Fill leaves them nil when
+no layer provides them.
+
 # Strict Mode
 
 Strict mode layers detect unused configuration values and conflicts:
@@ -67,7 +73,9 @@ Strict mode layers detect unused configuration values and conflicts:
 	)
 
 This ensures all provided configuration values are actually used, preventing
-configuration drift and accidental misconfigurations.
+configuration drift and accidental misconfigurations. A key bound to no
+field is reported with the closest keys of the model, i.e. "unbounded
+location env[MYAPP-DATABSE-HOST], did you mean MYAPP-DATABASE-HOST?".
 
 # Error Handling
 
@@ -85,6 +93,20 @@ The library provides comprehensive error types with location tracking:
 		}
 	}
 
+A ParseError holds the offending raw value, redacted when secret, a hint
+such as "expected integer, got 'eighty'", the decoder or YAML error as its
+cause and, for YAML blobs, the line and column of the value in the blob.
+
+Diagnostics flattens any Fill error into a DiagnosticList, one entry per
+error with a stable code (DSCO301...), a kind, the field path, the layer
+index and name and the location. The list renders as JSON or for a
+terminal:
+
+	if err != nil {
+		_ = dsco.Diagnostics(err).Render(os.Stderr, true)
+		os.Exit(1)
+	}
+
 # Testing
 
 The dsco library maintains 100% test coverage across all core packages,
@@ -113,6 +135,131 @@ The library implements a sophisticated processing pipeline:
 5. Type Conversion: String values converted to target types via YAML
 6. Validation: Required fields and custom validation applied
 7. Struct Filling: Target struct populated with resolved values
+8. Rule Validation: dsco tag rules and Validator methods checked
+
+# Validation
+
+Fields can declare rules in their dsco tag and structs can implement
+Validator for cross-field checks:
+
+	type Server struct {
+		Port  *int    `dsco:"min=1,max=65535"`
+		Level *string `dsco:"oneof=debug info warn"`
+	}
+
+	func (s *Server) Validate() error { ... }
+
+Every failure is reported as a ValidationError, holding the field path and
+the location of the winning value, aggregated in FillerErrors.
+
+# Hot Reload
+
+Watch fills a configuration and reloads it when a file or kfile layer
+source changes. Every fill runs like FillContext with the watch context.
+The new configuration replaces the current one, returned by
+Watcher.Config, only when the fill succeeds, and every reload is reported
+on the Watcher.Events channel as a ChangeEvent.
+
+# Fingerprint
+
+Fingerprint computes a deterministic Merkle hash of a filled configuration,
+with the hash of every subtree by path, to log a configuration version or
+find which part of a configuration differs between replicas.
+
+# Command Line
+
+The command line layer accepts --key=value, --key value, bare boolean
+--flag and --no-flag, single letter -k value keys and the -- terminator.
+Boolean fields are detected from the model, other fields requiring a value,
+and WithPositionalArgs collects the arguments that are not options, which
+are rejected without it. WithArgs replaces os.Args, and
+WithEnviron or WithEnvironFunc replace os.Environ for an environment layer,
+so a configuration can be loaded without touching the process state.
+
+# Environment Naming
+
+Environment layers name variables MYAPP-DATABASE-POOL_SIZE by default.
+WithEnvNaming changes the prefix, path and word separators and the case
+sensitivity, i.e. ShellEnvNaming for MYAPP_DATABASE__POOL_SIZE, and the
+prefix can be empty.
+
+# Remote Configuration
+
+The HTTP layer fetches a JSON, YAML or TOML document and reports values at
+http[url]:/json/pointer locations. It sends conditional requests once the
+server gave an ETag, and WithHTTPTimeout, WithHTTPRetries, WithBearerToken,
+WithBasicAuth and WithHTTPCacheFile, which keeps a last known good document,
+tune the fetch. When a fill falls back on the cache file, the layer Stale
+method returns the fetch error.
+
+# Secret Stores
+
+The secret layer reads the fields mapped to a path#field secret, by a
+secret struct tag or WithSecretPaths, from a SecretClient in a single
+batch. Its values are always redacted, and Watch reloads the configuration
+when the first secret lease expires, backing off while the store can't be
+read.
+
+# Custom Types
+
+Leaf values are YAML unmarshalled, except for types implementing
+encoding.TextUnmarshaler, such as netip.Prefix or slog.Level, decoded with
+UnmarshalText, and types registered with registry.RegisterDecoder, decoded
+with their own decoder, which also decodes the items of their slices. A
+decoder error is the Err of the field ParseError.
+
+# Context
+
+FillContext fills a configuration like Fill, the file, kfile, HTTP, secret
+and context provider layers (ContextLayer) reading their source with the
+context, so startup can be bounded or cancelled. Each layer that can't
+complete reports the context error in FillerErrors, and on any error the
+configuration is left untouched.
+
+# Warnings
+
+FillWithWarnings fills a configuration like FillContext and returns the
+non-fatal issues of the layers in FillResult.Warnings: keys of normal
+layers bound to no field, values shadowed by higher-priority layers, values
+of fields tagged dsco:"deprecated" and HTTP layers using their cache file.
+Warnings are returned even when the fill fails.
+
+# Key Tags
+
+The env, flag and file struct tags replace the key generated from the
+field path in environment, command line and file layers, i.e.
+env:"DATABASE_URL" is read by every environment layer whatever its prefix.
+The generated key is then unbound: non-strict environment layers ignore the
+generated variable, other layers report it as unbounded. A tagged key used by another field or an alias fails with a
+KeyTagCollisionError.
+
+# Usage
+
+WithHelp makes the command line layer accept --help and -h: Fill then
+prints a usage text listing every flag with its type, its desc struct tag
+description, its struct layer default and its environment variable, and
+exits. Usage writes the same text to any writer.
+
+# Subcommands
+
+Dispatch fills a parent configuration from the options preceding the
+command name, then the configuration of the selected Command with its own
+layers from the arguments following it. The command line layers of each
+configuration only see their own arguments, and the usage text of the
+parent lists the commands. Command.InventoryLayers and
+DispatchInventoryLayers return the layers of a command and of the parent
+scoped the same way, for their inventory. WithArgs given to Dispatch
+replaces the process arguments.
+
+# Interpolation
+
+WithInterpolation makes a string based layer expand ${env:NAME},
+${file:/path} and ${ref:field.path} references in its values. Field
+references are resolved across all the layers in Fill order, and expanded
+references are reported in the References of the value location.
+Environment references read the variables set by WithEnviron or
+WithEnvironFunc in environment layers, those returned by the
+WithInterpolationEnviron function or the process environment otherwise.
 
 For complete documentation and examples, see:
 https://pkg.go.dev/github.com/byte4ever/dsco
@@ -123,5 +270,12 @@ The inventory sub-package (github.com/byte4ever/dsco/inventory) computes
 a static list of configuration keys a Fill call would expect, with no
 I/O, suitable for "what do I need to set" diagnostics in operator
 tooling. See the Inventory section in README.md for examples.
+
+# Diff
+
+The diff sub-package (github.com/byte4ever/dsco/diff) compares two filled
+configurations of the same type and reports every added, removed or
+changed field with its old and new location, in text, JSON or YAML. See
+the Diff section in README.md for examples.
 */
 package dsco
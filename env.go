@@ -0,0 +1,165 @@
+package dsco
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/byte4ever/dsco/internal/env"
+)
+
+// ErrEnvOnlyOption represents an error where an environment option is used
+// by a layer that is not an environment layer.
+var ErrEnvOnlyOption = errors.New("option is only supported by env layers")
+
+// EnvOption is a processing option only supported by environment layers.
+type EnvOption interface {
+	Option
+	envOption() env.Option
+}
+
+type envOption env.Option
+
+func (envOption) apply(*internalOpts) error {
+	return ErrEnvOnlyOption
+}
+
+func (o envOption) envOption() env.Option {
+	return env.Option(o)
+}
+
+// WithEnviron makes the environment layer read the variables of environ
+// instead of the process environment, for its values and their ${env:NAME}
+// references alike.
+func WithEnviron(environ map[string]string) EnvOption {
+	entries := make([]string, 0, len(environ))
+
+	for name, value := range environ {
+		entries = append(entries, name+"="+value)
+	}
+
+	sort.Strings(entries)
+
+	return WithEnvironFunc(
+		func() []string {
+			return entries
+		},
+	)
+}
+
+// WithEnvironFunc makes the environment layer read the KEY=value entries
+// returned by environ instead of the process environment, like the content
+// of /proc/<pid>/environ or the Config.Env list of docker inspect. The
+// ${env:NAME} references of the layer are read from them as well.
+func WithEnvironFunc(environ func() []string) EnvOption {
+	return environOption(environ)
+}
+
+type environOption func() []string
+
+func (environOption) apply(*internalOpts) error {
+	return ErrEnvOnlyOption
+}
+
+func (o environOption) envOption() env.Option {
+	return env.WithEnviron(o)
+}
+
+// envEnviron returns the entries set by the last WithEnviron or
+// WithEnvironFunc option, or nil.
+func envEnviron(options []Option) func() []string {
+	var environ func() []string
+
+	for _, option := range options {
+		if eo, ok := option.(environOption); ok {
+			environ = eo
+		}
+	}
+
+	return environ
+}
+
+// EnvNaming is the naming scheme of the variables of an environment layer.
+// The field path Database.MaxConns under the prefix MYAPP is named
+// MYAPP<PrefixSeparator>DATABASE<PathSeparator>MAX<WordSeparator>CONNS.
+// Separators can't hold letters, digits or '=', and the path and word
+// separators must differ.
+type EnvNaming struct {
+	// PrefixSeparator separates the prefix from the key.
+	PrefixSeparator string
+
+	// PathSeparator separates nested field names.
+	PathSeparator string
+
+	// WordSeparator separates the words of a field name.
+	WordSeparator string
+
+	// CaseInsensitive accepts the prefix and the keys in any case, they must
+	// be upper case otherwise.
+	CaseInsensitive bool
+}
+
+// DefaultEnvNaming returns the MYAPP-DATABASE-MAX_CONNS naming scheme used
+// by environment layers.
+func DefaultEnvNaming() EnvNaming {
+	return EnvNaming(env.DefaultNaming())
+}
+
+// ShellEnvNaming returns the MYAPP_DATABASE__MAX_CONNS naming scheme, whose
+// names are valid in every shell.
+func ShellEnvNaming() EnvNaming {
+	return EnvNaming{
+		PrefixSeparator: "_",
+		PathSeparator:   "__",
+		WordSeparator:   "_",
+	}
+}
+
+type envNamingOption EnvNaming
+
+func (envNamingOption) apply(*internalOpts) error {
+	return ErrEnvOnlyOption
+}
+
+func (o envNamingOption) envOption() env.Option {
+	return env.WithNaming(env.Naming(o))
+}
+
+// WithEnvNaming makes the environment layer name its variables with the
+// naming scheme, in parsing, inventories and usage texts alike.
+func WithEnvNaming(naming EnvNaming) EnvOption {
+	return envNamingOption(naming)
+}
+
+// envNaming returns the naming scheme set by the last WithEnvNaming option,
+// or the default one.
+func envNaming(options []Option) env.Naming {
+	naming := env.DefaultNaming()
+
+	for _, option := range options {
+		if no, ok := option.(envNamingOption); ok {
+			naming = env.Naming(no)
+		}
+	}
+
+	return naming
+}
+
+// splitEnvOptions separates environment provider options from string based
+// builder options.
+func splitEnvOptions(options []Option) ([]env.Option, []Option) {
+	var (
+		envOptions     []env.Option
+		builderOptions []Option
+	)
+
+	for _, option := range options {
+		if eo, ok := option.(EnvOption); ok {
+			envOptions = append(envOptions, eo.envOption())
+			continue
+		}
+
+		builderOptions = append(builderOptions, option)
+	}
+
+	return envOptions, builderOptions
+}
@@ -0,0 +1,173 @@
+package dsco
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvOption_apply(t *testing.T) {
+	t.Parallel()
+
+	for _, option := range []EnvOption{
+		WithEnviron(map[string]string{"API-PORT": "1"}),
+		WithEnvironFunc(func() []string { return nil }),
+	} {
+		require.ErrorIs(
+			t,
+			option.apply(&internalOpts{}),
+			ErrEnvOnlyOption,
+		)
+		require.NotNil(t, option.envOption())
+	}
+}
+
+func Test_splitEnvOptions(t *testing.T) {
+	t.Parallel()
+
+	aliases := WithAliases(map[string]string{"a": "b"})
+
+	envOptions, builderOptions := splitEnvOptions(
+		[]Option{WithEnviron(nil), aliases},
+	)
+
+	require.Len(t, envOptions, 1)
+	require.Equal(t, []Option{aliases}, builderOptions)
+}
+
+func TestEnvOption_usedByOtherLayer(t *testing.T) {
+	t.Parallel()
+
+	err := WithCmdlineLayer(WithArgs(), WithEnviron(nil)).
+		register(newLayerBuilder(1))
+	require.ErrorIs(t, err, ErrEnvOnlyOption)
+}
+
+func TestFill_withEnviron(t *testing.T) {
+	t.Parallel()
+
+	type Root struct {
+		Port *int
+		Host *string
+	}
+
+	var cfg *Root
+
+	locations, err := Fill(
+		&cfg,
+		WithEnvLayer(
+			"API",
+			WithEnviron(map[string]string{"API-PORT": "8080"}),
+		),
+		WithEnvLayer(
+			"PROC",
+			// i.e. the NUL separated content of /proc/<pid>/environ
+			WithEnvironFunc(
+				func() []string {
+					return []string{"PROC-PORT=1", "PROC-HOST=remote"}
+				},
+			),
+		),
+	)
+	require.NoError(t, err)
+	require.Equal(t, 8080, *cfg.Port)
+	require.Equal(t, "remote", *cfg.Host)
+	require.Equal(
+		t,
+		"env[API-PORT]",
+		locationOf(t, locations, "Port").Location,
+	)
+}
+
+func TestFill_envNaming(t *testing.T) {
+	t.Parallel()
+
+	type Database struct {
+		MaxConns *int
+		Host     *string
+	}
+
+	type Root struct {
+		Database *Database
+		Port     *int
+	}
+
+	var cfg *Root
+
+	locations, err := Fill(
+		&cfg,
+		WithEnvLayer(
+			"MYAPP",
+			WithEnvNaming(ShellEnvNaming()),
+			WithEnviron(
+				map[string]string{
+					"MYAPP_DATABASE__MAX_CONNS": "10",
+					"MYAPP_PORT":                "8080",
+				},
+			),
+		),
+		WithEnvLayer(
+			"",
+			WithEnvNaming(ShellEnvNaming()),
+			WithEnviron(
+				map[string]string{
+					"DATABASE__HOST": "db",
+					"HOME":           "/root",
+				},
+			),
+		),
+	)
+	require.NoError(t, err)
+	require.Equal(t, 10, *cfg.Database.MaxConns)
+	require.Equal(t, "db", *cfg.Database.Host)
+	require.Equal(t, 8080, *cfg.Port)
+	require.Equal(
+		t,
+		"env[MYAPP_DATABASE__MAX_CONNS]",
+		locationOf(t, locations, "Database.MaxConns").Location,
+	)
+
+	// unbounded variables are reported by strict layers without prefix
+	_, err = Fill(
+		&cfg,
+		WithStrictEnvLayer(
+			"",
+			WithEnvNaming(ShellEnvNaming()),
+			WithEnviron(map[string]string{"HOME": "/root", "PORT": "1"}),
+		),
+	)
+	require.ErrorContains(t, err, "unbounded location env[HOME]")
+
+	_, err = Fill(
+		&cfg,
+		WithEnvLayer(
+			"MYAPP",
+			WithEnvNaming(EnvNaming{PathSeparator: "_", WordSeparator: "_"}),
+		),
+	)
+	require.ErrorContains(t, err, "invalid naming")
+}
+
+func TestUsage_envNaming(t *testing.T) {
+	t.Parallel()
+
+	type Root struct {
+		MaxConns *int
+	}
+
+	var (
+		cfg *Root
+		buf bytes.Buffer
+	)
+
+	require.NoError(
+		t,
+		Usage(
+			&buf,
+			&cfg,
+			WithEnvLayer("MYAPP", WithEnvNaming(ShellEnvNaming())),
+		),
+	)
+	require.Contains(t, buf.String(), "env: MYAPP_MAX_CONNS")
+}
@@ -55,6 +55,36 @@ type DuplicateStructIDError struct {
 	Index int
 }
 
+// ErrDuplicateFile is the sentinel error for duplicate file layer.
+var ErrDuplicateFile = errors.New("")
+
+// DuplicateFileError represents an error where the same file is used by
+// multiple layers.
+type DuplicateFileError struct {
+	Path  string
+	Index int
+}
+
+// ErrDuplicateKFile is the sentinel error for duplicate kfile layer.
+var ErrDuplicateKFile = errors.New("")
+
+// DuplicateKFileError represents an error where the same kfile directory is
+// used by multiple layers.
+type DuplicateKFileError struct {
+	Dir   string
+	Index int
+}
+
+// ErrDuplicateHTTP is the sentinel error for duplicate http layer.
+var ErrDuplicateHTTP = errors.New("")
+
+// DuplicateHTTPError represents an error where the same URL is used by
+// multiple layers.
+type DuplicateHTTPError struct {
+	URL   string
+	Index int
+}
+
 // InvalidInputError methods.
 func (c InvalidInputError) Error() string {
 	return fmt.Sprintf(
@@ -117,6 +147,45 @@ func (DuplicateStructIDError) Is(err error) bool {
 	return errors.Is(err, ErrDuplicateStructID)
 }
 
+// DuplicateFileError methods.
+func (c DuplicateFileError) Error() string {
+	return fmt.Sprintf(
+		"file layer #%d is using same path=%q",
+		c.Index,
+		c.Path,
+	)
+}
+
+func (DuplicateFileError) Is(err error) bool {
+	return errors.Is(err, ErrDuplicateFile)
+}
+
+// DuplicateKFileError methods.
+func (c DuplicateKFileError) Error() string {
+	return fmt.Sprintf(
+		"kfile layer #%d is using same dir=%q",
+		c.Index,
+		c.Dir,
+	)
+}
+
+func (DuplicateKFileError) Is(err error) bool {
+	return errors.Is(err, ErrDuplicateKFile)
+}
+
+// DuplicateHTTPError methods.
+func (c DuplicateHTTPError) Error() string {
+	return fmt.Sprintf(
+		"http layer #%d is using same url=%q",
+		c.Index,
+		c.URL,
+	)
+}
+
+func (DuplicateHTTPError) Is(err error) bool {
+	return errors.Is(err, ErrDuplicateHTTP)
+}
+
 // ErrDuplicateStringProvider is the sentinel error for duplicate string
 // provider.
 var ErrDuplicateStringProvider = errors.New("duplicate string provider")
@@ -150,6 +150,62 @@ func TestDuplicateStructIDError_Is(t *testing.T) {
 	)
 }
 
+func TestDuplicateFileError_Error(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(
+		t,
+		`file layer #12 is using same path="/etc/app.yaml"`,
+		DuplicateFileError{
+			Index: 12,
+			Path:  "/etc/app.yaml",
+		}.Error(),
+	)
+}
+
+func TestDuplicateFileError_Is(t *testing.T) {
+	t.Parallel()
+
+	require.NotErrorIs(
+		t,
+		errMocked1,
+		ErrDuplicateFile,
+	)
+	require.ErrorIs(
+		t,
+		DuplicateFileError{},
+		ErrDuplicateFile,
+	)
+}
+
+func TestDuplicateKFileError_Error(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(
+		t,
+		`kfile layer #3 is using same dir="/run/secrets"`,
+		DuplicateKFileError{
+			Index: 3,
+			Dir:   "/run/secrets",
+		}.Error(),
+	)
+}
+
+func TestDuplicateKFileError_Is(t *testing.T) {
+	t.Parallel()
+
+	require.NotErrorIs(
+		t,
+		errMocked1,
+		ErrDuplicateKFile,
+	)
+	require.ErrorIs(
+		t,
+		DuplicateKFileError{},
+		ErrDuplicateKFile,
+	)
+}
+
 func TestLayerErrors_Is(t *testing.T) {
 	t.Parallel()
 
@@ -4,14 +4,12 @@ package main
 
 import (
 	"fmt"
-	"log"
 	"os"
 	"time"
 
 	"gopkg.in/yaml.v3"
 
 	"github.com/byte4ever/dsco"
-	"github.com/byte4ever/dsco/internal/kfile"
 )
 
 // RetryConfTmpl is a sample config.
@@ -49,13 +47,6 @@ type MainConf struct {
 }
 
 func main() {
-	// try to get some variable/secrets from file system
-	secretProvider, err := kfile.NewEntriesProvider(
-		"examples/deadsimple/secrets")
-	if err != nil {
-		log.Fatal(err)
-	}
-
 	// DSCO will try to fill (and allocate the config struct
 	var pp *MainConf
 
@@ -144,7 +135,11 @@ func main() {
 			"mutable", // <- this is the layer id
 		),
 
-		dsco.WithStringValueProvider(secretProvider),
+		// try to get some variable/secrets from file system
+		dsco.WithKFileLayer(
+			"examples/deadsimple/secrets",
+			dsco.WithTrimTrailingNewlines(),
+		),
 	)
 
 	// If structure fill fails because of missing value field then structure
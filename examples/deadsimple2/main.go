@@ -4,14 +4,12 @@ package main
 
 import (
 	"fmt"
-	"log"
 	"os"
 	"time"
 
 	"gopkg.in/yaml.v3"
 
 	"github.com/byte4ever/dsco"
-	"github.com/byte4ever/dsco/internal/kfile"
 )
 
 // RetryConfTmpl is a sample config.
@@ -61,13 +59,6 @@ access_token: null
 		panic(err)
 	}
 
-	// try to get some variable/secrets from file system
-	secretProvider, err := kfile.NewEntriesProvider(
-		"examples/deadsimple/secrets")
-	if err != nil {
-		log.Fatal(err)
-	}
-
 	// DSCO will try to fill (and allocate the config struct
 	var pp *MainConf
 
@@ -134,7 +125,11 @@ access_token: null
 			"mutable", // <- this is the layer id
 		),
 
-		dsco.WithStringValueProvider(secretProvider),
+		// try to get some variable/secrets from file system
+		dsco.WithKFileLayer(
+			"examples/deadsimple/secrets",
+			dsco.WithTrimTrailingNewlines(),
+		),
 	)
 
 	// If structure fill fails because of missing value field then structure
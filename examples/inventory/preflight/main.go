@@ -65,13 +65,13 @@ func main() {
 	os.Exit(2)
 }
 
-// requiredKeys returns the canonical key for every field that has no
-// baked-in default. These are the keys the operator must set for the
+// requiredKeys returns the canonical key for every non optional field that
+// has no baked-in default. These are the keys the operator must set for the
 // service to start.
 func requiredKeys(report *inventory.Report) []string {
 	var keys []string
 	for _, field := range report.Fields {
-		if field.Satisfied != nil {
+		if field.Satisfied != nil || field.Optional {
 			continue
 		}
 		if field.Key == nil {
@@ -4,9 +4,11 @@
 package dsco
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"reflect"
+	"sort"
 
 	"github.com/byte4ever/dsco/internal/fvalue"
 	"github.com/byte4ever/dsco/internal/merror"
@@ -18,6 +20,7 @@ import (
 // filling process across multiple phases: model generation, builder
 // creation, field value extraction, struct filling, and validation.
 type dscoContext struct {
+	ctx           context.Context
 	inputModelRef any
 	err           FillerErrors
 	layers        PoliciesGetter
@@ -28,6 +31,10 @@ type dscoContext struct {
 	layerFieldValues []fvalue.Values
 	mustBeUsed       []int
 	pathLocations    plocation.Locations
+
+	// warn enables the collection of warnings.
+	warn     bool
+	warnings []Warning
 }
 
 // FillerErrors aggregates multiple errors that can occur during the
@@ -52,12 +59,15 @@ func (FillerErrors) Is(err error) bool {
 }
 
 // newDSCOContext creates a new configuration filling context with the
-// target struct reference and source layers to process.
+// target struct reference and source layers to process, whose sources are
+// read with ctx.
 func newDSCOContext(
+	ctx context.Context,
 	inputModelRef any,
 	layers Layers,
 ) *dscoContext {
 	return &dscoContext{
+		ctx:           ctx,
 		inputModelRef: inputModelRef,
 		layers:        layers,
 	}
@@ -106,7 +116,7 @@ func (c *dscoContext) generateBuilders() {
 	if c.err.None() {
 		var err error
 
-		c.builders, err = c.layers.GetPolicies()
+		c.builders, err = c.layers.GetPoliciesContext(c.ctx, c.model)
 		if err != nil {
 			c.err.Add(err)
 		}
@@ -119,16 +129,18 @@ func (c *dscoContext) generateFieldValues() {
 			base, err := builder.GetFieldValuesFrom(c.model)
 			if err != nil {
 				c.err.Add(
-					fmt.Errorf(
-						"layer #%d\n %w",
-						idx,
-						err,
-					),
+					layerValuesError{
+						Err:   err,
+						Name:  layerName(builder),
+						Index: idx,
+					},
 				)
 
 				continue
 			}
 
+			c.collectLayerWarnings(idx, builder, base)
+
 			if builder.isStrict() {
 				c.mustBeUsed = append(c.mustBeUsed, len(c.layerFieldValues))
 			}
@@ -138,6 +150,37 @@ func (c *dscoContext) generateFieldValues() {
 	}
 }
 
+// layerValuesError represents an error where the values of the layer
+// named Name at position Index can't be got.
+type layerValuesError struct {
+	Err   error
+	Name  string
+	Index int
+}
+
+func (e layerValuesError) Error() string {
+	return fmt.Sprintf("layer #%d\n %s", e.Index, e.Err.Error())
+}
+
+func (e layerValuesError) Unwrap() error {
+	return e.Err
+}
+
+// layerName returns the name of the layer of policy (i.e. "env:MYAPP"),
+// empty when unknown.
+func layerName(policy constraintLayerPolicy) string {
+	switch getter := policy.getFieldValuesGetter().(type) {
+	case *StringBasedBuilder:
+		if getter.keyFormatter != nil {
+			return getter.keyFormatter.LayerName()
+		}
+	case *StructBuilder:
+		return "struct:" + getter.id
+	}
+
+	return ""
+}
+
 func (c *dscoContext) fillIt() {
 	if c.err.None() {
 		v := reflect.ValueOf(c.inputModelRef).Elem()
@@ -156,11 +199,11 @@ func (c *dscoContext) checkUnused() {
 	if c.err.None() {
 		for _, idx := range c.mustBeUsed {
 			for valUID, e := range c.layerFieldValues[idx] {
-				c.err.Add(
-					OverriddenKeyError{
-						Path:             c.pathLocations[valUID].Path,
-						Location:         e.Location,
-						OverrideLocation: c.pathLocations[valUID].Location,
+				c.reportOverridden(
+					valUID,
+					e,
+					func(err OverriddenKeyError) {
+						c.err.Add(err)
 					},
 				)
 			}
@@ -168,6 +211,71 @@ func (c *dscoContext) checkUnused() {
 	}
 }
 
+// reportOverridden reports the unused value e of field uid with report. Map
+// values are reported entry leaf by entry leaf.
+func (c *dscoContext) reportOverridden(
+	uid uint,
+	e *fvalue.Value,
+	report func(OverriddenKeyError),
+) {
+	if e.Entries == nil {
+		override := c.overrideLocation(uid, e.Path)
+
+		path := e.Path
+		if path == "" {
+			path = override.Path
+		}
+
+		report(
+			OverriddenKeyError{
+				Path:             path,
+				Location:         e.Location,
+				OverrideLocation: override.Location,
+			},
+		)
+
+		return
+	}
+
+	keys := make([]string, 0, len(e.Entries))
+	for key := range e.Entries {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		for _, entryValue := range e.Entries[key] {
+			c.reportOverridden(uid, entryValue, report)
+		}
+	}
+}
+
+// overrideLocation returns the location of the value filled for path, or
+// the first location of field uid when path was not filled (i.e. a map
+// entry missing from the winning map).
+func (c *dscoContext) overrideLocation(
+	uid uint,
+	path string,
+) plocation.Location {
+	var (
+		byUID plocation.Location
+		found bool
+	)
+
+	for _, location := range c.pathLocations {
+		if path != "" && location.Path == path {
+			return location
+		}
+
+		if !found && location.UID == uid {
+			byUID, found = location, true
+		}
+	}
+
+	return byUID
+}
+
 // Fill fills the structure using the layers.
 func Fill(
 	inputModelRef any,
@@ -176,17 +284,62 @@ func Fill(
 	plocation.Locations,
 	error,
 ) {
-	fillContext := newDSCOContext(inputModelRef, layers)
+	return newDSCOContext(context.Background(), inputModelRef, layers).fill()
+}
+
+// FillContext fills the structure using the layers like Fill, the sources
+// of context layers being read with ctx. When ctx is done, the layers that
+// can't complete report the context error in FillerErrors. On any error,
+// the partial results are discarded: the structure is left untouched and no
+// location is returned.
+func FillContext(
+	ctx context.Context,
+	inputModelRef any,
+	layers ...Layer,
+) (
+	plocation.Locations,
+	error,
+) {
+	return newDSCOContext(ctx, inputModelRef, layers).fillOrRestore()
+}
+
+// fillOrRestore runs every phase of the filling process, restoring the
+// structure and discarding the locations on error.
+func (c *dscoContext) fillOrRestore() (plocation.Locations, error) {
+	var previous reflect.Value
+
+	if v := reflect.ValueOf(c.inputModelRef); v.Kind() == reflect.Pointer &&
+		!v.IsNil() {
+		previous = reflect.ValueOf(v.Elem().Interface())
+	}
 
-	fillContext.generateModel()
-	fillContext.generateBuilders()
-	fillContext.generateFieldValues()
-	fillContext.fillIt()
-	fillContext.checkUnused()
+	locations, err := c.fill()
+	if err == nil {
+		return locations, nil
+	}
+
+	if previous.IsValid() && c.model != nil {
+		reflect.ValueOf(c.inputModelRef).Elem().Set(previous)
+	}
 
-	if fillContext.err.None() {
-		return fillContext.pathLocations, nil
+	return nil, err
+}
+
+// fill runs every phase of the filling process.
+func (c *dscoContext) fill() (plocation.Locations, error) {
+	c.generateModel()
+	c.generateBuilders()
+	c.showHelp()
+	c.shareReferences()
+	c.generateFieldValues()
+	c.fillIt()
+	c.checkUnused()
+	c.collectShadowed()
+	c.validate()
+
+	if c.err.None() {
+		return c.pathLocations, nil
 	}
 
-	return fillContext.pathLocations, fillContext.err //nolint:wrapcheck // ok
+	return c.pathLocations, c.err //nolint:wrapcheck // ok
 }
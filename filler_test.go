@@ -1,8 +1,23 @@
 package dsco
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
 	"os"
+	"path/filepath"
 	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -13,6 +28,8 @@ import (
 	"github.com/byte4ever/dsco/internal/merror"
 	"github.com/byte4ever/dsco/internal/plocation"
 	"github.com/byte4ever/dsco/ref"
+	"github.com/byte4ever/dsco/registry"
+	"github.com/byte4ever/dsco/svalue"
 )
 
 func Test_newDSCOContext(t *testing.T) {
@@ -22,10 +39,16 @@ func Test_newDSCOContext(t *testing.T) {
 	layers := Layers{}
 
 	c := newDSCOContext(
+		context.Background(),
 		v,
 		layers,
 	)
 
+	require.Equal(
+		t,
+		context.Background(),
+		c.ctx,
+	)
 	require.Equal(
 		t,
 		v,
@@ -132,11 +155,12 @@ func Test_dscoContext_generateBuilders(t *testing.T) {
 
 			pg := NewMockPoliciesGetter(t)
 			pg.
-				On("GetPolicies").
+				On("GetPoliciesContext", context.Background(), nil).
 				Return(builders, nil).
 				Once()
 
 			c := &dscoContext{
+				ctx:    context.Background(),
 				layers: pg,
 			}
 
@@ -157,11 +181,12 @@ func Test_dscoContext_generateBuilders(t *testing.T) {
 
 			pg := NewMockPoliciesGetter(t)
 			pg.
-				On("GetPolicies").
+				On("GetPoliciesContext", context.Background(), nil).
 				Return(nil, errMocked1).
 				Once()
 
 			c := &dscoContext{
+				ctx:    context.Background(),
 				layers: pg,
 			}
 
@@ -281,6 +306,11 @@ func Test_dscoContext_generateFieldValues(t *testing.T) {
 				Return(nil, errMocked1).
 				Once()
 
+			clp1.
+				On("getFieldValuesGetter").
+				Return(&StructBuilder{id: "defaults"}).
+				Once()
+
 			fvs2 := fvalue.Values{
 				400: &fvalue.Value{
 					Value:    reflect.Value{},
@@ -331,6 +361,15 @@ func Test_dscoContext_generateFieldValues(t *testing.T) {
 				c.err.MError[0],
 				errMocked1,
 			)
+			require.Equal(
+				t,
+				layerValuesError{
+					Err:   errMocked1,
+					Name:  "struct:defaults",
+					Index: 0,
+				},
+				c.err.MError[0],
+			)
 		},
 	)
 }
@@ -531,6 +570,7 @@ func Test_dscoContext_checkUnused(t *testing.T) {
 					plocation.Location{
 						Path:     "path1",
 						Location: "foundLoc1",
+						UID:      1,
 					},
 				},
 				layerFieldValues: base,
@@ -793,3 +833,971 @@ func TestFillerErrors_Is(t *testing.T) {
 		ErrFiller,
 	)
 }
+
+func TestFill_fileLayer(t *testing.T) {
+	t.Parallel()
+
+	type Database struct {
+		Host *string
+		Port *int
+	}
+
+	type Root struct {
+		Database *Database
+		Tags     []string
+		Verbose  *bool
+	}
+
+	dir := t.TempDir()
+
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+		return path
+	}
+
+	yamlPath := write(
+		"app.yaml",
+		"database:\n  host: db.local\n  port: 5432\ntags: [a, b]\n",
+	)
+	tomlPath := write(
+		"app.toml",
+		"verbose = true\n\n[database]\nport = 6543\n",
+	)
+	jsonPath := write(
+		"app.json",
+		`{"database": {"host": "json.local"}, "unknown": 1}`,
+	)
+
+	t.Run(
+		"success",
+		func(t *testing.T) {
+			t.Parallel()
+
+			var pp *Root
+
+			locations, err := Fill(
+				&pp,
+				WithFileLayer(tomlPath),
+				WithFileLayer(jsonPath),
+				WithFileLayer(yamlPath),
+			)
+			require.NoError(t, err)
+
+			require.Equal(t, "json.local", *pp.Database.Host)
+			require.Equal(t, 6543, *pp.Database.Port)
+			require.Equal(t, []string{"a", "b"}, pp.Tags)
+			require.True(t, *pp.Verbose)
+
+			require.ElementsMatch(
+				t,
+				plocation.Locations{
+					{
+						Path:     "Database.Host",
+						Location: "file[" + jsonPath + "]:1:15",
+					},
+					{
+						UID:      1,
+						Path:     "Database.Port",
+						Location: "file[" + tomlPath + "]:4:1",
+					},
+					{
+						UID:      2,
+						Path:     "Tags",
+						Location: "file[" + yamlPath + "]:4:1",
+					},
+					{
+						UID:      3,
+						Path:     "Verbose",
+						Location: "file[" + tomlPath + "]:1:1",
+					},
+				},
+				locations,
+			)
+		},
+	)
+
+	t.Run(
+		"strict unknown key",
+		func(t *testing.T) {
+			t.Parallel()
+
+			var pp *Root
+
+			_, err := Fill(
+				&pp,
+				WithFileLayer(tomlPath),
+				WithStrictFileLayer(jsonPath),
+				WithFileLayer(yamlPath),
+			)
+
+			var ue UnboundedLocationError
+
+			require.ErrorAs(t, err, &ue)
+			require.Equal(
+				t,
+				"file["+jsonPath+"]:1:38",
+				ue.Location,
+			)
+		},
+	)
+
+	t.Run(
+		"duplicate file",
+		func(t *testing.T) {
+			t.Parallel()
+
+			var pp *Root
+
+			_, err := Fill(
+				&pp,
+				WithFileLayer(yamlPath),
+				WithStrictFileLayer(yamlPath),
+			)
+
+			var e DuplicateFileError
+
+			require.ErrorAs(t, err, &e)
+			require.Equal(t, yamlPath, e.Path)
+		},
+	)
+}
+
+func TestFill_mapFields(t *testing.T) {
+	t.Parallel()
+
+	type Backend struct {
+		URL     *string
+		Timeout *time.Duration
+	}
+
+	type Root struct {
+		Backends map[string]*Backend
+		Labels   map[string]string `dsco:"merge=replace"`
+		Weights  map[string]*int
+	}
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.yaml")
+
+	require.NoError(
+		t,
+		os.WriteFile(
+			path,
+			[]byte(
+				"backends:\n"+
+					"  eu:\n    url: https://eu.local\n    timeout: 1s\n"+
+					"  us:\n    url: https://us.local\n    timeout: 2s\n"+
+					"labels:\n  team: core\n  tier: back\n",
+			),
+			0o600,
+		),
+	)
+
+	defaults := &Root{
+		Backends: map[string]*Backend{
+			"asia": {
+				URL:     R("https://asia.local"),
+				Timeout: R(3 * time.Second),
+			},
+		},
+		Labels:  map[string]string{"owner": "ops"},
+		Weights: map[string]*int{"eu": R(1)},
+	}
+
+	t.Run(
+		"merge", func(t *testing.T) {
+			t.Parallel()
+
+			var pp *Root
+
+			locations, err := Fill(
+				&pp,
+				WithStringValueProvider(
+					&stubValuesProvider{
+						name: "env",
+						values: svalue.Values{
+							"backends-us-url": {
+								Location: "env[APP-BACKENDS-US-URL]",
+								Value:    "https://override.local",
+							},
+							"weights": {
+								Location: "env[APP-WEIGHTS]",
+								Value:    "{us: 2}",
+							},
+						},
+					},
+				),
+				WithFileLayer(path),
+				WithStructLayer(defaults, "defaults"),
+			)
+			require.NoError(t, err)
+
+			require.Equal(
+				t,
+				map[string]*Backend{
+					"asia": {
+						URL:     R("https://asia.local"),
+						Timeout: R(3 * time.Second),
+					},
+					"eu": {
+						URL:     R("https://eu.local"),
+						Timeout: R(time.Second),
+					},
+					"us": {
+						URL:     R("https://override.local"),
+						Timeout: R(2 * time.Second),
+					},
+				},
+				pp.Backends,
+			)
+			require.Equal(
+				t,
+				map[string]string{"team": "core", "tier": "back"},
+				pp.Labels,
+			)
+			require.Equal(
+				t,
+				map[string]*int{"eu": R(1), "us": R(2)},
+				pp.Weights,
+			)
+
+			byPath := make(map[string]string, len(locations))
+			for _, location := range locations {
+				byPath[location.Path] = location.Location
+			}
+
+			require.Equal(
+				t,
+				map[string]string{
+					"Backends[asia].URL":     "struct[defaults]:Backends[asia].URL",
+					"Backends[asia].Timeout": "struct[defaults]:Backends[asia].Timeout",
+					"Backends[eu].URL":       "file[" + path + "]:3:5",
+					"Backends[eu].Timeout":   "file[" + path + "]:4:5",
+					"Backends[us].URL":       "env[APP-BACKENDS-US-URL]",
+					"Backends[us].Timeout": "file[" + path +
+						"]:7:5",
+					"Labels[team]": "file[" + path + "]:9:3",
+					"Labels[tier]": "file[" + path + "]:10:3",
+					"Weights[eu]":  "struct[defaults]:Weights[eu]",
+					"Weights[us]":  "env[APP-WEIGHTS]",
+				},
+				byPath,
+			)
+		},
+	)
+
+	t.Run(
+		"missing entry field", func(t *testing.T) {
+			t.Parallel()
+
+			var pp *Root
+
+			_, err := Fill(
+				&pp,
+				WithStringValueProvider(
+					&stubValuesProvider{
+						name: "env",
+						values: svalue.Values{
+							"backends-new-url": {
+								Location: "env[APP-BACKENDS-NEW-URL]",
+								Value:    "https://new.local",
+							},
+						},
+					},
+				),
+				WithStructLayer(defaults, "defaults"),
+			)
+
+			require.ErrorContains(
+				t,
+				err,
+				"Backends[new].Timeout-[*time/time.Duration]: uninitialized key",
+			)
+		},
+	)
+
+	t.Run(
+		"strict overridden entry", func(t *testing.T) {
+			t.Parallel()
+
+			var pp *Root
+
+			_, err := Fill(
+				&pp,
+				WithStringValueProvider(
+					&stubValuesProvider{
+						name: "env",
+						values: svalue.Values{
+							"backends-asia-url": {
+								Location: "env[APP-BACKENDS-ASIA-URL]",
+								Value:    "https://new.local",
+							},
+						},
+					},
+				),
+				WithStrictStructLayer(defaults, "defaults"),
+			)
+
+			var e OverriddenKeyError
+
+			require.ErrorAs(t, err, &e)
+			require.Equal(
+				t,
+				OverriddenKeyError{
+					Path:             "Backends[asia].URL",
+					Location:         "struct[defaults]:Backends[asia].URL",
+					OverrideLocation: "env[APP-BACKENDS-ASIA-URL]",
+				},
+				e,
+			)
+		},
+	)
+}
+
+func TestFill_sliceFields(t *testing.T) {
+	t.Parallel()
+
+	type Listener struct {
+		Port *int
+		TLS  *bool
+	}
+
+	type Root struct {
+		Listeners []*Listener
+		Extra     []*Listener `dsco:"merge=append"`
+		Fallback  []*Listener `dsco:"merge=replace"`
+	}
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.yaml")
+
+	require.NoError(
+		t,
+		os.WriteFile(
+			path,
+			[]byte(
+				"listeners:\n"+
+					"  - port: 80\n    tls: false\n"+
+					"  - port: 443\n    tls: true\n"+
+					"extra:\n  - port: 8080\n    tls: false\n"+
+					"fallback:\n  - port: 9090\n    tls: false\n",
+			),
+			0o600,
+		),
+	)
+
+	defaults := &Root{
+		Listeners: []*Listener{
+			{Port: R(1), TLS: R(false)},
+		},
+		Extra: []*Listener{
+			{Port: R(2), TLS: R(true)},
+		},
+		Fallback: []*Listener{
+			{Port: R(3), TLS: R(true)},
+			{Port: R(4), TLS: R(true)},
+		},
+	}
+
+	t.Run(
+		"policies", func(t *testing.T) {
+			t.Parallel()
+
+			var pp *Root
+
+			locations, err := Fill(
+				&pp,
+				WithStringValueProvider(
+					&stubValuesProvider{
+						name: "env",
+						values: svalue.Values{
+							"listeners-1-port": {
+								Location: "env[APP-LISTENERS-1-PORT]",
+								Value:    "8443",
+							},
+						},
+					},
+				),
+				WithFileLayer(path),
+				WithStructLayer(defaults, "defaults"),
+			)
+			require.NoError(t, err)
+
+			require.Equal(
+				t,
+				[]*Listener{
+					{Port: R(80), TLS: R(false)},
+					{Port: R(8443), TLS: R(true)},
+				},
+				pp.Listeners,
+			)
+			require.Equal(
+				t,
+				[]*Listener{
+					{Port: R(2), TLS: R(true)},
+					{Port: R(8080), TLS: R(false)},
+				},
+				pp.Extra,
+			)
+			require.Equal(
+				t,
+				[]*Listener{
+					{Port: R(9090), TLS: R(false)},
+				},
+				pp.Fallback,
+			)
+
+			byPath := make(map[string]string, len(locations))
+			for _, location := range locations {
+				byPath[location.Path] = location.Location
+			}
+
+			require.Equal(
+				t,
+				"env[APP-LISTENERS-1-PORT]",
+				byPath["Listeners[1].Port"],
+			)
+			require.Equal(
+				t,
+				"struct[defaults]:Extra[0].Port",
+				byPath["Extra[0].Port"],
+			)
+			// elements of a whole list are expanded like struct values
+			require.Equal(
+				t,
+				"struct[file["+path+"]:6:1]:Port",
+				byPath["Extra[1].Port"],
+			)
+			require.Len(t, byPath, 10)
+		},
+	)
+
+	t.Run(
+		"missing element", func(t *testing.T) {
+			t.Parallel()
+
+			var pp *Root
+
+			_, err := Fill(
+				&pp,
+				WithStringValueProvider(
+					&stubValuesProvider{
+						name: "env",
+						values: svalue.Values{
+							"listeners-2-port": {
+								Location: "env[APP-LISTENERS-2-PORT]",
+								Value:    "8443",
+							},
+						},
+					},
+				),
+				WithStructLayer(defaults, "defaults"),
+			)
+
+			require.ErrorContains(
+				t,
+				err,
+				"Listeners[1].Port-[*int]: uninitialized key",
+			)
+			require.ErrorContains(
+				t,
+				err,
+				"Listeners[2].TLS-[*bool]: uninitialized key",
+			)
+		},
+	)
+
+	t.Run(
+		"strict overridden element", func(t *testing.T) {
+			t.Parallel()
+
+			var pp *Root
+
+			_, err := Fill(
+				&pp,
+				WithStringValueProvider(
+					&stubValuesProvider{
+						name: "env",
+						values: svalue.Values{
+							"listeners-0-tls": {
+								Location: "env[APP-LISTENERS-0-TLS]",
+								Value:    "true",
+							},
+						},
+					},
+				),
+				WithStrictStructLayer(defaults, "defaults"),
+			)
+
+			var e OverriddenKeyError
+
+			require.ErrorAs(t, err, &e)
+			require.Equal(
+				t,
+				OverriddenKeyError{
+					Path:             "Listeners[0].TLS",
+					Location:         "struct[defaults]:Listeners[0].TLS",
+					OverrideLocation: "env[APP-LISTENERS-0-TLS]",
+				},
+				e,
+			)
+		},
+	)
+}
+
+func TestFill_optionalFields(t *testing.T) {
+	t.Parallel()
+
+	type TLS struct {
+		Cert *string
+		Key  *string
+	}
+
+	type Root struct {
+		Host  *string
+		Proxy *string `dsco:"optional"`
+		TLS   *TLS    `dsco:"optional"`
+	}
+
+	t.Run(
+		"left nil", func(t *testing.T) {
+			t.Parallel()
+
+			var pp *Root
+
+			locations, err := Fill(
+				&pp,
+				WithStructLayer(&Root{Host: R("a.local")}, "defaults"),
+			)
+			require.NoError(t, err)
+			require.Equal(t, &Root{Host: R("a.local")}, pp)
+			require.Len(t, locations, 1)
+		},
+	)
+
+	t.Run(
+		"provided", func(t *testing.T) {
+			t.Parallel()
+
+			var pp *Root
+
+			_, err := Fill(
+				&pp,
+				WithStringValueProvider(
+					&stubValuesProvider{
+						name: "env",
+						values: svalue.Values{
+							"proxy": {
+								Location: "env[APP-PROXY]",
+								Value:    "http://proxy.local:3128",
+							},
+							"tls-cert": {
+								Location: "env[APP-TLS-CERT]",
+								Value:    "cert.pem",
+							},
+						},
+					},
+				),
+				WithStructLayer(&Root{Host: R("a.local")}, "defaults"),
+			)
+			require.ErrorContains(t, err, "TLS.Key-[*string]: uninitialized key")
+			require.Equal(t, "http://proxy.local:3128", *pp.Proxy)
+		},
+	)
+}
+
+func TestFill_secretFields(t *testing.T) {
+	t.Parallel()
+
+	type Root struct {
+		User     *string
+		Password *string `dsco:"secret"`
+	}
+
+	var pp *Root
+
+	locations, err := Fill(
+		&pp,
+		WithStructLayer(
+			&Root{User: R("app"), Password: R("hunter2")},
+			"defaults",
+		),
+	)
+	require.NoError(t, err)
+	require.Equal(t, "hunter2", *pp.Password)
+
+	var buf bytes.Buffer
+
+	locations.Dump(&buf)
+	require.Contains(t, buf.String(), "struct[defaults]:Password (secret)")
+	require.NotContains(t, buf.String(), "hunter2")
+}
+
+func TestFill_secretElementFields(t *testing.T) {
+	t.Parallel()
+
+	type Backend struct {
+		Host *string
+		Port *int `dsco:"secret"`
+	}
+
+	type Root struct {
+		Backends map[string]*Backend
+	}
+
+	for name, args := range map[string][]string{
+		"map entry": {"--backends-eu-port=hunter2"},
+		"blob":      {"--backends={eu: {port: hunter2}}"},
+	} {
+		args := args
+
+		t.Run(
+			name, func(t *testing.T) {
+				t.Parallel()
+
+				var pp *Root
+
+				_, err := Fill(&pp, WithCmdlineLayer(WithArgs(args...)))
+				require.Error(t, err)
+				require.ErrorContains(t, err, "got '"+SecretMask+"'")
+				require.NotContains(t, err.Error(), "hunter2")
+
+				for _, diagnostic := range Diagnostics(err) {
+					require.NotContains(t, diagnostic.Message, "hunter2")
+				}
+			},
+		)
+	}
+}
+
+// ctxProvider is a ContextProvider recording the context it reads with.
+type ctxProvider struct {
+	values svalue.Values
+	err    error
+	ctx    context.Context
+}
+
+func (p *ctxProvider) GetName() string {
+	return "ctx"
+}
+
+func (p *ctxProvider) GetStringValuesContext(
+	ctx context.Context,
+) (svalue.Values, error) {
+	p.ctx = ctx
+
+	if p.err != nil {
+		return nil, p.err
+	}
+
+	return p.values, ctx.Err()
+}
+
+func TestFillContext(t *testing.T) {
+	t.Parallel()
+
+	type Root struct {
+		Host *string
+		Port *int
+	}
+
+	type ctxKey struct{}
+
+	t.Run(
+		"success",
+		func(t *testing.T) {
+			t.Parallel()
+
+			provider := &ctxProvider{
+				values: svalue.Values{
+					"host": {Location: "ctx[host]", Value: "db.local"},
+				},
+			}
+
+			ctx := context.WithValue(context.Background(), ctxKey{}, 1)
+
+			var cfg *Root
+
+			locations, err := FillContext(
+				ctx,
+				&cfg,
+				WithCmdlineLayer(WithArgs("--port=5432")),
+				WithContextProvider(provider),
+			)
+			require.NoError(t, err)
+			require.Equal(t, "db.local", *cfg.Host)
+			require.Equal(t, 5432, *cfg.Port)
+			require.Equal(
+				t,
+				"ctx[host]",
+				locationOf(t, locations, "Host").Location,
+			)
+			require.Equal(t, 1, provider.ctx.Value(ctxKey{}))
+		},
+	)
+
+	t.Run(
+		"cancelled",
+		func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			previous := &Root{Host: R("previous")}
+			cfg := previous
+
+			locations, err := FillContext(
+				ctx,
+				&cfg,
+				WithCmdlineLayer(WithArgs("--port=5432")),
+				WithContextProvider(&ctxProvider{}),
+				WithFileLayer(filepath.Join(t.TempDir(), "config.yaml")),
+			)
+			require.ErrorContains(t, err, "layer #1: context canceled")
+			require.ErrorContains(t, err, "layer #2: context canceled")
+			require.NotContains(t, err.Error(), "layer #0")
+			require.Nil(t, locations)
+			require.Same(t, previous, cfg)
+		},
+	)
+
+	t.Run(
+		"deadline",
+		func(t *testing.T) {
+			t.Parallel()
+
+			server := httptest.NewServer(
+				http.HandlerFunc(
+					func(w http.ResponseWriter, r *http.Request) {
+						<-r.Context().Done()
+					},
+				),
+			)
+			defer server.Close()
+
+			ctx, cancel := context.WithTimeout(
+				context.Background(),
+				50*time.Millisecond,
+			)
+			defer cancel()
+
+			var cfg *Root
+
+			_, err := FillContext(
+				ctx,
+				&cfg,
+				WithHTTPLayer(server.URL),
+				WithStructLayer(&Root{Host: R("h"), Port: R(1)}, "defaults"),
+			)
+			require.ErrorContains(t, err, "layer #0: http builder")
+			require.ErrorContains(t, err, context.DeadlineExceeded.Error())
+			require.Nil(t, cfg)
+		},
+	)
+
+	t.Run(
+		"partial results discarded",
+		func(t *testing.T) {
+			t.Parallel()
+
+			previous := &Root{Host: R("previous")}
+			cfg := previous
+
+			locations, err := FillContext(
+				context.Background(),
+				&cfg,
+				WithCmdlineLayer(WithArgs("--host=h")),
+			)
+			require.ErrorContains(t, err, "Port")
+			require.Nil(t, locations)
+			require.Same(t, previous, cfg)
+			require.Equal(t, "previous", *cfg.Host)
+		},
+	)
+
+	t.Run(
+		"provider error",
+		func(t *testing.T) {
+			t.Parallel()
+
+			var cfg *Root
+
+			_, err := FillContext(
+				context.Background(),
+				&cfg,
+				WithStrictContextProvider(
+					&ctxProvider{err: errors.New("unreachable")},
+				),
+			)
+			require.ErrorContains(t, err, "layer #0: ctx: unreachable")
+		},
+	)
+}
+
+// byteSize is a size in bytes decoded from 512MiB like values.
+type byteSize int64
+
+var errInvalidByteSize = errors.New("invalid byte size")
+
+//nolint:gochecknoglobals // registration must happen once
+var registerByteSize sync.Once
+
+func parseByteSize(text string) (byteSize, error) {
+	units := []struct {
+		suffix string
+		factor int64
+	}{
+		{"GiB", 1 << 30},
+		{"MiB", 1 << 20},
+		{"KiB", 1 << 10},
+		{"B", 1},
+	}
+
+	for _, unit := range units {
+		number, found := strings.CutSuffix(text, unit.suffix)
+		if !found {
+			continue
+		}
+
+		n, err := strconv.ParseInt(number, 10, 64)
+		if err != nil {
+			break
+		}
+
+		return byteSize(n * unit.factor), nil
+	}
+
+	return 0, fmt.Errorf("%q: %w", text, errInvalidByteSize)
+}
+
+func TestFill_decoders(t *testing.T) {
+	t.Parallel()
+
+	registerByteSize.Do(
+		func() {
+			registry.RegisterDecoder(parseByteSize)
+		},
+	)
+
+	type Root struct {
+		IP       net.IP
+		Network  *netip.Prefix
+		Pattern  *regexp.Regexp
+		Big      *big.Int
+		Level    *slog.Level
+		Cache    *byteSize
+		Limits   map[string]byteSize
+		Sizes    []byteSize
+		Timeout  *time.Duration
+		Replicas *int
+	}
+
+	var cfg *Root
+
+	_, err := Fill(
+		&cfg,
+		WithCmdlineLayer(
+			WithArgs(
+				"--ip=10.0.0.1",
+				"--network=10.0.0.0/8",
+				"--pattern=^a+$",
+				"--big=123456789012345678901234567890",
+				"--level=warn",
+				"--sizes=[1KiB, 2MiB]",
+				"--timeout=5s",
+				"--replicas=3",
+			),
+		),
+		WithEnvLayer(
+			"APP",
+			WithEnviron(
+				map[string]string{
+					"APP-CACHE":         "512MiB",
+					"APP-LIMITS-UPLOAD": "2KiB",
+				},
+			),
+		),
+	)
+	require.NoError(t, err)
+	require.Equal(t, net.ParseIP("10.0.0.1"), cfg.IP)
+	require.Equal(t, netip.MustParsePrefix("10.0.0.0/8"), *cfg.Network)
+	require.True(t, cfg.Pattern.MatchString("aaa"))
+	require.Equal(t, "123456789012345678901234567890", cfg.Big.String())
+	require.Equal(t, slog.LevelWarn, *cfg.Level)
+	require.Equal(t, byteSize(512<<20), *cfg.Cache)
+	require.Equal(t, map[string]byteSize{"upload": 2 << 10}, cfg.Limits)
+	require.Equal(t, []byteSize{1 << 10, 2 << 20}, cfg.Sizes)
+	require.Equal(t, 5*time.Second, *cfg.Timeout)
+	require.Equal(t, 3, *cfg.Replicas)
+
+	type Sizes struct {
+		Cache   *byteSize
+		Network *netip.Prefix
+		Sizes   []byteSize
+	}
+
+	var sizes *Sizes
+
+	_, err = Fill(
+		&sizes,
+		WithCmdlineLayer(
+			WithArgs(
+				"--cache=lots",
+				"--network=10.0.0.0/64",
+				"--sizes=[1KiB, lots]",
+			),
+		),
+	)
+
+	var parseErr ParseError
+
+	require.ErrorAs(t, err, &parseErr)
+	require.ErrorContains(
+		t,
+		err,
+		`parse error on Cache-<*github.com/byte4ever/dsco/dsco.byteSize> `+
+			`cmdline[--cache]: expected dsco.byteSize, got 'lots': `+
+			`"lots": invalid byte size`,
+	)
+	require.ErrorContains(
+		t,
+		err,
+		`cmdline[--network]: expected netip.Prefix, got '10.0.0.0/64': `+
+			`netip.ParsePrefix("10.0.0.0/64"): prefix length out of range`,
+	)
+	require.ErrorContains(
+		t,
+		err,
+		`cmdline[--sizes]: expected list, got '[1KiB, lots]': `+
+			`item 1: "lots": invalid byte size`,
+	)
+
+	type Secrets struct {
+		Network *netip.Prefix       `dsco:"secret"`
+		Limits  map[string]byteSize `dsco:"secret"`
+	}
+
+	var secrets *Secrets
+
+	_, err = Fill(
+		&secrets,
+		WithCmdlineLayer(
+			WithArgs("--network=10.1.2.3/64", "--limits-upload=lots"),
+		),
+	)
+	require.ErrorContains(
+		t,
+		err,
+		"cmdline[--network]: expected netip.Prefix, got '******'",
+	)
+	require.ErrorContains(
+		t,
+		err,
+		"cmdline[--limits-upload]: expected dsco.byteSize, got '******'",
+	)
+	require.NotContains(t, err.Error(), "10.1.2.3")
+	require.NotContains(t, err.Error(), "lots")
+}
@@ -0,0 +1,85 @@
+package dsco
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"reflect"
+	"sort"
+
+	"github.com/byte4ever/dsco/hit/hprovider"
+)
+
+// fingerprintPoolSize is the number of hash instances kept for reuse by
+// Fingerprint.
+const fingerprintPoolSize = 8
+
+//nolint:gochecknoglobals // shared hash pool
+var fingerprintHashes = hprovider.New(
+	func() hash.Hash { return sha256.New() },
+	fingerprintPoolSize,
+)
+
+// ConfigFingerprint is a deterministic content hash of a configuration. Root
+// changes when any value changes and Subtrees holds the hash of every
+// struct, collection and leaf by path, so two fingerprints tell which
+// subtree changed. Hashes are hex encoded SHA-256 Merkle tree hashes.
+//
+// Secret values are part of Root but their subtrees are not listed.
+type ConfigFingerprint struct {
+	Subtrees map[string]string
+	Root     string
+}
+
+// Fingerprint returns the fingerprint of cfg, a *T filled by Fill. It is
+// suitable to log a configuration version or detect drift between replicas.
+func Fingerprint(cfg any) (*ConfigFingerprint, error) {
+	const errCtx = "computing fingerprint"
+
+	mdl, err := buildModel(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", errCtx, err)
+	}
+
+	hashes := mdl.Fingerprint(fingerprintHashes, reflect.ValueOf(cfg))
+
+	result := &ConfigFingerprint{
+		Root:     hex.EncodeToString(hashes[""]),
+		Subtrees: make(map[string]string, len(hashes)-1),
+	}
+
+	for path, sum := range hashes {
+		if path != "" {
+			result.Subtrees[path] = hex.EncodeToString(sum)
+		}
+	}
+
+	return result, nil
+}
+
+// Changed returns the sorted paths whose hashes differ from other,
+// including paths present in one fingerprint only.
+func (f *ConfigFingerprint) Changed(other *ConfigFingerprint) []string {
+	var paths []string
+
+	if f.Root == other.Root {
+		return paths
+	}
+
+	for path, sum := range f.Subtrees {
+		if otherSum, found := other.Subtrees[path]; !found || otherSum != sum {
+			paths = append(paths, path)
+		}
+	}
+
+	for path := range other.Subtrees {
+		if _, found := f.Subtrees[path]; !found {
+			paths = append(paths, path)
+		}
+	}
+
+	sort.Strings(paths)
+
+	return paths
+}
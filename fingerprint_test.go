@@ -0,0 +1,65 @@
+package dsco
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fingerprintServer struct {
+	Host *string
+	Port *int
+}
+
+type fingerprintConfig struct {
+	Server   *fingerprintServer
+	Password *string `dsco:"secret"`
+}
+
+func TestFingerprint(t *testing.T) {
+	t.Parallel()
+
+	newConfig := func(port int) *fingerprintConfig {
+		return &fingerprintConfig{
+			Server: &fingerprintServer{
+				Host: R("localhost"),
+				Port: R(port),
+			},
+			Password: R("secret"),
+		}
+	}
+
+	reference, err := Fingerprint(newConfig(80))
+	require.NoError(t, err)
+	require.Len(t, reference.Root, 64)
+	require.Len(t, reference.Subtrees, 3)
+	require.NotContains(t, reference.Subtrees, "Password")
+
+	same, err := Fingerprint(newConfig(80))
+	require.NoError(t, err)
+	require.Equal(t, reference, same)
+	require.Empty(t, reference.Changed(same))
+
+	other, err := Fingerprint(newConfig(81))
+	require.NoError(t, err)
+	require.NotEqual(t, reference.Root, other.Root)
+	require.Equal(
+		t,
+		[]string{"Server", "Server.Port"},
+		reference.Changed(other),
+	)
+
+	withoutServer := newConfig(80)
+	withoutServer.Server = nil
+
+	partial, err := Fingerprint(withoutServer)
+	require.NoError(t, err)
+	require.Equal(
+		t,
+		[]string{"Server", "Server.Host", "Server.Port"},
+		partial.Changed(reference),
+	)
+
+	_, err = Fingerprint(fingerprintConfig{})
+	require.ErrorIs(t, err, ErrCfgMustBePointer)
+}
@@ -120,17 +120,30 @@ func NewIntNode(
 	}
 }
 
-type parentNode struct {
+// ParentNode represents a hash tree node combining the hashes of its
+// children. The combination is order independent, children must be keyed
+// when their position matters.
+type ParentNode struct {
 	nodeImpl
 	children []MerkelNode
 }
 
-func newParentNode(
+// NewParentNode creates a new ParentNode over the specified children and
+// computes its hash.
+//
+// Parameters:
+//   - hashProvider: Provider for hash instances to avoid allocation overhead
+//   - salt: Random bytes to prevent hash collision attacks
+//   - id: Unique identifier for this node
+//   - node: The children nodes
+//
+// Returns a new ParentNode with computed hash value.
+func NewParentNode(
 	hashProvider HashProvider[hash.Hash],
 	salt []byte,
 	id string,
 	node ...MerkelNode,
-) *parentNode {
+) *ParentNode {
 	h := hashProvider.Get()
 	defer hashProvider.PutBack(h)
 
@@ -160,7 +173,7 @@ func newParentNode(
 
 	accumSig = h.Sum(accumSig)
 
-	return &parentNode{
+	return &ParentNode{
 		nodeImpl: nodeImpl{
 			id:   id,
 			hash: accumSig,
@@ -224,19 +237,32 @@ func NewStringNode(
 	}
 }
 
-type keyedNode struct {
+// KeyedNode represents a hash tree node binding a value node to a key node
+// (i.e. a field name or a slice index).
+type KeyedNode struct {
 	key   MerkelNode
 	value MerkelNode
 	nodeImpl
 }
 
-func newkeyedNode(
+// NewKeyedNode creates a new KeyedNode binding value to key and computes its
+// hash.
+//
+// Parameters:
+//   - hashProvider: Provider for hash instances to avoid allocation overhead
+//   - salt: Random bytes to prevent hash collision attacks
+//   - id: Unique identifier for this node
+//   - key: The key node
+//   - value: The value node
+//
+// Returns a new KeyedNode with computed hash value.
+func NewKeyedNode(
 	hashProvider HashProvider[hash.Hash],
 	salt []byte,
 	id string,
 	key MerkelNode,
 	value MerkelNode,
-) *keyedNode {
+) *KeyedNode {
 	h := hashProvider.Get()
 	defer hashProvider.PutBack(h)
 
@@ -260,7 +286,7 @@ func newkeyedNode(
 
 	hashSig = h.Sum(hashSig)
 
-	return &keyedNode{
+	return &KeyedNode{
 		key:   key,
 		value: value,
 		nodeImpl: nodeImpl{
@@ -16,7 +16,7 @@ func TestNewIntNode(t *testing.T) {
 	hp := hprovider.New(md5.New, 100)
 
 	for i := 0; i < 10; i++ {
-		k1 := newkeyedNode(
+		k1 := NewKeyedNode(
 			hp,
 			nil,
 			"k1",
@@ -36,7 +36,7 @@ func TestNewIntNode(t *testing.T) {
 		nodes = append(nodes, k1)
 	}
 
-	p := newParentNode(
+	p := NewParentNode(
 		hp,
 		nil,
 		"parent",
@@ -59,7 +59,7 @@ func TestNewStringNode(t *testing.T) {
 	}
 
 	for key, val := range m {
-		k1 := newkeyedNode(
+		k1 := NewKeyedNode(
 			hp,
 			nil,
 			"k1",
@@ -79,7 +79,7 @@ func TestNewStringNode(t *testing.T) {
 		nodes = append(nodes, k1)
 	}
 
-	p := newParentNode(
+	p := NewParentNode(
 		hp,
 		nil,
 		"parent",
@@ -124,8 +124,8 @@ func TestGetID(t *testing.T) {
 		t.Errorf("Expected ID 'string-node-456', got '%s'", stringNode.GetID())
 	}
 
-	// Test keyedNode GetID method.
-	keyNode := newkeyedNode(
+	// Test KeyedNode GetID method.
+	keyNode := NewKeyedNode(
 		hp,
 		nil,
 		"keyed-node-789",
@@ -146,8 +146,8 @@ func TestGetID(t *testing.T) {
 		t.Errorf("Expected ID 'keyed-node-789', got '%s'", keyNode.GetID())
 	}
 
-	// Test parentNode GetID method.
-	parentNode := newParentNode(
+	// Test ParentNode GetID method.
+	parentNode := NewParentNode(
 		hp,
 		nil,
 		"parent-node-000",
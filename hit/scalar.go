@@ -0,0 +1,149 @@
+package hit
+
+import (
+	"encoding/binary"
+	"hash"
+	"math"
+)
+
+var (
+	saltBool  = []byte("bool-0c1f6f4e-9d3b-4c52-8a8e-5f0f3b7d2e61")
+	saltUint  = []byte("uint-6b2e9a57-41c4-4f0e-b7d9-2a8c1e5f9d03")
+	saltFloat = []byte("float-e87d3c20-5a1b-4b6f-9c44-7d1e0a9b3f85")
+)
+
+// BoolNode represents a hash tree node that contains a boolean value.
+// It implements the MerkelNode interface and provides content-addressable
+// hashing for boolean data.
+type BoolNode struct {
+	nodeImpl      // Embedded base implementation
+	value    bool // The boolean value stored in this node
+}
+
+// NewBoolNode creates a new BoolNode with the specified value and computes
+// its hash.
+//
+// Parameters:
+//   - hashProvider: Provider for hash instances to avoid allocation overhead
+//   - salt: Random bytes to prevent hash collision attacks
+//   - id: Unique identifier for this node
+//   - value: The boolean value to store and hash
+//
+// Returns a new BoolNode with computed hash value.
+func NewBoolNode(
+	hashProvider HashProvider[hash.Hash],
+	salt []byte,
+	id string,
+	value bool,
+) *BoolNode {
+	buf := []byte{0}
+	if value {
+		buf[0] = 1
+	}
+
+	return &BoolNode{
+		value: value,
+		nodeImpl: nodeImpl{
+			id:   id,
+			hash: sumValue(hashProvider, salt, saltBool, buf),
+		},
+	}
+}
+
+// UintNode represents a hash tree node that contains an unsigned integer
+// value. It implements the MerkelNode interface and provides
+// content-addressable hashing for unsigned integer data.
+type UintNode struct {
+	nodeImpl        // Embedded base implementation
+	value    uint64 // The unsigned integer value stored in this node
+}
+
+// NewUintNode creates a new UintNode with the specified value and computes
+// its hash.
+//
+// Parameters:
+//   - hashProvider: Provider for hash instances to avoid allocation overhead
+//   - salt: Random bytes to prevent hash collision attacks
+//   - id: Unique identifier for this node
+//   - value: The unsigned integer value to store and hash
+//
+// Returns a new UintNode with computed hash value.
+func NewUintNode(
+	hashProvider HashProvider[hash.Hash],
+	salt []byte,
+	id string,
+	value uint64,
+) *UintNode {
+	buf := make([]byte, binary.MaxVarintLen64)
+	binary.PutUvarint(buf, value)
+
+	return &UintNode{
+		value: value,
+		nodeImpl: nodeImpl{
+			id:   id,
+			hash: sumValue(hashProvider, salt, saltUint, buf),
+		},
+	}
+}
+
+// FloatNode represents a hash tree node that contains a floating point
+// value. It implements the MerkelNode interface and provides
+// content-addressable hashing for floating point data.
+type FloatNode struct {
+	nodeImpl         // Embedded base implementation
+	value    float64 // The floating point value stored in this node
+}
+
+// NewFloatNode creates a new FloatNode with the specified value and computes
+// its hash. Values are hashed by their IEEE 754 representation.
+//
+// Parameters:
+//   - hashProvider: Provider for hash instances to avoid allocation overhead
+//   - salt: Random bytes to prevent hash collision attacks
+//   - id: Unique identifier for this node
+//   - value: The floating point value to store and hash
+//
+// Returns a new FloatNode with computed hash value.
+func NewFloatNode(
+	hashProvider HashProvider[hash.Hash],
+	salt []byte,
+	id string,
+	value float64,
+) *FloatNode {
+	buf := make([]byte, 8) //nolint:mnd // size of a float64
+	binary.BigEndian.PutUint64(buf, math.Float64bits(value))
+
+	return &FloatNode{
+		value: value,
+		nodeImpl: nodeImpl{
+			id:   id,
+			hash: sumValue(hashProvider, salt, saltFloat, buf),
+		},
+	}
+}
+
+// sumValue hashes buf surrounded by salt and the type salt, like the
+// IntNode and StringNode hashes.
+func sumValue(
+	hashProvider HashProvider[hash.Hash],
+	salt, typeSalt, buf []byte,
+) []byte {
+	h := hashProvider.Get()
+	defer hashProvider.PutBack(h)
+
+	sig := make(
+		[]byte,
+		0,
+		h.Size(),
+	)
+
+	h.Write(salt)
+	h.Write(typeSalt)
+	h.Write(salt)
+	h.Write(buf)
+	h.Write(salt)
+	h.Write(typeSalt)
+	h.Write(salt)
+
+	return h.Sum(sig)
+}
@@ -0,0 +1,58 @@
+package hit
+
+import (
+	"crypto/sha256"
+	"hash"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/byte4ever/dsco/hit/hprovider"
+)
+
+func TestScalarNodes(t *testing.T) {
+	t.Parallel()
+
+	hp := hprovider.New(func() hash.Hash { return sha256.New() }, 4)
+
+	t.Run("bool", func(t *testing.T) {
+		t.Parallel()
+
+		n := NewBoolNode(hp, nil, "b", true)
+
+		assert.Equal(t, "b", n.GetID())
+		assert.Equal(t, n.GetHash(), NewBoolNode(hp, nil, "x", true).GetHash())
+		assert.NotEqual(t, n.GetHash(), NewBoolNode(hp, nil, "b", false).GetHash())
+	})
+
+	t.Run("uint", func(t *testing.T) {
+		t.Parallel()
+
+		n := NewUintNode(hp, nil, "u", 42)
+
+		assert.Equal(t, "u", n.GetID())
+		assert.Equal(t, n.GetHash(), NewUintNode(hp, nil, "u", 42).GetHash())
+		assert.NotEqual(t, n.GetHash(), NewUintNode(hp, nil, "u", 43).GetHash())
+		assert.NotEqual(t, n.GetHash(), NewIntNode(hp, nil, "u", 42).GetHash())
+	})
+
+	t.Run("float", func(t *testing.T) {
+		t.Parallel()
+
+		n := NewFloatNode(hp, nil, "f", 0.5)
+
+		assert.Equal(t, "f", n.GetID())
+		assert.Equal(t, n.GetHash(), NewFloatNode(hp, nil, "f", 0.5).GetHash())
+		assert.NotEqual(t, n.GetHash(), NewFloatNode(hp, nil, "f", 0.25).GetHash())
+	})
+
+	t.Run("salt", func(t *testing.T) {
+		t.Parallel()
+
+		assert.NotEqual(
+			t,
+			NewBoolNode(hp, []byte("a"), "b", true).GetHash(),
+			NewBoolNode(hp, []byte("b"), "b", true).GetHash(),
+		)
+	})
+}
@@ -0,0 +1,85 @@
+package dsco
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/byte4ever/dsco/internal/remote"
+)
+
+// ErrHTTPOnlyOption represents an error where an HTTP option is used by a
+// layer that is not an HTTP layer.
+var ErrHTTPOnlyOption = errors.New("option is only supported by http layers")
+
+// HTTPOption is a processing option only supported by HTTP layers.
+type HTTPOption interface {
+	Option
+	httpOption() remote.Option
+}
+
+type httpOption remote.Option
+
+func (httpOption) apply(*internalOpts) error {
+	return ErrHTTPOnlyOption
+}
+
+func (o httpOption) httpOption() remote.Option {
+	return remote.Option(o)
+}
+
+// WithHTTPClient makes the HTTP layer send its requests with client instead
+// of http.DefaultClient, i.e. to set TLS or proxy settings.
+func WithHTTPClient(client *http.Client) HTTPOption {
+	return httpOption(remote.WithClient(client))
+}
+
+// WithHTTPTimeout sets the timeout of each HTTP fetch attempt, 30 seconds by
+// default. A zero timeout disables it.
+func WithHTTPTimeout(timeout time.Duration) HTTPOption {
+	return httpOption(remote.WithTimeout(timeout))
+}
+
+// WithHTTPRetries retries a failed HTTP fetch up to retries times, the first
+// retry waiting backoff and every next one twice as long. Network errors,
+// 429 and 5xx statuses are retried.
+func WithHTTPRetries(retries int, backoff time.Duration) HTTPOption {
+	return httpOption(remote.WithRetries(retries, backoff))
+}
+
+// WithBearerToken authenticates HTTP requests with the bearer token.
+func WithBearerToken(token string) HTTPOption {
+	return httpOption(remote.WithBearerToken(token))
+}
+
+// WithBasicAuth authenticates HTTP requests with basic authentication.
+func WithBasicAuth(username, password string) HTTPOption {
+	return httpOption(remote.WithBasicAuth(username, password))
+}
+
+// WithHTTPCacheFile stores every fetched document in the file at path.
+// When a fetch fails, the layer provides the values of this last known good
+// document instead.
+func WithHTTPCacheFile(path string) HTTPOption {
+	return httpOption(remote.WithCacheFile(path))
+}
+
+// splitHTTPOptions separates HTTP provider options from string based builder
+// options.
+func splitHTTPOptions(options []Option) ([]remote.Option, []Option) {
+	var (
+		httpOptions    []remote.Option
+		builderOptions []Option
+	)
+
+	for _, option := range options {
+		if ho, ok := option.(HTTPOption); ok {
+			httpOptions = append(httpOptions, ho.httpOption())
+			continue
+		}
+
+		builderOptions = append(builderOptions, option)
+	}
+
+	return httpOptions, builderOptions
+}
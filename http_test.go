@@ -0,0 +1,201 @@
+package dsco
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPOption_apply(t *testing.T) {
+	t.Parallel()
+
+	for _, option := range []HTTPOption{
+		WithHTTPClient(http.DefaultClient),
+		WithHTTPTimeout(time.Second),
+		WithHTTPRetries(3, time.Second),
+		WithBearerToken("token"),
+		WithBasicAuth("user", "password"),
+		WithHTTPCacheFile("cache.json"),
+	} {
+		require.ErrorIs(
+			t,
+			option.apply(&internalOpts{}),
+			ErrHTTPOnlyOption,
+		)
+		require.NotNil(t, option.httpOption())
+	}
+}
+
+func Test_splitHTTPOptions(t *testing.T) {
+	t.Parallel()
+
+	aliases := WithAliases(map[string]string{"a": "b"})
+
+	httpOptions, builderOptions := splitHTTPOptions(
+		[]Option{
+			WithHTTPTimeout(time.Second),
+			aliases,
+			WithBearerToken("token"),
+		},
+	)
+
+	require.Len(t, httpOptions, 2)
+	require.Equal(t, []Option{aliases}, builderOptions)
+}
+
+func TestHTTPOption_usedByOtherLayer(t *testing.T) {
+	t.Parallel()
+
+	err := WithCmdlineLayer(WithArgs(), WithBearerToken("token")).
+		register(newLayerBuilder(1))
+
+	require.ErrorIs(t, err, ErrHTTPOnlyOption)
+}
+
+func TestFill_httpLayer(t *testing.T) {
+	t.Parallel()
+
+	type Database struct {
+		Host     *string
+		MaxConns *int
+	}
+
+	type Root struct {
+		Database *Database
+		Timeout  *time.Duration
+	}
+
+	var (
+		requests atomic.Int32
+		down     atomic.Bool
+	)
+
+	server := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				requests.Add(1)
+
+				switch {
+				case down.Load():
+					w.WriteHeader(http.StatusServiceUnavailable)
+				case r.Header.Get("Authorization") != "Bearer token":
+					w.WriteHeader(http.StatusUnauthorized)
+				case r.Header.Get("If-None-Match") == `"v1"`:
+					w.WriteHeader(http.StatusNotModified)
+				default:
+					w.Header().Set("Content-Type", "application/json")
+					w.Header().Set("ETag", `"v1"`)
+					_, _ = w.Write(
+						[]byte(`{
+							"database": {"host": "db.local", "maxConns": 10},
+							"timeout": "5s",
+							"unknown": 1
+						}`),
+					)
+				}
+			},
+		),
+	)
+	defer server.Close()
+
+	cacheFile := filepath.Join(t.TempDir(), "cache.json")
+
+	layer := WithHTTPLayer(
+		server.URL+"/myapp",
+		WithBearerToken("token"),
+		WithHTTPCacheFile(cacheFile),
+		WithHTTPRetries(1, time.Millisecond),
+	)
+
+	var cfg *Root
+
+	locations, err := Fill(&cfg, layer)
+	require.NoError(t, err)
+	require.NoError(t, layer.Stale())
+	require.Equal(t, "db.local", *cfg.Database.Host)
+	require.Equal(t, 10, *cfg.Database.MaxConns)
+	require.Equal(t, 5*time.Second, *cfg.Timeout)
+	require.Equal(
+		t,
+		"http["+server.URL+"/myapp]:/database/maxConns",
+		locationOf(t, locations, "Database.MaxConns").Location,
+	)
+
+	// the layer revalidates its document
+	_, err = Fill(&cfg, layer)
+	require.NoError(t, err)
+	require.Equal(t, "db.local", *cfg.Database.Host)
+
+	// the last known good document is used when the server is down
+	down.Store(true)
+	requests.Store(0)
+
+	cached := WithHTTPLayer(server.URL+"/myapp", WithHTTPCacheFile(cacheFile))
+
+	_, err = Fill(&cfg, cached)
+	require.NoError(t, err)
+	require.Equal(t, 10, *cfg.Database.MaxConns)
+	require.ErrorContains(t, cached.Stale(), "status 503")
+
+	_, err = Fill(
+		&cfg,
+		WithHTTPLayer(server.URL+"/myapp", WithHTTPRetries(1, time.Millisecond)),
+	)
+	require.ErrorContains(t, err, "status 503")
+	require.Equal(t, int32(3), requests.Load())
+
+	down.Store(false)
+
+	_, err = Fill(
+		&cfg,
+		WithStrictHTTPLayer(server.URL+"/myapp", WithBearerToken("token")),
+	)
+	require.ErrorContains(
+		t,
+		err,
+		"unbounded location http["+server.URL+"/myapp]:/unknown",
+	)
+
+	_, err = Fill(
+		&cfg,
+		WithHTTPLayer(server.URL+"/myapp", WithBearerToken("token")),
+		WithHTTPLayer(server.URL+"/myapp", WithBearerToken("token")),
+	)
+	require.ErrorContains(t, err, "http layer #0 is using same url")
+}
+
+func TestStringBasedBuilder_ReportInventory_http(t *testing.T) {
+	t.Parallel()
+
+	type Root struct {
+		Database *struct {
+			MaxConns *int
+		}
+	}
+
+	mdl, err := buildModel(&Root{})
+	require.NoError(t, err)
+
+	builder, err := NewStringBasedBuilderForTest(
+		&stubValuesProvider{},
+		"http",
+		"https://config.local/myapp",
+	)
+	require.NoError(t, err)
+
+	inv, err := builder.ReportInventory(mdl)
+	require.NoError(t, err)
+	require.Equal(t, "http:https://config.local/myapp", inv.Name)
+	require.Equal(
+		t,
+		[]FieldProvision{
+			{FieldUID: "Database.MaxConns", Key: "/database/max_conns"},
+		},
+		inv.Provides,
+	)
+}
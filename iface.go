@@ -1,15 +1,23 @@
 package dsco
 
 import (
+	"context"
+
 	"github.com/byte4ever/dsco/svalue"
 )
 
 // PoliciesGetter defines the ability to retrieve layer policies for
 // configuration processing, converting layers into constraint policies.
 type PoliciesGetter interface {
-	// GetPolicies transforms configuration layers into policy objects
-	// that determine how values are processed and validated.
-	GetPolicies() (constraintLayerPolicies, error)
+	// GetPoliciesContext transforms configuration layers into policy
+	// objects that determine how values are processed and validated, the
+	// sources of context layers being read with ctx. The model, when not
+	// nil, lets layers parse their source according to the field types
+	// (i.e. command line boolean flags).
+	GetPoliciesContext(
+		ctx context.Context,
+		model ModelInterface,
+	) (constraintLayerPolicies, error)
 }
 
 // StringValuesProvider defines the interface for providers that supply
@@ -31,3 +39,16 @@ type NamedStringValuesProvider interface {
 	// used in error messages and debugging output.
 	GetName() string
 }
+
+// ContextProvider defines the interface for named providers reading their
+// string values with the fill context, i.e. from a remote source, so
+// FillContext can bound or cancel them.
+type ContextProvider interface {
+	// GetName returns a human-readable identifier for this provider,
+	// used in error messages and debugging output.
+	GetName() string
+
+	// GetStringValuesContext retrieves all string values provided by this
+	// source, giving up when ctx is done.
+	GetStringValuesContext(ctx context.Context) (svalue.Values, error)
+}
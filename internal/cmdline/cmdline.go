@@ -3,20 +3,107 @@ package cmdline
 import (
 	"fmt"
 	"regexp"
+	"strings"
 
 	"github.com/byte4ever/dsco/svalue"
 )
 
-const locationFmt = "cmdline[--%s]"
+const (
+	longLocationFmt  = "cmdline[--%s]"
+	shortLocationFmt = "cmdline[-%s]"
 
-var re = regexp.MustCompile(
-	`^--([a-z][a-z\d]*(?:[-_][a-z][a-z\d]*)*)=(.+)$`,
+	longPrefix  = "--"
+	shortPrefix = "-"
+	terminator  = "--"
+	negation    = "no-"
+	valueSep    = "="
+	implicit    = "true"
+	negated     = "false"
 )
 
+var (
+	longRe = regexp.MustCompile(
+		`^--([a-z][a-z\d]*(?:[-_](?:[a-z][a-z\d]*|\d+))*)(?:=(.+))?$`,
+	)
+
+	shortRe = regexp.MustCompile(`^-([a-z])(?:=(.+))?$`)
+
+	numberRe = regexp.MustCompile(`^-\.?\d`)
+)
+
+// helpArgs are the arguments requesting the usage text.
+var helpArgs = map[string]struct{}{ //nolint:gochecknoglobals // constant set
+	"--help": {},
+	"-h":     {},
+}
+
 // EntriesProvider is an entries' provider that extract entries from
 // command line.
 type EntriesProvider struct {
-	stringValues svalue.Values
+	stringValues  svalue.Values
+	positionals   []string
+	helpRequested bool
+}
+
+type options struct {
+	isBool      func(key string) bool
+	needsValue  func(key string) bool
+	positionals *[]string
+	help        bool
+	stop        bool
+	strict      bool
+}
+
+// Option is a command line entries' provider option.
+type Option func(opt *options)
+
+// WithHelp makes the provider accept the --help and -h arguments, reported
+// by HelpRequested.
+func WithHelp() Option {
+	return func(opt *options) {
+		opt.help = true
+	}
+}
+
+// WithBoolKeys tells the provider which keys are booleans: --key and -k
+// without value are then true, --no-key is false, and the next argument is
+// never taken as their value.
+func WithBoolKeys(isBool func(key string) bool) Option {
+	return func(opt *options) {
+		opt.isBool = isBool
+	}
+}
+
+// WithValueKeys tells the provider which keys require a value: without
+// one, they are reported as ErrInvalidFormat instead of being true.
+func WithValueKeys(needsValue func(key string) bool) Option {
+	return func(opt *options) {
+		opt.needsValue = needsValue
+	}
+}
+
+// WithPositionals stores the positional arguments in dst once parsed.
+func WithPositionals(dst *[]string) Option {
+	return func(opt *options) {
+		opt.positionals = dst
+	}
+}
+
+// WithStrictPositionals reports the positional arguments as
+// ErrUnexpectedPositional, unless they are stored by WithPositionals.
+func WithStrictPositionals() Option {
+	return func(opt *options) {
+		opt.strict = true
+	}
+}
+
+// WithStopAtPositional stops parsing at the first positional argument: it
+// and all the following arguments are positionals, i.e. a subcommand name
+// and its own options.
+func WithStopAtPositional() Option {
+	return func(opt *options) {
+		opt.stop = true
+	}
 }
 
 // GetStringValues implements svalue.Provider interface.
@@ -24,78 +111,214 @@ func (ep *EntriesProvider) GetStringValues() svalue.Values {
 	return ep.stringValues
 }
 
+// Positionals returns the arguments that are not options, in command line
+// order.
+func (ep *EntriesProvider) Positionals() []string {
+	return ep.positionals
+}
+
+// HelpRequested returns true when the command line holds a help argument
+// and the provider was created with WithHelp.
+func (ep *EntriesProvider) HelpRequested() bool {
+	return ep.helpRequested
+}
+
 // NewEntriesProvider creates an entries' provider that parses and extract
 // parameters from command line.
 //
-// Each command line parameter MUST match regexp '^--([a-z\d_-]+)=(.+)$'.
-// ErrInvalidFormat is returned in such a case.
-func NewEntriesProvider(commandLine []string) (*EntriesProvider, error) {
-	lo := len(commandLine)
+// Options are --key=value, --key value, -k=value or -k value where keys
+// MUST match '^[a-z\d_-]+$' and short keys are a single letter.
+// Without value, an option is set to true and --no-key sets a boolean key
+// to false (see WithBoolKeys), unless it requires a value (see
+// WithValueKeys). Other arguments and all the arguments
+// following "--" are positionals. ErrInvalidFormat is returned for
+// malformed options, and ErrUnexpectedPositional for positionals with
+// WithStrictPositionals. With WithHelp, --help and -h are accepted as well and
+// the other arguments are not checked, as the usage text is shown instead.
+func NewEntriesProvider(
+	commandLine []string,
+	opts ...Option,
+) (*EntriesProvider, error) {
+	opt := &options{
+		isBool:     func(string) bool { return false },
+		needsValue: func(string) bool { return false },
+	}
+
+	for _, o := range opts {
+		o(opt)
+	}
+
+	p := &parser{
+		opt:          opt,
+		args:         commandLine,
+		dedup:        make(map[string]int, len(commandLine)),
+		stringValues: make(svalue.Values, len(commandLine)),
+	}
 
-	if lo == 0 {
+	p.parse()
+
+	if p.helpRequested {
+		return &EntriesProvider{helpRequested: true}, nil
+	}
+
+	if len(p.errs) > 0 {
+		return nil, &ParamError{
+			Positions: p.positions,
+			Errs:      p.errs,
+		}
+	}
+
+	if opt.positionals != nil {
+		*opt.positionals = p.positionals
+	}
+
+	if len(commandLine) == 0 {
 		return &EntriesProvider{}, nil
 	}
 
-	dedup := make(map[string]int, lo)
+	return &EntriesProvider{
+		stringValues: p.stringValues,
+		positionals:  p.positionals,
+	}, nil
+}
 
-	stringValues := make(svalue.Values, lo)
+// parser holds the state of a command line parsing.
+type parser struct {
+	opt           *options
+	dedup         map[string]int
+	stringValues  svalue.Values
+	args          []string
+	positionals   []string
+	errs          []error
+	positions     []int
+	helpRequested bool
+}
 
-	expectedGroups := re.NumSubexp() + 1
+func (p *parser) parse() {
+	for idx := 0; idx < len(p.args); idx++ {
+		arg := p.args[idx]
 
-	var (
-		errs      []error
-		positions []int
-	)
+		_, isHelp := helpArgs[arg]
 
-	for idx, arg := range commandLine {
-		groups := re.FindStringSubmatch(arg)
+		switch {
+		case arg == terminator:
+			p.addPositionals(idx+1, len(p.args))
+			return
 
-		if len(groups) != expectedGroups {
-			errs = append(
-				errs, fmt.Errorf(
-					"arg %q: %w",
-					arg,
-					ErrInvalidFormat,
-				),
-			)
+		case isHelp && p.opt.help:
+			p.helpRequested = true
 
-			positions = append(positions, idx+1)
+		case strings.HasPrefix(arg, longPrefix):
+			idx = p.option(idx, longRe, longLocationFmt)
 
-			continue
+		case len(arg) > 1 && strings.HasPrefix(arg, shortPrefix) &&
+			!numberRe.MatchString(arg):
+			idx = p.option(idx, shortRe, shortLocationFmt)
+
+		case p.opt.stop:
+			p.addPositionals(idx, len(p.args))
+			return
+
+		default:
+			p.addPositionals(idx, idx+1)
 		}
+	}
+}
 
-		key := groups[1]
-		if prevPosition, found := dedup[key]; found {
-			errs = append(
-				errs, fmt.Errorf(
-					"--%s previous found at position #%d: %w",
-					key,
-					prevPosition,
-					ErrDuplicateParam,
-				),
+// addPositionals adds the arguments from index from to index to as
+// positionals.
+func (p *parser) addPositionals(from, to int) {
+	for idx := from; idx < to; idx++ {
+		if p.opt.strict && p.opt.positionals == nil {
+			p.fail(
+				idx,
+				fmt.Errorf("arg %q: %w", p.args[idx], ErrUnexpectedPositional),
 			)
 
-			positions = append(positions, idx+1)
-
 			continue
 		}
 
-		dedup[key] = idx + 1
+		p.positionals = append(p.positionals, p.args[idx])
+	}
+}
+
+// option parses the option at idx with re and returns the index of the
+// last argument it consumes.
+func (p *parser) option(idx int, re *regexp.Regexp, locationFmt string) int {
+	arg := p.args[idx]
+
+	groups := re.FindStringSubmatch(arg)
+	if groups == nil {
+		p.fail(idx, fmt.Errorf("arg %q: %w", arg, ErrInvalidFormat))
+		return idx
+	}
+
+	name, value := groups[1], groups[2]
+	key := name
+	last := idx
 
-		if len(errs) < 1 {
-			stringValues[key] = &svalue.Value{
-				Location: fmt.Sprintf(locationFmt, key),
-				Value:    groups[2],
-			}
+	if !strings.Contains(arg, valueSep) {
+		switch {
+		case p.opt.isBool(key):
+			value = implicit
+		case strings.HasPrefix(key, negation) &&
+			p.opt.isBool(strings.TrimPrefix(key, negation)):
+			key, value = strings.TrimPrefix(key, negation), negated
+		case p.takesNext(idx):
+			last++
+			value = p.args[last]
+		case p.opt.needsValue(key):
+			p.fail(
+				idx,
+				fmt.Errorf("arg %q: value missing: %w", arg, ErrInvalidFormat),
+			)
+
+			return last
+		default:
+			value = implicit
 		}
 	}
 
-	if len(errs) < 1 {
-		return &EntriesProvider{stringValues: stringValues}, nil
+	if prevPosition, found := p.dedup[key]; found {
+		p.fail(
+			idx,
+			fmt.Errorf(
+				"--%s previous found at position #%d: %w",
+				key,
+				prevPosition,
+				ErrDuplicateParam,
+			),
+		)
+
+		return last
+	}
+
+	p.dedup[key] = idx + 1
+
+	if len(p.errs) < 1 {
+		p.stringValues[key] = &svalue.Value{
+			Location: fmt.Sprintf(locationFmt, name),
+			Value:    value,
+		}
 	}
 
-	return nil, &ParamError{
-		Positions: positions,
-		Errs:      errs,
+	return last
+}
+
+// takesNext returns true when the argument following idx is the value of
+// the option at idx: it exists and is not an option nor the terminator.
+func (p *parser) takesNext(idx int) bool {
+	if idx+1 >= len(p.args) {
+		return false
 	}
+
+	next := p.args[idx+1]
+
+	return next == shortPrefix || !strings.HasPrefix(next, shortPrefix) ||
+		numberRe.MatchString(next)
+}
+
+func (p *parser) fail(idx int, err error) {
+	p.errs = append(p.errs, err)
+	p.positions = append(p.positions, idx+1)
 }
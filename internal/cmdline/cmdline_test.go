@@ -19,7 +19,7 @@ func TestProvide(t *testing.T) {
 		arg1        = "--arg1=value1"
 		arg2        = "--arg2=value2"
 		arg3        = "--arg3=value3"
-		invalidArg1 = "invalid_arg1"
+		invalidArg1 = "-invalid_arg1"
 		invalidArg2 = "--asd-_asd=failure"
 	)
 
@@ -138,6 +138,20 @@ func TestProvide(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "success numeric segment",
+			args: args{
+				optionsLine: []string{"--listeners-1-tls=true"},
+			},
+			want: &EntriesProvider{
+				stringValues: svalue.Values{
+					"listeners-1-tls": {
+						Location: "cmdline[--listeners-1-tls]",
+						Value:    "true",
+					},
+				},
+			},
+		},
 		{
 			name: "duplicate params",
 			args: args{
@@ -274,3 +288,249 @@ func TestProvider_GetStringValues(t *testing.T) {
 		p.GetStringValues(),
 	)
 }
+
+func TestNewEntriesProvider_help(t *testing.T) {
+	t.Parallel()
+
+	for _, arg := range []string{"--help", "-h"} {
+		provider, err := NewEntriesProvider(
+			[]string{"--arg1=value1", arg, "-invalid"},
+			WithHelp(),
+		)
+		require.NoError(t, err, arg)
+		require.True(t, provider.HelpRequested(), arg)
+		require.Empty(t, provider.GetStringValues(), arg)
+
+		// without the option, help arguments are regular options
+		provider, err = NewEntriesProvider([]string{arg})
+		require.NoError(t, err, arg)
+		require.False(t, provider.HelpRequested(), arg)
+		require.Len(t, provider.GetStringValues(), 1, arg)
+	}
+
+	provider, err := NewEntriesProvider(
+		[]string{"--arg1=value1"},
+		WithHelp(),
+	)
+	require.NoError(t, err)
+	require.False(t, provider.HelpRequested())
+	require.Equal(
+		t,
+		svalue.Values{
+			"arg1": {Location: "cmdline[--arg1]", Value: "value1"},
+		},
+		provider.GetStringValues(),
+	)
+}
+
+func TestNewEntriesProvider_posix(t *testing.T) {
+	t.Parallel()
+
+	isBool := func(key string) bool {
+		return key == "verbose" || key == "debug" || key == "v"
+	}
+
+	var positionals []string
+
+	provider, err := NewEntriesProvider(
+		[]string{
+			"run",
+			"--host", "localhost",
+			"--verbose", "file.txt",
+			"--no-debug",
+			"-p", "8080",
+			"-v",
+			"--offset", "-12",
+			"--name=a=b",
+			"--flag",
+			"-t=5s",
+			"-",
+			"--",
+			"--port=1",
+			"-x",
+		},
+		WithBoolKeys(isBool),
+		WithPositionals(&positionals),
+	)
+	require.NoError(t, err)
+	require.Equal(
+		t,
+		svalue.Values{
+			"host":    {Location: "cmdline[--host]", Value: "localhost"},
+			"verbose": {Location: "cmdline[--verbose]", Value: "true"},
+			"debug":   {Location: "cmdline[--no-debug]", Value: "false"},
+			"p":       {Location: "cmdline[-p]", Value: "8080"},
+			"v":       {Location: "cmdline[-v]", Value: "true"},
+			"offset":  {Location: "cmdline[--offset]", Value: "-12"},
+			"name":    {Location: "cmdline[--name]", Value: "a=b"},
+			"flag":    {Location: "cmdline[--flag]", Value: "true"},
+			"t":       {Location: "cmdline[-t]", Value: "5s"},
+		},
+		provider.GetStringValues(),
+	)
+
+	want := []string{"run", "file.txt", "-", "--port=1", "-x"}
+	require.Equal(t, want, provider.Positionals())
+	require.Equal(t, want, positionals)
+}
+
+func TestNewEntriesProvider_posixErrors(t *testing.T) {
+	t.Parallel()
+
+	isBool := func(key string) bool { return key == "verbose" }
+
+	for _, tt := range []struct {
+		name      string
+		args      []string
+		positions []int
+		errs      []error
+	}{
+		{
+			name:      "negated bool twice",
+			args:      []string{"--verbose", "--no-verbose"},
+			positions: []int{2},
+			errs:      []error{ErrDuplicateParam},
+		},
+		{
+			name:      "short and long",
+			args:      []string{"-abc", "--Host", "x"},
+			positions: []int{1, 2},
+			errs:      []error{ErrInvalidFormat, ErrInvalidFormat},
+		},
+		{
+			name:      "empty value",
+			args:      []string{"--host="},
+			positions: []int{1},
+			errs:      []error{ErrInvalidFormat},
+		},
+	} {
+		_, err := NewEntriesProvider(tt.args, WithBoolKeys(isBool))
+
+		var pe *ParamError
+
+		require.ErrorAs(t, err, &pe, tt.name)
+		require.Equal(t, tt.positions, pe.Positions, tt.name)
+
+		for i, want := range tt.errs {
+			require.ErrorIs(t, pe.Errs[i], want, tt.name)
+		}
+	}
+
+	// a value is never taken for a bool key
+	provider, err := NewEntriesProvider(
+		[]string{"--no-host", "--verbose", "x"},
+		WithBoolKeys(isBool),
+	)
+	require.NoError(t, err)
+	require.Equal(
+		t,
+		svalue.Values{
+			"no-host": {Location: "cmdline[--no-host]", Value: "true"},
+			"verbose": {Location: "cmdline[--verbose]", Value: "true"},
+		},
+		provider.GetStringValues(),
+	)
+	require.Equal(t, []string{"x"}, provider.Positionals())
+}
+
+func TestNewEntriesProvider_valueKeys(t *testing.T) {
+	t.Parallel()
+
+	needsValue := func(key string) bool { return key == "port" }
+
+	_, err := NewEntriesProvider(
+		[]string{"--port", "--verbose"},
+		WithValueKeys(needsValue),
+	)
+
+	var pe *ParamError
+
+	require.ErrorAs(t, err, &pe)
+	require.Equal(t, []int{1}, pe.Positions)
+	require.ErrorIs(t, pe.Errs[0], ErrInvalidFormat)
+
+	// other keys without value are true
+	provider, err := NewEntriesProvider(
+		[]string{"--port", "80", "--verbose"},
+		WithValueKeys(needsValue),
+	)
+	require.NoError(t, err)
+	require.Equal(
+		t,
+		svalue.Values{
+			"port":    {Location: "cmdline[--port]", Value: "80"},
+			"verbose": {Location: "cmdline[--verbose]", Value: "true"},
+		},
+		provider.GetStringValues(),
+	)
+}
+
+func TestNewEntriesProvider_strictPositionals(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewEntriesProvider(
+		[]string{"a", "--port=1", "--", "b"},
+		WithStrictPositionals(),
+	)
+
+	var pe *ParamError
+
+	require.ErrorAs(t, err, &pe)
+	require.Equal(t, []int{1, 4}, pe.Positions)
+	require.ErrorIs(t, pe.Errs[0], ErrUnexpectedPositional)
+	require.ErrorIs(t, pe.Errs[1], ErrUnexpectedPositional)
+
+	var positionals []string
+
+	_, err = NewEntriesProvider(
+		[]string{"a", "--port=1", "--", "b"},
+		WithStrictPositionals(),
+		WithPositionals(&positionals),
+	)
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "b"}, positionals)
+}
+
+func TestNewEntriesProvider_stopAtPositional(t *testing.T) {
+	t.Parallel()
+
+	isBool := func(key string) bool { return key == "verbose" }
+
+	provider, err := NewEntriesProvider(
+		[]string{
+			"--config", "app.yaml",
+			"--verbose",
+			"serve",
+			"--port=1",
+			"--help",
+			"extra",
+		},
+		WithBoolKeys(isBool),
+		WithStopAtPositional(),
+		WithHelp(),
+	)
+	require.NoError(t, err)
+	require.False(t, provider.HelpRequested())
+	require.Equal(
+		t,
+		svalue.Values{
+			"config":  {Location: "cmdline[--config]", Value: "app.yaml"},
+			"verbose": {Location: "cmdline[--verbose]", Value: "true"},
+		},
+		provider.GetStringValues(),
+	)
+	require.Equal(
+		t,
+		[]string{"serve", "--port=1", "--help", "extra"},
+		provider.Positionals(),
+	)
+
+	provider, err = NewEntriesProvider(
+		[]string{"--verbose", "-h", "serve"},
+		WithBoolKeys(isBool),
+		WithStopAtPositional(),
+		WithHelp(),
+	)
+	require.NoError(t, err)
+	require.True(t, provider.HelpRequested())
+}
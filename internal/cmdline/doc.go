@@ -11,16 +11,28 @@ system.
 
 # Command Line Format
 
-Command line arguments must follow the format: --key=value
-
-	--host=localhost
-	--port=8080
-	--timeout=30s
-	--verbose=true
+Options follow the POSIX long and short forms:
+
+	--host=localhost          # key and value
+	--host localhost          # value in the next argument
+	--verbose                 # no value: true, unless a value is required
+	--no-verbose              # negated bool key: false
+	-p 8080                   # single letter short key, -p=8080 works too
+	--                        # terminator: the next arguments are positionals
+
+The next argument is taken as the value unless it is an option; negative
+numbers are values. The WithBoolKeys option lists the boolean keys: they
+never take the next argument and their --no- form sets them to false.
+WithValueKeys lists the keys requiring a value, reported as
+ErrInvalidFormat without one. Arguments that are not options and all the
+arguments following "--" are positionals, returned by Positionals or stored
+by WithPositionals; WithStrictPositionals reports them as
+ErrUnexpectedPositional when they are not stored.
 
 # Key Format Rules
 
-Keys must match the regular expression: ^--([a-z][a-z\d]*(?:[-_][a-z][a-z\d]*)*)=(.+)$
+Long keys must match the regular expression: ^[a-z][a-z\d]*(?:[-_](?:[a-z][a-z\d]*|\d+))*$
+and short keys are a single lowercase letter.
 
 Valid key examples:
 
@@ -28,13 +40,15 @@ Valid key examples:
 	--max-connections=100     # Kebab-case with hyphens
 	--db_host=localhost       # Snake_case with underscores
 	--api-key-v2=secret       # Mixed alphanumeric
+	--listeners-0-port=8080   # Numeric segment (slice index)
 
 Invalid key examples:
 
 	--Host=value              # Uppercase letters not allowed
 	--123key=value            # Cannot start with digits
 	--=value                  # Empty key
-	host=value                # Missing -- prefix
+	--host=                   # Empty value
+	-abc                      # Several letters after a single dash
 
 # Value Format
 
@@ -45,6 +59,17 @@ unicode. The entire string after the first '=' is treated as the value:
 	--path="/home/user/config file.yaml"
 	--json={"key": "value", "nested": {"array": [1,2,3]}}
 
+# Help
+
+With the WithHelp option, --help and -h are accepted. The other arguments
+are then ignored and HelpRequested returns true, so the caller can show
+the usage text:
+
+	provider, err := cmdline.NewEntriesProvider(args, cmdline.WithHelp())
+	if err == nil && provider.HelpRequested() {
+		// print usage
+	}
+
 # Error Handling
 
 The package provides comprehensive error handling for common issues:
@@ -133,7 +158,8 @@ Example error message:
 
 Each parsed value includes location information for debugging:
 
-	Location format: "cmdline[--key]"
+	Location format: "cmdline[--key]", "cmdline[-k]" for short keys and
+	"cmdline[--no-key]" for negated ones
 
 Example locations:
   - "cmdline[--host]"      # For --host=localhost
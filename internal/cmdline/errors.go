@@ -12,6 +12,10 @@ import (
 // invalid command line option.
 var ErrInvalidFormat = errors.New("invalid format")
 
+// ErrUnexpectedPositional represents an error when creating the provider
+// with a positional argument nothing collects.
+var ErrUnexpectedPositional = errors.New("unexpected positional argument")
+
 // ErrDuplicateParam represents an error when creating the provider with
 // duplicated options in the command line.
 var ErrDuplicateParam = errors.New("duplicate param")
@@ -7,7 +7,7 @@ import (
 
 func Example_newEntriesProvider_1() {
 	// defines params line command. os.Arg[1:] is commonly used.
-	params := []string{"some-thing"}
+	params := []string{"-some-thing"}
 
 	_, err := NewEntriesProvider(params)
 
@@ -15,7 +15,7 @@ func Example_newEntriesProvider_1() {
 	fmt.Println(err)
 	// Output:
 	// when processing invalid params got error:
-	// cmdline issue at position #1: arg "some-thing": invalid format
+	// cmdline issue at position #1: arg "-some-thing": invalid format
 }
 
 func Example_newEntriesProvider_2() {
@@ -16,7 +16,7 @@ PREFIX_SUBKEY=value
 
 Where:
 - PREFIX: Uppercase letters and digits, matching ^[A-Z][A-Z\d]*$
-- SUBKEY: Dash-prefixed key matching ^-[A-Z][A-Z\d]*(?:[-_][A-Z][A-Z\d]*)*$
+- SUBKEY: Dash-prefixed key matching ^-[A-Z][A-Z\d]*(?:[-_](?:[A-Z][A-Z\d]*|\d+))*$
 - value: Any string value
 
 # Examples
@@ -50,6 +50,9 @@ Invalid prefixes:
 	MY-APP        # Hyphens not allowed in prefix
 	MY_APP        # Underscores not allowed in prefix
 
+Lowercase prefixes are accepted by case-insensitive naming schemes, and the
+prefix can be empty.
+
 # Key Format Rules
 
 Environment variable keys (after prefix) must follow specific patterns:
@@ -159,6 +162,36 @@ When environment variable names don't follow the expected pattern:
 		}
 	}
 
+## Explicit Environment
+
+WithEnviron makes the provider scan the KEY=value entries of a function
+instead of os.Environ, i.e. the environment of another process:
+
+	provider, err := env.NewEntriesProvider(
+		"MYAPP",
+		env.WithEnviron(func() []string {
+			return []string{"MYAPP-HOST=localhost"}
+		}),
+	)
+
+## Naming Schemes
+
+WithNaming replaces DefaultNaming, i.e. to parse MYAPP_DATABASE__HOST:
+
+	provider, err := env.NewEntriesProvider(
+		"MYAPP",
+		env.WithNaming(env.Naming{
+			PrefixSeparator: "_",
+			PathSeparator:   "__",
+			WordSeparator:   "_",
+		}),
+	)
+
+Variable names are normalized to the PREFIX-SUB-KEY_WORD form before being
+checked, and ErrInvalidNaming is returned when the path and word
+separators can't be told apart. With an empty prefix, variables that are
+not valid keys are ignored instead of being ambiguous.
+
 ## Multiple Prefixes
 
 Different prefixes can be used for different configuration sections:
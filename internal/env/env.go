@@ -18,7 +18,7 @@ type EntriesProvider struct {
 }
 
 const (
-	reSubKeyExp = `^-[A-Z][A-Z\d]*(?:[-_][A-Z][A-Z\d]*)*$`
+	reSubKeyExp = `^-[A-Z][A-Z\d]*(?:[-_](?:[A-Z][A-Z\d]*|\d+))*$`
 	rePrefixExp = `^[A-Z][A-Z\d]*$`
 )
 
@@ -34,12 +34,29 @@ func getRePrefixed(prefix string) *regexp.Regexp {
 func newProvider(
 	prefix string,
 	environ []string,
+	naming Naming,
+	names map[string]string,
 ) (
 	*EntriesProvider,
 	error,
 ) {
-	// ensure prefix is uppercase
-	if !rePrefix.MatchString(prefix) {
+	if err := naming.validate(); err != nil {
+		return nil, err
+	}
+
+	if naming.CaseInsensitive {
+		prefix = strings.ToUpper(prefix)
+
+		upper := make(map[string]string, len(names))
+		for name, key := range names {
+			upper[strings.ToUpper(name)] = key
+		}
+
+		names = upper
+	}
+
+	// ensure prefix is uppercase, it's optional
+	if prefix != "" && !rePrefix.MatchString(prefix) {
 		return nil, fmt.Errorf(
 			"%q : %w",
 			prefix,
@@ -47,7 +64,7 @@ func newProvider(
 		)
 	}
 
-	stringValues, err := extractStringValues(environ, prefix)
+	stringValues, err := extractStringValues(environ, prefix, naming, names)
 	if err != nil {
 		return nil, err
 	}
@@ -61,11 +78,63 @@ func newProvider(
 	return res, nil
 }
 
+type options struct {
+	environ func() []string
+	names   map[string]string
+	naming  Naming
+}
+
+// Option is an environment entries' provider option.
+type Option func(opt *options)
+
+// WithEnviron makes the provider scan the KEY=value entries returned by
+// environ instead of the process environment, i.e. the environment of
+// another process.
+func WithEnviron(environ func() []string) Option {
+	return func(opt *options) {
+		opt.environ = environ
+	}
+}
+
+// WithNaming makes the provider parse variable names with the naming
+// scheme instead of DefaultNaming.
+func WithNaming(naming Naming) Option {
+	return func(opt *options) {
+		opt.naming = naming
+	}
+}
+
+// WithNames makes the provider read the variables named like the names keys,
+// whatever the prefix, as the names values keys (i.e. DATABASE_URL as
+// database-url).
+func WithNames(names map[string]string) Option {
+	return func(opt *options) {
+		opt.names = names
+	}
+}
+
 // NewEntriesProvider creates an entries provider based on environment variable
 // scanning. It's sensitive to a prefix that *MUST* match this regexp
-// '^[A-Z][A-Z\d]*$'.
-func NewEntriesProvider(prefix string) (*EntriesProvider, error) {
-	return newProvider(prefix, os.Environ())
+// '^[A-Z][A-Z\d]*$' (in any case with a case-insensitive naming). Without
+// prefix, every variable whose name is a valid key is an entry and the
+// others are ignored.
+func NewEntriesProvider(prefix string, opts ...Option) (
+	*EntriesProvider,
+	error,
+) {
+	opt := &options{
+		environ: os.Environ,
+		naming:  DefaultNaming(),
+	}
+
+	for _, o := range opts {
+		o(opt)
+	}
+
+	// the entries are sorted in place
+	environ := append([]string(nil), opt.environ()...)
+
+	return newProvider(prefix, environ, opt.naming, opt.names)
 }
 
 //nolint:gochecknoglobals,goconst // dgas
@@ -77,41 +146,74 @@ func NewEntriesProvider(prefix string) (*EntriesProvider, error) {
 // 	"\\", `\`,
 // ).
 
-func extractStringValues(env []string, prefix string) (
+func extractStringValues(
+	env []string,
+	prefix string,
+	naming Naming,
+	names map[string]string,
+) (
 	svalue.Values, error,
 ) {
 	var ambiguousKeys []string
 
 	stringValues := make(svalue.Values, len(env))
 
+	// add stores the value of the variable name under key, unless another
+	// variable already provides it.
+	add := func(key, name, value string) bool {
+		if _, duplicated := stringValues[key]; duplicated {
+			return false
+		}
+
+		stringValues[key] = &svalue.Value{
+			Location: fmt.Sprintf("env[%s]", name),
+			// Value: replacer.Replace(value),
+			Value: value,
+		}
+
+		return true
+	}
+
 	sort.Strings(env)
 
 	rePrefixed := getRePrefixed(prefix)
 	for _, s := range env {
-		groups := rePrefixed.FindStringSubmatch(s)
-
-		if len(groups) == rePrefixed.NumSubexp()+1 {
-			if reSubKey.MatchString(groups[1]) {
-				stringValues[strings.ToLower(groups[1][1:])] = &svalue.Value{
-					Location: fmt.Sprintf(
-						"env[%s%s]",
-						prefix,
-						groups[1],
-					),
-					// Value: replacer.Replace(groups[2]),
-					Value: groups[2],
-				}
-
-				continue
+		name, value, _ := strings.Cut(s, "=")
+
+		candidate := name
+		if naming.CaseInsensitive {
+			candidate = strings.ToUpper(name)
+		}
+
+		if key, found := names[candidate]; found {
+			if !add(key, name, value) {
+				ambiguousKeys = append(ambiguousKeys, name)
 			}
 
-			ambiguousKeys = append(
-				ambiguousKeys, fmt.Sprintf(
-					"%s%s",
-					prefix,
-					groups[1],
-				),
-			)
+			continue
+		}
+
+		groups := rePrefixed.FindStringSubmatch(candidate + s[len(name):])
+
+		if len(groups) != rePrefixed.NumSubexp()+1 {
+			continue
+		}
+
+		key, found := groups[1], true
+		if prefix != "" {
+			key, found = strings.CutPrefix(key, naming.PrefixSeparator)
+		}
+
+		subKey := "-" + naming.normalize(key)
+
+		if found && reSubKey.MatchString(subKey) &&
+			add(strings.ToLower(subKey[1:]), name, groups[2]) {
+			continue
+		}
+
+		// without prefix, other variables are not meant for the provider
+		if prefix != "" {
+			ambiguousKeys = append(ambiguousKeys, name)
 		}
 	}
 
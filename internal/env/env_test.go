@@ -88,6 +88,15 @@ func TestRegexpKey(t *testing.T) {
 			str:     "-A-B-C",
 			matches: true,
 		},
+		{
+			name:    "",
+			str:     "-LISTENERS-0-PORT",
+			matches: true,
+		},
+		{
+			name: "",
+			str:  "-0-PORT",
+		},
 		{
 			name: "",
 			str:  "--A-B-C",
@@ -381,3 +390,30 @@ func TestProvideKeySyntaxError(
 		},
 	)
 }
+
+func TestNewEntriesProvider_withEnviron(t *testing.T) {
+	t.Parallel()
+
+	environ := []string{
+		"PREFIX-B=2",
+		"PREFIX-A=1",
+		"OTHER-C=3",
+	}
+
+	p, err := NewEntriesProvider(
+		"PREFIX",
+		WithEnviron(func() []string { return environ }),
+	)
+	require.NoError(t, err)
+	require.Equal(
+		t,
+		svalue.Values{
+			"a": {Location: "env[PREFIX-A]", Value: "1"},
+			"b": {Location: "env[PREFIX-B]", Value: "2"},
+		},
+		p.GetStringValues(),
+	)
+
+	// the given entries are left untouched
+	require.Equal(t, "PREFIX-B=2", environ[0])
+}
@@ -15,3 +15,7 @@ var ErrAmbiguousKey = errors.New("is ambiguous")
 // ErrAmbiguousKeys represent an error when multiple env keys  starts with a
 // valid prefix but with invalid syntax.
 var ErrAmbiguousKeys = errors.New("are ambiguous")
+
+// ErrInvalidNaming represents an error when creating the provider with a
+// naming scheme whose variable names can't be parsed back.
+var ErrInvalidNaming = errors.New("invalid naming")
@@ -53,7 +53,12 @@ func FuzzNewEntriesProvider(f *testing.F) {
 				key,
 				value,
 			)
-			p, errs := newProvider(prefix, []string{envKeyVar})
+			p, errs := newProvider(
+				prefix,
+				[]string{envKeyVar},
+				DefaultNaming(),
+				nil,
+			)
 			if p != nil && errs != nil {
 				t.Errorf(
 					"%v %v",
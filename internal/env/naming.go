@@ -0,0 +1,135 @@
+package env
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	pathSep = '-'
+	wordSep = '_'
+
+	// invalidChar replaces the characters that are not allowed in a key so
+	// the normalized key never matches reSubKey.
+	invalidChar = '.'
+)
+
+var reSeparator = regexp.MustCompile(`^[^A-Za-z\d=]+$`)
+
+// Naming is the naming scheme of environment variables. A field path
+// "database.max_conns" under the prefix "MYAPP" is named
+// MYAPP<PrefixSeparator>DATABASE<PathSeparator>MAX<WordSeparator>CONNS.
+type Naming struct {
+	// PrefixSeparator separates the prefix from the key.
+	PrefixSeparator string
+
+	// PathSeparator separates nested field names.
+	PathSeparator string
+
+	// WordSeparator separates the words of a field name.
+	WordSeparator string
+
+	// CaseInsensitive accepts the prefix and keys in any case, they must be
+	// upper case otherwise.
+	CaseInsensitive bool
+}
+
+// DefaultNaming returns the PREFIX-SUB-KEY_WORD naming scheme.
+func DefaultNaming() Naming {
+	return Naming{
+		PrefixSeparator: string(pathSep),
+		PathSeparator:   string(pathSep),
+		WordSeparator:   string(wordSep),
+	}
+}
+
+// validate returns ErrInvalidNaming when a separator is empty, holds letters,
+// digits or '=', or when path and word separators can't be told apart.
+func (n Naming) validate() error {
+	for _, sep := range []string{
+		n.PrefixSeparator,
+		n.PathSeparator,
+		n.WordSeparator,
+	} {
+		if !reSeparator.MatchString(sep) {
+			return fmt.Errorf("separator %q: %w", sep, ErrInvalidNaming)
+		}
+	}
+
+	if n.PathSeparator == n.WordSeparator {
+		return fmt.Errorf(
+			"path and word separators are both %q: %w",
+			n.PathSeparator,
+			ErrInvalidNaming,
+		)
+	}
+
+	return nil
+}
+
+// normalize returns the key in the PREFIX-SUB-KEY_WORD form, characters
+// that are not separators, letters or digits being replaced by an invalid
+// one. The longest separator is matched first.
+func (n Naming) normalize(key string) string {
+	seps := []struct {
+		sep string
+		to  byte
+	}{
+		{n.PathSeparator, pathSep},
+		{n.WordSeparator, wordSep},
+	}
+
+	if len(n.WordSeparator) > len(n.PathSeparator) {
+		seps[0], seps[1] = seps[1], seps[0]
+	}
+
+	var sb strings.Builder
+
+	for i := 0; i < len(key); {
+		matched := false
+
+		for _, s := range seps {
+			if strings.HasPrefix(key[i:], s.sep) {
+				sb.WriteByte(s.to)
+				i += len(s.sep)
+				matched = true
+
+				break
+			}
+		}
+
+		if matched {
+			continue
+		}
+
+		c := key[i]
+		if c == pathSep || c == wordSep {
+			c = invalidChar
+		}
+
+		sb.WriteByte(c)
+		i++
+	}
+
+	if n.CaseInsensitive {
+		return strings.ToUpper(sb.String())
+	}
+
+	return sb.String()
+}
+
+// FormatKey returns the variable name of key, a lower case PREFIX-SUB-KEY_WORD
+// form key.
+func (n Naming) FormatKey(prefix, key string) string {
+	name := strings.NewReplacer(
+		string(pathSep), n.PathSeparator,
+		string(wordSep), n.WordSeparator,
+	).Replace(strings.ToUpper(key))
+
+	if prefix == "" {
+		return name
+	}
+
+	return prefix + n.PrefixSeparator + name
+}
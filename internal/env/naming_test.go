@@ -0,0 +1,214 @@
+package env
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/byte4ever/dsco/svalue"
+)
+
+func shellNaming() Naming {
+	return Naming{
+		PrefixSeparator: "_",
+		PathSeparator:   "__",
+		WordSeparator:   "_",
+	}
+}
+
+func TestNaming_validate(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, DefaultNaming().validate())
+	require.NoError(t, shellNaming().validate())
+
+	for _, naming := range []Naming{
+		{PrefixSeparator: "", PathSeparator: "-", WordSeparator: "_"},
+		{PrefixSeparator: "_", PathSeparator: "X", WordSeparator: "_"},
+		{PrefixSeparator: "_", PathSeparator: "=", WordSeparator: "_"},
+		{PrefixSeparator: "_", PathSeparator: "_", WordSeparator: "_"},
+	} {
+		require.ErrorIs(t, naming.validate(), ErrInvalidNaming)
+	}
+}
+
+func TestNaming_normalize(t *testing.T) {
+	t.Parallel()
+
+	shell := shellNaming()
+
+	require.Equal(t, "DATABASE-MAX_CONNS", shell.normalize("DATABASE__MAX_CONNS"))
+	require.Equal(t, "A-_B", shell.normalize("A___B"))
+	require.Equal(t, "A.B", shell.normalize("A-B"))
+	require.Equal(t, "A-B_C", DefaultNaming().normalize("A-B_C"))
+
+	shell.CaseInsensitive = true
+	require.Equal(t, "DATABASE-HOST", shell.normalize("database__host"))
+}
+
+func TestNaming_FormatKey(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(
+		t,
+		"MYAPP_DATABASE__MAX_CONNS",
+		shellNaming().FormatKey("MYAPP", "database-max_conns"),
+	)
+	require.Equal(
+		t,
+		"DATABASE__MAX_CONNS",
+		shellNaming().FormatKey("", "database-max_conns"),
+	)
+	require.Equal(
+		t,
+		"MYAPP-DATABASE-MAX_CONNS",
+		DefaultNaming().FormatKey("MYAPP", "database-max_conns"),
+	)
+}
+
+func TestNewEntriesProvider_withNaming(t *testing.T) {
+	t.Parallel()
+
+	environ := func(entries ...string) Option {
+		return WithEnviron(func() []string { return entries })
+	}
+
+	p, err := NewEntriesProvider(
+		"MYAPP",
+		WithNaming(shellNaming()),
+		environ(
+			"MYAPP_DATABASE__MAX_CONNS=10",
+			"MYAPP_TIMEOUT=1s",
+			"OTHER_HOST=h",
+		),
+	)
+	require.NoError(t, err)
+	require.Equal(
+		t,
+		svalue.Values{
+			"database-max_conns": {
+				Location: "env[MYAPP_DATABASE__MAX_CONNS]",
+				Value:    "10",
+			},
+			"timeout": {Location: "env[MYAPP_TIMEOUT]", Value: "1s"},
+		},
+		p.GetStringValues(),
+	)
+
+	_, err = NewEntriesProvider(
+		"MYAPP",
+		WithNaming(shellNaming()),
+		environ("MYAPP-HOST=h", "MYAPP_A-B=1"),
+	)
+	require.ErrorIs(t, err, ErrAmbiguousKeys)
+	require.ErrorContains(t, err, "MYAPP-HOST")
+	require.ErrorContains(t, err, "MYAPP_A-B")
+
+	_, err = NewEntriesProvider(
+		"MYAPP",
+		WithNaming(Naming{PathSeparator: "_", WordSeparator: "_"}),
+	)
+	require.ErrorIs(t, err, ErrInvalidNaming)
+}
+
+func TestNewEntriesProvider_caseInsensitive(t *testing.T) {
+	t.Parallel()
+
+	naming := shellNaming()
+	naming.CaseInsensitive = true
+
+	p, err := NewEntriesProvider(
+		"myapp",
+		WithNaming(naming),
+		WithEnviron(
+			func() []string {
+				return []string{"myapp_database__host=h", "MYAPP_DATABASE__HOST=x"}
+			},
+		),
+	)
+	require.ErrorIs(t, err, ErrAmbiguousKey)
+	require.ErrorContains(t, err, "myapp_database__host")
+	require.Nil(t, p)
+
+	p, err = NewEntriesProvider(
+		"myapp",
+		WithNaming(naming),
+		WithEnviron(
+			func() []string {
+				return []string{"myapp_database__host=h"}
+			},
+		),
+	)
+	require.NoError(t, err)
+	require.Equal(
+		t,
+		svalue.Values{
+			"database-host": {Location: "env[myapp_database__host]", Value: "h"},
+		},
+		p.GetStringValues(),
+	)
+}
+
+func TestNewEntriesProvider_noPrefix(t *testing.T) {
+	t.Parallel()
+
+	p, err := NewEntriesProvider(
+		"",
+		WithNaming(shellNaming()),
+		WithEnviron(
+			func() []string {
+				return []string{"PORT=8080", "lower=1", "A-B=2", "DATABASE__HOST=h"}
+			},
+		),
+	)
+	require.NoError(t, err)
+	require.Equal(
+		t,
+		svalue.Values{
+			"port":          {Location: "env[PORT]", Value: "8080"},
+			"database-host": {Location: "env[DATABASE__HOST]", Value: "h"},
+		},
+		p.GetStringValues(),
+	)
+}
+
+func TestNewEntriesProvider_withNames(t *testing.T) {
+	t.Parallel()
+
+	environ := []string{
+		"DATABASE_URL=postgres://db",
+		"MYAPP-PORT=8080",
+		"OTHER=1",
+	}
+
+	p, err := NewEntriesProvider(
+		"MYAPP",
+		WithNames(map[string]string{"DATABASE_URL": "database-url"}),
+		WithEnviron(func() []string { return environ }),
+	)
+	require.NoError(t, err)
+	require.Equal(
+		t,
+		svalue.Values{
+			"database-url": {
+				Location: "env[DATABASE_URL]",
+				Value:    "postgres://db",
+			},
+			"port": {Location: "env[MYAPP-PORT]", Value: "8080"},
+		},
+		p.GetStringValues(),
+	)
+
+	// the generated name of the field is ambiguous
+	_, err = NewEntriesProvider(
+		"MYAPP",
+		WithNames(map[string]string{"DATABASE_URL": "database-url"}),
+		WithEnviron(
+			func() []string {
+				return append(environ, "MYAPP-DATABASE-URL=x")
+			},
+		),
+	)
+	require.ErrorIs(t, err, ErrAmbiguousKey)
+	require.ErrorContains(t, err, "MYAPP-DATABASE-URL")
+}
@@ -0,0 +1,63 @@
+/*
+Package file provides configuration file value extraction for dsco's
+configuration system.
+
+# Overview
+
+The file package implements a provider that reads a single YAML, JSON or
+TOML configuration file and flattens it into the dash separated keys used
+by every string based dsco layer. Unlike a plain unmarshal into a struct,
+each extracted value keeps the exact position it was read from so that
+fill reports point back to the file line and column.
+
+# Supported Formats
+
+The format is deduced from the file extension:
+
+	.yaml, .yml   → YAML
+	.json         → JSON (parsed with the YAML parser, JSON being a subset)
+	.toml         → TOML
+
+Any other extension is rejected with ErrUnsupportedFormat.
+
+# Key Transformation
+
+Nested mappings are flattened, and each key segment is normalized to
+snake case so that the usual naming styles of configuration files all bind
+to the same Go field:
+
+	database:
+	  host: db.local        # → "database-host"
+	  maxConns: 10          # → "database-max_conns"
+	  max-idle: 2           # → "database-max_idle"
+
+Sequences are kept as YAML blobs (they are later unmarshalled into slice
+fields) and null values are ignored, as if the key was not present.
+
+# Locations
+
+Every value location has the following form, line and column being those
+of the key in the document:
+
+	file[/etc/app.yaml]:12:3
+
+# Usage Examples
+
+	provider, err := file.NewEntriesProvider("/etc/myapp/config.yaml")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	values := provider.GetStringValues()
+
+# Error Handling
+
+The provider reports:
+
+  - ErrUnsupportedFormat when the extension is unknown
+  - ErrInvalidRoot when the document root is not a mapping
+  - ErrInvalidKey when a mapping key is not a scalar
+  - DuplicateKeyError when two keys normalize to the same key
+  - wrapped I/O and syntax errors from the underlying parsers
+*/
+package file
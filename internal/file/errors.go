@@ -0,0 +1,42 @@
+package file
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrUnsupportedFormat represents an error when the file extension does not
+// match any supported configuration format.
+var ErrUnsupportedFormat = errors.New("unsupported file format")
+
+// ErrInvalidRoot represents an error when the document root is not a
+// mapping (e.g. a top level sequence or scalar).
+var ErrInvalidRoot = errors.New("document root must be a mapping")
+
+// ErrDuplicateKey represents an error when two keys of the document end up
+// with the same normalized key.
+var ErrDuplicateKey = errors.New("duplicate key")
+
+// ErrInvalidKey represents an error when a mapping key is not a scalar.
+var ErrInvalidKey = errors.New("invalid key")
+
+// DuplicateKeyError represents an error when two document keys are bound to
+// the same normalized key.
+type DuplicateKeyError struct {
+	Key       string
+	Location1 string
+	Location2 string
+}
+
+func (e DuplicateKeyError) Error() string {
+	return fmt.Sprintf(
+		"key %s defined at %s and %s",
+		e.Key,
+		e.Location1,
+		e.Location2,
+	)
+}
+
+func (DuplicateKeyError) Is(err error) bool {
+	return errors.Is(err, ErrDuplicateKey)
+}
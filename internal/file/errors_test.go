@@ -0,0 +1,29 @@
+package file
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDuplicateKeyError_Error(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(
+		t,
+		"key database-host defined at file[a.yaml]:1:1 and file[a.yaml]:4:3",
+		DuplicateKeyError{
+			Key:       "database-host",
+			Location1: "file[a.yaml]:1:1",
+			Location2: "file[a.yaml]:4:3",
+		}.Error(),
+	)
+}
+
+func TestDuplicateKeyError_Is(t *testing.T) {
+	t.Parallel()
+
+	require.ErrorIs(t, DuplicateKeyError{}, ErrDuplicateKey)
+	require.NotErrorIs(t, errors.New("other"), ErrDuplicateKey) //nolint:err113 // test
+}
@@ -0,0 +1,416 @@
+package file
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+	"github.com/pelletier/go-toml/v2/unstable"
+	"github.com/spf13/afero"
+	"gopkg.in/yaml.v3"
+
+	"github.com/byte4ever/dsco/internal/utils"
+	"github.com/byte4ever/dsco/svalue"
+)
+
+const (
+	locationFmt = "file[%s]:%d:%d"
+	keySep      = "-"
+	yamlNullTag = "!!null"
+)
+
+// Format is a supported configuration file format.
+type Format string
+
+const (
+	// FormatYAML is the YAML file format (.yaml, .yml).
+	FormatYAML Format = "yaml"
+
+	// FormatJSON is the JSON file format (.json).
+	FormatJSON Format = "json"
+
+	// FormatTOML is the TOML file format (.toml).
+	FormatTOML Format = "toml"
+)
+
+// EntriesProvider is an entries' provider that extract entries from a YAML,
+// JSON or TOML configuration file.
+type EntriesProvider struct {
+	values svalue.Values
+	name   string
+	path   string
+}
+
+// GetName returns the provider name.
+func (e *EntriesProvider) GetName() string {
+	return e.name
+}
+
+// GetPath returns the path of the configuration file.
+func (e *EntriesProvider) GetPath() string {
+	return e.path
+}
+
+// GetStringValues implements svalue.Provider interface.
+func (e *EntriesProvider) GetStringValues() svalue.Values {
+	return e.values
+}
+
+// NewEntriesProvider creates an entries' provider that parses the
+// configuration file located at path. The format is deduced from the file
+// extension.
+//
+// Nested mapping keys are flattened into dash separated keys (i.e. the
+// "host" key nested in "database" gives "database-host") and every key
+// segment is converted to snake case, so "maxConns", "max-conns" and
+// "max_conns" are all bound to "max_conns". Sequences are kept as YAML
+// blobs and null values are ignored.
+func NewEntriesProvider(path string) (*EntriesProvider, error) {
+	return newProvider(
+		afero.NewReadOnlyFs(afero.NewOsFs()),
+		path,
+	)
+}
+
+func newProvider(fs afero.Fs, path string) (*EntriesProvider, error) {
+	cleanPath := filepath.Clean(path)
+
+	format, err := FormatFromPath(cleanPath)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := afero.ReadFile(fs, cleanPath)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", cleanPath, err)
+	}
+
+	values, err := ParseValues(
+		format,
+		content,
+		func(_ string, line, column int) string {
+			return fmt.Sprintf(locationFmt, cleanPath, line, column)
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", cleanPath, err)
+	}
+
+	return &EntriesProvider{
+		values: values,
+		name:   fmt.Sprintf("file(%s)", cleanPath),
+		path:   cleanPath,
+	}, nil
+}
+
+// Locator returns the location of a value given the JSON pointer of its key
+// in the document (i.e. "/database/host") and the key position.
+type Locator func(pointer string, line, column int) string
+
+// ParseValues parses the content of a document in the format and flattens
+// it like NewEntriesProvider does, value locations being given by locate.
+func ParseValues(
+	format Format,
+	content []byte,
+	locate Locator,
+) (svalue.Values, error) {
+	root, err := parse(format, content)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(svalue.Values)
+
+	if root != nil {
+		if err := flatten(locate, "", "", root, values); err != nil {
+			return nil, err
+		}
+	}
+
+	return values, nil
+}
+
+// FormatFromPath returns the file format matching the path extension.
+func FormatFromPath(path string) (Format, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return FormatYAML, nil
+	case ".json":
+		return FormatJSON, nil
+	case ".toml":
+		return FormatTOML, nil
+	default:
+		return "", fmt.Errorf("%q: %w", path, ErrUnsupportedFormat)
+	}
+}
+
+// parse returns the root mapping node of the document or nil when the
+// document is empty. JSON is a subset of YAML so both share the same
+// parser.
+func parse(format Format, content []byte) (*yaml.Node, error) {
+	if format == FormatTOML {
+		return parseTOML(content)
+	}
+
+	var doc yaml.Node
+
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", format, err)
+	}
+
+	if len(doc.Content) == 0 {
+		return nil, nil //nolint:nilnil // empty document
+	}
+
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, ErrInvalidRoot
+	}
+
+	return root, nil
+}
+
+// parseTOML decodes the TOML document and converts it into a YAML node
+// tree, then decorates every mapping key with its position in the original
+// document.
+func parseTOML(content []byte) (*yaml.Node, error) {
+	var data map[string]any
+
+	if err := toml.Unmarshal(content, &data); err != nil {
+		return nil, fmt.Errorf("parsing toml: %w", err)
+	}
+
+	if len(data) == 0 {
+		return nil, nil //nolint:nilnil // empty document
+	}
+
+	var root yaml.Node
+
+	if err := root.Encode(normalizeTOML(data)); err != nil {
+		return nil, fmt.Errorf("converting toml: %w", err)
+	}
+
+	positions := tomlKeyPositions(content)
+
+	setPositions(&root, nil, positions)
+
+	return &root, nil
+}
+
+// normalizeTOML replaces TOML local date/time values with their textual
+// form so they survive the conversion into YAML nodes.
+func normalizeTOML(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for k, sub := range v {
+			v[k] = normalizeTOML(sub)
+		}
+
+		return v
+	case []any:
+		for i, sub := range v {
+			v[i] = normalizeTOML(sub)
+		}
+
+		return v
+	case time.Time:
+		return v
+	case fmt.Stringer:
+		return v.String()
+	default:
+		return v
+	}
+}
+
+type position struct {
+	line   int
+	column int
+}
+
+// tomlKeyPositions returns the position of every key found in the TOML
+// document, indexed by the joined raw key path.
+func tomlKeyPositions(content []byte) map[string]position {
+	positions := make(map[string]position)
+
+	var (
+		parser unstable.Parser
+		prefix []string
+	)
+
+	parser.Reset(content)
+
+	for parser.NextExpression() {
+		expr := parser.Expression()
+
+		switch expr.Kind { //nolint:exhaustive // only keyed expressions matter
+		case unstable.Table, unstable.ArrayTable:
+			prefix = recordTOMLKey(&parser, positions, nil, expr.Key())
+		case unstable.KeyValue:
+			recordTOMLKeyValue(&parser, positions, prefix, expr)
+		}
+	}
+
+	return positions
+}
+
+func recordTOMLKeyValue(
+	parser *unstable.Parser,
+	positions map[string]position,
+	prefix []string,
+	expr *unstable.Node,
+) {
+	path := recordTOMLKey(parser, positions, prefix, expr.Key())
+
+	if value := expr.Value(); value.Kind == unstable.InlineTable {
+		children := value.Children()
+
+		for children.Next() {
+			recordTOMLKeyValue(parser, positions, path, children.Node())
+		}
+	}
+}
+
+// recordTOMLKey records the position of every (possibly dotted) key part
+// that has not been seen yet and returns the resulting full key path.
+func recordTOMLKey(
+	parser *unstable.Parser,
+	positions map[string]position,
+	prefix []string,
+	keys unstable.Iterator,
+) []string {
+	path := append([]string{}, prefix...)
+
+	for keys.Next() {
+		node := keys.Node()
+		path = append(path, string(node.Data))
+
+		joined := joinPath(path)
+		if _, found := positions[joined]; found {
+			continue
+		}
+
+		start := parser.Shape(node.Raw).Start
+
+		positions[joined] = position{
+			line:   start.Line,
+			column: start.Column,
+		}
+	}
+
+	return path
+}
+
+func setPositions(
+	node *yaml.Node,
+	path []string,
+	positions map[string]position,
+) {
+	switch node.Kind { //nolint:exhaustive // only containers hold keys
+	case yaml.DocumentNode, yaml.SequenceNode:
+		for _, sub := range node.Content {
+			setPositions(sub, path, positions)
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			subPath := append(append([]string{}, path...), key.Value)
+
+			if pos, found := positions[joinPath(subPath)]; found {
+				key.Line, key.Column = pos.line, pos.column
+			}
+
+			setPositions(value, subPath, positions)
+		}
+	}
+}
+
+func joinPath(path []string) string {
+	return strings.Join(path, "\x00")
+}
+
+// NormalizeKey converts a document key segment into the snake case form
+// used by dsco alias paths.
+func NormalizeKey(key string) string {
+	return utils.ToSnakeCase(strings.ReplaceAll(key, "-", "_"))
+}
+
+// pointerEscaper escapes a key as a JSON pointer reference token.
+//
+//nolint:gochecknoglobals // stateless replacer
+var pointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+func flatten(
+	locate Locator,
+	pointer, prefix string,
+	node *yaml.Node,
+	result svalue.Values,
+) error {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+
+		keyPointer := pointer + "/" + pointerEscaper.Replace(key.Value)
+		location := locate(keyPointer, key.Line, key.Column)
+
+		if key.Kind != yaml.ScalarNode {
+			return fmt.Errorf("%s: %w", location, ErrInvalidKey)
+		}
+
+		fullKey := NormalizeKey(key.Value)
+		if prefix != "" {
+			fullKey = prefix + keySep + fullKey
+		}
+
+		if value.Kind == yaml.AliasNode {
+			value = value.Alias
+		}
+
+		switch {
+		case value.Kind == yaml.MappingNode:
+			err := flatten(locate, keyPointer, fullKey, value, result)
+			if err != nil {
+				return err
+			}
+
+			continue
+		case value.Kind == yaml.ScalarNode && value.Tag == yamlNullTag:
+			continue
+		}
+
+		if previous, found := result[fullKey]; found {
+			return DuplicateKeyError{
+				Key:       fullKey,
+				Location1: previous.Location,
+				Location2: location,
+			}
+		}
+
+		raw, err := rawValue(value)
+		if err != nil {
+			return fmt.Errorf("%s: %w", location, err)
+		}
+
+		result[fullKey] = &svalue.Value{
+			Location: location,
+			Value:    raw,
+		}
+	}
+
+	return nil
+}
+
+// rawValue returns the textual value of a leaf node. Scalars are returned
+// verbatim while sequences are serialized back into YAML.
+func rawValue(node *yaml.Node) (string, error) {
+	if node.Kind == yaml.ScalarNode {
+		return node.Value, nil
+	}
+
+	out, err := yaml.Marshal(node)
+	if err != nil {
+		return "", fmt.Errorf("serializing value: %w", err)
+	}
+
+	return string(out), nil
+}
@@ -0,0 +1,302 @@
+package file
+
+import (
+	"os"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+
+	"github.com/byte4ever/dsco/svalue"
+)
+
+func newTestFs(t *testing.T, files map[string]string) afero.Fs {
+	t.Helper()
+
+	fs := afero.NewMemMapFs()
+
+	for name, content := range files {
+		require.NoError(
+			t,
+			afero.WriteFile(fs, name, []byte(content), 0o600),
+		)
+	}
+
+	return fs
+}
+
+func TestNewEntriesProvider(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewEntriesProvider("/this/file/does/not/exist.yaml")
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestFormatFromPath(t *testing.T) {
+	t.Parallel()
+
+	for path, expected := range map[string]Format{
+		"/etc/app.yaml": FormatYAML,
+		"/etc/app.YML":  FormatYAML,
+		"app.json":      FormatJSON,
+		"a/b/app.toml":  FormatTOML,
+	} {
+		format, err := FormatFromPath(path)
+		require.NoError(t, err)
+		require.Equal(t, expected, format)
+	}
+
+	_, err := FormatFromPath("/etc/app.ini")
+	require.ErrorIs(t, err, ErrUnsupportedFormat)
+}
+
+func TestNormalizeKey(t *testing.T) {
+	t.Parallel()
+
+	for key, expected := range map[string]string{
+		"host":           "host",
+		"maxConns":       "max_conns",
+		"max-conns":      "max_conns",
+		"max_conns":      "max_conns",
+		"MaxConnections": "max_connections",
+	} {
+		require.Equal(t, expected, NormalizeKey(key))
+	}
+}
+
+func Test_newProvider(t *testing.T) {
+	t.Parallel()
+
+	t.Run(
+		"yaml",
+		func(t *testing.T) {
+			t.Parallel()
+
+			fs := newTestFs(
+				t, map[string]string{
+					"/etc/app.yaml": "" +
+						"database:\n" +
+						"  host: db.local\n" +
+						"  maxConns: 3\n" +
+						"  proxy: null\n" +
+						"tags:\n" +
+						"  - a\n" +
+						"  - b\n" +
+						"empty: {}\n",
+				},
+			)
+
+			provider, err := newProvider(fs, "/etc/app.yaml")
+			require.NoError(t, err)
+			require.Equal(t, "file(/etc/app.yaml)", provider.GetName())
+			require.Equal(t, "/etc/app.yaml", provider.GetPath())
+			require.Equal(
+				t,
+				svalue.Values{
+					"database-host": {
+						Location: "file[/etc/app.yaml]:2:3",
+						Value:    "db.local",
+					},
+					"database-max_conns": {
+						Location: "file[/etc/app.yaml]:3:3",
+						Value:    "3",
+					},
+					"tags": {
+						Location: "file[/etc/app.yaml]:5:1",
+						Value:    "- a\n- b\n",
+					},
+				},
+				provider.GetStringValues(),
+			)
+		},
+	)
+
+	t.Run(
+		"json",
+		func(t *testing.T) {
+			t.Parallel()
+
+			fs := newTestFs(
+				t, map[string]string{
+					"/app.json": "{\n" +
+						"  \"database\": {\"host\": \"db.local\"},\n" +
+						"  \"ports\": [1, 2]\n" +
+						"}\n",
+				},
+			)
+
+			provider, err := newProvider(fs, "/app.json")
+			require.NoError(t, err)
+			require.Equal(
+				t,
+				svalue.Values{
+					"database-host": {
+						Location: "file[/app.json]:2:16",
+						Value:    "db.local",
+					},
+					"ports": {
+						Location: "file[/app.json]:3:3",
+						Value:    "[1, 2]\n",
+					},
+				},
+				provider.GetStringValues(),
+			)
+		},
+	)
+
+	t.Run(
+		"toml",
+		func(t *testing.T) {
+			t.Parallel()
+
+			fs := newTestFs(
+				t, map[string]string{
+					"/app.toml": "" +
+						"title = 'demo'\n" +
+						"\n" +
+						"[database]\n" +
+						"host = \"db.local\"\n" +
+						"day = 1979-05-27\n" +
+						"pool = { min = 1, max = 10 }\n" +
+						"\n" +
+						"[[servers]]\n" +
+						"port = 80\n",
+				},
+			)
+
+			provider, err := newProvider(fs, "/app.toml")
+			require.NoError(t, err)
+			require.Equal(
+				t,
+				svalue.Values{
+					"title": {
+						Location: "file[/app.toml]:1:1",
+						Value:    "demo",
+					},
+					"database-host": {
+						Location: "file[/app.toml]:4:1",
+						Value:    "db.local",
+					},
+					"database-day": {
+						Location: "file[/app.toml]:5:1",
+						Value:    "1979-05-27",
+					},
+					"database-pool-min": {
+						Location: "file[/app.toml]:6:10",
+						Value:    "1",
+					},
+					"database-pool-max": {
+						Location: "file[/app.toml]:6:19",
+						Value:    "10",
+					},
+					"servers": {
+						Location: "file[/app.toml]:8:3",
+						Value:    "- port: 80\n",
+					},
+				},
+				provider.GetStringValues(),
+			)
+		},
+	)
+
+	t.Run(
+		"empty documents",
+		func(t *testing.T) {
+			t.Parallel()
+
+			fs := newTestFs(
+				t, map[string]string{
+					"/app.yaml": "",
+					"/app.toml": "# nothing here\n",
+				},
+			)
+
+			for _, path := range []string{"/app.yaml", "/app.toml"} {
+				provider, err := newProvider(fs, path)
+				require.NoError(t, err)
+				require.Empty(t, provider.GetStringValues())
+			}
+		},
+	)
+
+	t.Run(
+		"failures",
+		func(t *testing.T) {
+			t.Parallel()
+
+			fs := newTestFs(
+				t, map[string]string{
+					"/bad.yaml":     "a: [",
+					"/bad.toml":     "a = ",
+					"/list.yaml":    "- a\n- b\n",
+					"/complex.yaml": "? [a, b]\n: c\n",
+					"/dup.yaml":     "maxConns: 1\nmax_conns: 2\n",
+					"/app.ini":      "a=b",
+				},
+			)
+
+			_, err := newProvider(fs, "/bad.yaml")
+			require.ErrorContains(t, err, "parsing yaml")
+
+			_, err = newProvider(fs, "/bad.toml")
+			require.ErrorContains(t, err, "parsing toml")
+
+			_, err = newProvider(fs, "/list.yaml")
+			require.ErrorIs(t, err, ErrInvalidRoot)
+
+			_, err = newProvider(fs, "/complex.yaml")
+			require.ErrorIs(t, err, ErrInvalidKey)
+
+			_, err = newProvider(fs, "/dup.yaml")
+
+			var dupErr DuplicateKeyError
+
+			require.ErrorAs(t, err, &dupErr)
+			require.Equal(
+				t,
+				DuplicateKeyError{
+					Key:       "max_conns",
+					Location1: "file[/dup.yaml]:1:1",
+					Location2: "file[/dup.yaml]:2:1",
+				},
+				dupErr,
+			)
+
+			_, err = newProvider(fs, "/app.ini")
+			require.ErrorIs(t, err, ErrUnsupportedFormat)
+
+			_, err = newProvider(fs, "/missing.yaml")
+			require.ErrorIs(t, err, os.ErrNotExist)
+		},
+	)
+}
+
+func TestParseValues(t *testing.T) {
+	t.Parallel()
+
+	values, err := ParseValues(
+		FormatJSON,
+		[]byte(`{"database": {"max-conns": 10, "a/b~c": "x"}, "empty": null}`),
+		func(pointer string, _, _ int) string {
+			return "doc:" + pointer
+		},
+	)
+	require.NoError(t, err)
+	require.Equal(
+		t,
+		svalue.Values{
+			"database-max_conns": {
+				Location: "doc:/database/max-conns",
+				Value:    "10",
+			},
+			"database-a/b~c": {
+				Location: "doc:/database/a~1b~0c",
+				Value:    "x",
+			},
+		},
+		values,
+	)
+
+	_, err = ParseValues(FormatJSON, []byte(`[1]`), nil)
+	require.ErrorIs(t, err, ErrInvalidRoot)
+}
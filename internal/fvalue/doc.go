@@ -20,6 +20,7 @@ The Value struct represents a single configuration field value with its metadata
 		Value    reflect.Value  // The actual configuration value
 		Location string         // Source location for debugging
 		Path     string         // Configuration path/key
+		Entries  map[string]Values // Map entries (map fields only)
 	}
 
 ### Fields
@@ -34,6 +35,10 @@ The Value struct represents a single configuration field value with its metadata
   - **Path**: The configuration path/key identifying this field within the
     configuration hierarchy (e.g., "host", "database.connection.timeout").
 
+  - **Entries**: For map fields only, the values of every map entry indexed
+    by entry key. Each entry holds the Values of the element sub-model, so
+    entries coming from several layers can be merged leaf by leaf.
+
 ## Values
 
 Values is a map type that associates field identifiers with their values:
@@ -8,6 +8,19 @@ type Value struct {
 	Value    reflect.Value
 	Location string
 	Path     string
+
+	// Secret is true when the value must be redacted whatever the field
+	// (i.e. values read from a secret store).
+	Secret bool
+
+	// References lists the references expanded by interpolation to build
+	// the value (i.e. "env:HOME").
+	References []string
+
+	// Entries holds, for map fields, the element values of every map entry
+	// indexed by entry key. Element values are indexed by the UIDs of the
+	// entry sub-model.
+	Entries map[string]Values
 }
 
 type Values map[uint]*Value
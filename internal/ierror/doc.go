@@ -143,7 +143,7 @@ When processing struct fields or configuration values:
 
 The ierror package is heavily used in dsco's layer processing:
 
-	func (layers Layers) GetPolicies() (constraintLayerPolicies, error) {
+	func (layers Layers) GetPolicies(model ModelInterface) (constraintLayerPolicies, error) {
 		var errs LayerErrors
 
 		for index, layer := range layers {
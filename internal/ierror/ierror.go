@@ -9,6 +9,7 @@ import (
 type IError struct {
 	Err   error  // The underlying error that occurred
 	Info  string // Contextual description of the operation
+	Name  string // Name of the item at Index, when known
 	Index int    // Index position where the error occurred
 }
 
@@ -24,7 +25,9 @@ func (e IError) Error() string {
 }
 
 // Unwrap returns the underlying error, enabling error chain traversal
-// for error.Is() and error.As() functionality.
-func (e *IError) Unwrap() error {
+// for error.Is() and error.As() functionality. It has a value receiver as
+// IError values, not pointers, are stored in error lists: with a pointer
+// receiver, errors.Is and errors.As stopped at them.
+func (e IError) Unwrap() error {
 	return e.Err
 }
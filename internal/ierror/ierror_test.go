@@ -2,6 +2,7 @@ package ierror
 
 import (
 	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -36,3 +37,33 @@ func TestIError_Unwrap(t *testing.T) {
 		e.Unwrap(),
 	)
 }
+
+type causeError struct {
+	reason string
+}
+
+func (c causeError) Error() string {
+	return c.reason
+}
+
+func TestIError_Unwrap_chain(t *testing.T) {
+	t.Parallel()
+
+	// IError is used as a value, i.e. stored by value in error lists
+	var err error = IError{
+		Index: 1,
+		Info:  "layer",
+		Err:   fmt.Errorf("file builder: %w", causeError{reason: "missing"}),
+	}
+
+	var cause causeError
+
+	require.ErrorAs(t, err, &cause)
+	require.Equal(t, "missing", cause.reason)
+
+	err = IError{Index: 2, Info: "layer", Err: errMocked}
+	require.ErrorIs(t, err, errMocked)
+
+	// pointers unwrap as well
+	require.ErrorIs(t, &IError{Err: errMocked}, errMocked)
+}
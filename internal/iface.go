@@ -16,6 +16,18 @@ type StructExpander interface {
 	ExpandStruct(path string, structType reflect.Type) error
 }
 
+// EntriesGetter defines the ability to get the entries of map fields.
+type EntriesGetter interface {
+	ValueGetter
+	StructExpander
+
+	// GetEntryKeys returns the sorted keys of the entries available for
+	// the map located at path. leaves lists the element relative paths
+	// that end an entry key and prefixes the ones that can be followed by
+	// further segments (nested maps).
+	GetEntryKeys(path string, leaves, prefixes []string) []string
+}
+
 // ModelInterface represents the target configuration structure model.
 type ModelInterface interface {
 	TypeName() string
@@ -14,7 +14,7 @@ commonly used in Kubernetes ConfigMaps and Secrets mounted as volumes.
 
 Files must follow a strict naming pattern to be recognized:
 
-	Pattern: ^[A-Z][A-Z\d]*([-_][A-Z][A-Z\d]*)*$
+	Pattern: ^[A-Z][A-Z\d]*([-_]([A-Z][A-Z\d]*|\d+))*$
 
 ## Valid File Names
 
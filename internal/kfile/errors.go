@@ -9,17 +9,35 @@ type pathError struct {
 	path string
 }
 
+func (p *pathError) Error() string {
+	return p.path + ": " + p.err.Error()
+}
+
+func (p *pathError) Unwrap() error {
+	return p.err
+}
+
 type PathErrors []*pathError
 
 func (p PathErrors) Error() string {
 	var sb strings.Builder
 
 	for _, ep := range p {
-		sb.WriteString(ep.path)
-		sb.WriteString(": ")
-		sb.WriteString(ep.err.Error())
+		sb.WriteString(ep.Error())
 		sb.WriteRune('\n')
 	}
 
 	return sb.String()
 }
+
+// Unwrap returns every path error, enabling errors.Is and errors.As on the
+// underlying causes.
+func (p PathErrors) Unwrap() []error {
+	errs := make([]error, len(p))
+
+	for i, ep := range p {
+		errs[i] = ep
+	}
+
+	return errs
+}
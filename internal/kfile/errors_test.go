@@ -3,6 +3,8 @@ package kfile
 import (
 	"errors"
 	"testing"
+
+	"github.com/stretchr/testify/require"
 )
 
 var (
@@ -50,3 +52,22 @@ func TestPathErrors_Error(t *testing.T) {
 		)
 	}
 }
+
+func TestPathErrors_Unwrap(t *testing.T) {
+	t.Parallel()
+
+	err := PathErrors{
+		&pathError{
+			err:  errMocked1,
+			path: "p1",
+		},
+	}
+
+	require.ErrorIs(t, err, errMocked1)
+	require.NotErrorIs(t, err, errMocked2)
+
+	var pe *pathError
+
+	require.ErrorAs(t, err, &pe)
+	require.Equal(t, "p1: err1", pe.Error())
+}
@@ -1,6 +1,7 @@
 package kfile
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
@@ -14,7 +15,7 @@ import (
 )
 
 const (
-	fileNameExp = `^[A-Z][A-Z\d]*([-_][A-Z][A-Z\d]*)*$`
+	fileNameExp = `^[A-Z][A-Z\d]*([-_]([A-Z][A-Z\d]*|\d+))*$`
 )
 
 var (
@@ -23,11 +24,71 @@ var (
 	// ErrInvalidFileName represents an error indicating that the file name is
 	// invalid.
 	ErrInvalidFileName = errors.New("invalid kfile name")
+
+	// ErrFileTooLarge represents an error indicating that the file size
+	// exceeds the maximum allowed size.
+	ErrFileTooLarge = errors.New("kfile too large")
+
+	// ErrSymlinkLoop represents an error indicating that a symlink leads to
+	// one of the directories holding it.
+	ErrSymlinkLoop = errors.New("kfile symlink loop")
 )
 
+const projectedVolumePrefix = ".."
+
 type options struct {
+	ctx context.Context
 	// silentDirErrors  bool
-	silentFileErrors bool
+	silentFileErrors     bool
+	trimTrailingNewlines bool
+	followSymlinks       bool
+	maxFileSize          int64
+}
+
+// Option is a kfile entries' provider option.
+type Option func(opt *options)
+
+// WithSilentFileErrors ignores errors reported while walking the
+// directory tree (e.g. permission denied on a sub directory).
+func WithSilentFileErrors() Option {
+	return func(opt *options) {
+		opt.silentFileErrors = true
+	}
+}
+
+// WithTrimTrailingNewlines removes the trailing new lines (\n and \r) from
+// every file content.
+func WithTrimTrailingNewlines() Option {
+	return func(opt *options) {
+		opt.trimTrailingNewlines = true
+	}
+}
+
+// WithFollowSymlinks makes the provider understand the symlink layout used
+// by Kubernetes projected volumes: entries starting with ".." (the ..data
+// link and the timestamped directories) are skipped, links to files are
+// read through and links to directories are walked. A link to one of the
+// directories holding it, i.e. a link cycle, is reported as ErrSymlinkLoop
+// and not walked again.
+func WithFollowSymlinks() Option {
+	return func(opt *options) {
+		opt.followSymlinks = true
+	}
+}
+
+// WithMaxFileSize rejects every file larger than size bytes with
+// ErrFileTooLarge.
+func WithMaxFileSize(size int64) Option {
+	return func(opt *options) {
+		opt.maxFileSize = size
+	}
+}
+
+// WithContext stops the walk with the ctx error when ctx is done.
+func WithContext(ctx context.Context) Option {
+	return func(opt *options) {
+		opt.ctx = ctx
+	}
 }
 
 func newProvider(
@@ -51,7 +112,7 @@ func newProvider(
 
 	var errs PathErrors
 
-	_ = afero.Walk(
+	if err := afero.Walk(
 		fs, cleanDirName,
 		walkFunc(
 			fs,
@@ -61,7 +122,9 @@ func newProvider(
 			cleanDirName,
 			dirToSkip,
 		),
-	)
+	); err != nil {
+		return nil, fmt.Errorf("%s: %w", cleanDirName, err)
+	}
 
 	if len(errs) > 0 {
 		return nil, errs
@@ -102,7 +165,34 @@ func walkFunc(
 		)
 	}
 
-	return func(path string, info os.FileInfo, err error) error {
+	// directories of the current descent, to detect symlink cycles
+	var ancestors []ancestor
+
+	// descend drops the directories that do not hold path
+	descend := func(path string) {
+		for len(ancestors) > 0 &&
+			!ancestors[len(ancestors)-1].holds(path) {
+			ancestors = ancestors[:len(ancestors)-1]
+		}
+	}
+
+	isAncestor := func(dir os.FileInfo) bool {
+		for _, a := range ancestors {
+			if os.SameFile(a.info, dir) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	var walk func(path string, info os.FileInfo, err error) error
+
+	walk = func(path string, info os.FileInfo, err error) error {
+		if opt.ctx != nil && opt.ctx.Err() != nil {
+			return opt.ctx.Err() //nolint:wrapcheck // wrapped by newProvider
+		}
+
 		if err != nil {
 			if !opt.silentFileErrors {
 				appendError(path, err)
@@ -111,7 +201,46 @@ func walkFunc(
 			return nil
 		}
 
+		if opt.followSymlinks {
+			descend(filepath.Clean(path))
+		}
+
+		if opt.followSymlinks &&
+			path != cleanDirName &&
+			strings.HasPrefix(info.Name(), projectedVolumePrefix) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if opt.followSymlinks && info.Mode()&os.ModeSymlink != 0 {
+			target, err := fs.Stat(path)
+			if err != nil {
+				return walk(path, info, err)
+			}
+
+			if target.IsDir() {
+				if isAncestor(target) {
+					return walk(path, info, ErrSymlinkLoop)
+				}
+
+				// trailing separator makes the walk resolve the link
+				return afero.Walk(fs, path+string(filepath.Separator), walk)
+			}
+
+			info = target
+		}
+
 		if info.IsDir() {
+			if opt.followSymlinks {
+				ancestors = append(
+					ancestors,
+					ancestor{path: filepath.Clean(path), info: info},
+				)
+			}
+
 			return nil
 		}
 
@@ -121,6 +250,12 @@ func walkFunc(
 			return nil
 		}
 
+		if opt.maxFileSize > 0 && info.Size() > opt.maxFileSize {
+			appendError(path, ErrFileTooLarge)
+
+			return nil
+		}
+
 		fileContent, err := afero.ReadFile(fs, path)
 		if err != nil {
 			appendError(path, err)
@@ -128,6 +263,11 @@ func walkFunc(
 			return nil
 		}
 
+		value := string(fileContent)
+		if opt.trimTrailingNewlines {
+			value = strings.TrimRight(value, "\r\n")
+		}
+
 		result[strings.ToLower(info.Name())] = &svalue.Value{
 			Location: fmt.Sprintf(
 				"kfile[%s]:%s",
@@ -136,11 +276,33 @@ func walkFunc(
 					strings.Split(path, string(filepath.Separator))[dirToSkip:]...,
 				),
 			),
-			Value: string(fileContent),
+			Value: value,
 		}
 
 		return nil
 	}
+
+	return walk
+}
+
+// ancestor is a directory of the current descent.
+type ancestor struct {
+	path string
+	info os.FileInfo
+}
+
+// holds returns true when path is located under the directory.
+func (a ancestor) holds(path string) bool {
+	if a.path == "." {
+		return true
+	}
+
+	prefix := a.path
+	if !strings.HasSuffix(prefix, string(filepath.Separator)) {
+		prefix += string(filepath.Separator)
+	}
+
+	return strings.HasPrefix(path, prefix)
 }
 
 type EntriesProvider struct {
@@ -156,15 +318,25 @@ func (e *EntriesProvider) GetStringValues() svalue.Values {
 	return e.values
 }
 
+// NewEntriesProvider creates an entries' provider that reads every file of
+// the directory tree rooted at path, file names being the keys and file
+// contents the values.
 func NewEntriesProvider(
 	path string,
+	opts ...Option,
 ) (
 	*EntriesProvider,
 	error,
 ) {
+	opt := &options{}
+
+	for _, o := range opts {
+		o(opt)
+	}
+
 	return newProvider(
 		afero.NewReadOnlyFs(afero.NewOsFs()),
 		path,
-		&options{},
+		opt,
 	)
 }
@@ -1,6 +1,7 @@
 package kfile
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"testing"
@@ -511,10 +512,19 @@ func Test(t *testing.T) {
 			fileName: "A1SD-Z1AY_ASD1-Q1WE",
 			match:    true,
 		},
+		{
+			name:     "match4",
+			fileName: "LISTENERS-0-PORT",
+			match:    true,
+		},
 		{
 			name:     "unmatch1",
 			fileName: "12ASD",
 		},
+		{
+			name:     "unmatch3",
+			fileName: "LISTENERS-0A-PORT",
+		},
 		{
 			name:     "unmatch2",
 			fileName: "PQR+",
@@ -634,3 +644,298 @@ func TestEntriesProvider_GetName(t *testing.T) {
 		ep.GetName(),
 	)
 }
+
+func TestNewEntriesProvider_options(t *testing.T) {
+	t.Parallel()
+
+	t.Run(
+		"trim trailing newlines", func(t *testing.T) {
+			t.Parallel()
+
+			tempDir := t.TempDir()
+
+			require.NoError(
+				t, os.WriteFile(
+					filepath.Join(tempDir, "K1"),
+					[]byte("content1\r\n\n"),
+					0o600,
+				),
+			)
+
+			provider, err := NewEntriesProvider(
+				tempDir,
+				WithTrimTrailingNewlines(),
+			)
+
+			require.NoError(t, err)
+			require.Equal(
+				t,
+				"content1",
+				provider.GetStringValues()["k1"].Value,
+			)
+		},
+	)
+
+	t.Run(
+		"max file size", func(t *testing.T) {
+			t.Parallel()
+
+			tempDir := t.TempDir()
+
+			require.NoError(
+				t, os.WriteFile(
+					filepath.Join(tempDir, "K1"),
+					[]byte("0123456789"),
+					0o600,
+				),
+			)
+
+			provider, err := NewEntriesProvider(
+				tempDir,
+				WithMaxFileSize(10),
+			)
+			require.NoError(t, err)
+			require.Len(t, provider.GetStringValues(), 1)
+
+			provider, err = NewEntriesProvider(
+				tempDir,
+				WithMaxFileSize(9),
+			)
+
+			var ep PathErrors
+
+			require.ErrorAs(t, err, &ep)
+			require.Len(t, ep, 1)
+			require.ErrorIs(t, ep[0].err, ErrFileTooLarge)
+			require.Nil(t, provider)
+		},
+	)
+
+	t.Run(
+		"silent file errors", func(t *testing.T) {
+			t.Parallel()
+
+			provider, err := NewEntriesProvider(
+				filepath.Join(t.TempDir(), "missing"),
+				WithSilentFileErrors(),
+			)
+
+			require.NoError(t, err)
+			require.Empty(t, provider.GetStringValues())
+		},
+	)
+
+	t.Run(
+		"projected volume", func(t *testing.T) {
+			t.Parallel()
+
+			tempDir := t.TempDir()
+			dataDir := filepath.Join(tempDir, "..2026_10_18_12_00_00.1")
+
+			require.NoError(t, os.MkdirAll(dataDir, 0o700))
+			require.NoError(
+				t, os.WriteFile(
+					filepath.Join(dataDir, "PASSWORD"),
+					[]byte("secret\n"),
+					0o600,
+				),
+			)
+			require.NoError(
+				t, os.MkdirAll(filepath.Join(dataDir, "SUB"), 0o700),
+			)
+			require.NoError(
+				t, os.WriteFile(
+					filepath.Join(dataDir, "SUB", "TOKEN"),
+					[]byte("token"),
+					0o600,
+				),
+			)
+			require.NoError(
+				t, os.Symlink(
+					filepath.Base(dataDir),
+					filepath.Join(tempDir, "..data"),
+				),
+			)
+			require.NoError(
+				t, os.Symlink(
+					filepath.Join("..data", "PASSWORD"),
+					filepath.Join(tempDir, "PASSWORD"),
+				),
+			)
+			require.NoError(
+				t, os.Symlink(
+					filepath.Join("..data", "SUB"),
+					filepath.Join(tempDir, "SUB"),
+				),
+			)
+
+			_, err := NewEntriesProvider(tempDir)
+			require.ErrorIs(t, err, ErrInvalidFileName)
+
+			provider, err := NewEntriesProvider(
+				tempDir,
+				WithFollowSymlinks(),
+				WithTrimTrailingNewlines(),
+			)
+
+			require.NoError(t, err)
+			require.Equal(
+				t,
+				svalue.Values{
+					"password": {
+						Location: "kfile[" + tempDir + "]:PASSWORD",
+						Value:    "secret",
+					},
+					"token": {
+						Location: "kfile[" + tempDir + "]:SUB/TOKEN",
+						Value:    "token",
+					},
+				},
+				provider.GetStringValues(),
+			)
+		},
+	)
+
+	t.Run(
+		"dangling symlink", func(t *testing.T) {
+			t.Parallel()
+
+			tempDir := t.TempDir()
+
+			require.NoError(
+				t, os.Symlink(
+					filepath.Join(tempDir, "nowhere"),
+					filepath.Join(tempDir, "K1"),
+				),
+			)
+
+			_, err := NewEntriesProvider(tempDir, WithFollowSymlinks())
+			require.ErrorIs(t, err, os.ErrNotExist)
+
+			provider, err := NewEntriesProvider(
+				tempDir,
+				WithFollowSymlinks(),
+				WithSilentFileErrors(),
+			)
+			require.NoError(t, err)
+			require.Empty(t, provider.GetStringValues())
+		},
+	)
+
+	t.Run(
+		"symlink cycle", func(t *testing.T) {
+			t.Parallel()
+
+			tempDir := t.TempDir()
+
+			require.NoError(
+				t, os.MkdirAll(filepath.Join(tempDir, "SUB"), 0o700),
+			)
+			require.NoError(
+				t, os.WriteFile(
+					filepath.Join(tempDir, "SUB", "K1"),
+					[]byte("v1"),
+					0o600,
+				),
+			)
+			require.NoError(
+				t, os.Symlink(
+					"..",
+					filepath.Join(tempDir, "SUB", "LOOP"),
+				),
+			)
+
+			_, err := NewEntriesProvider(tempDir, WithFollowSymlinks())
+			require.ErrorIs(t, err, ErrSymlinkLoop)
+			require.ErrorContains(
+				t,
+				err,
+				filepath.Join(tempDir, "SUB", "LOOP"),
+			)
+			require.Len(t, err.(PathErrors), 1) //nolint:errorlint // test
+
+			provider, err := NewEntriesProvider(
+				tempDir,
+				WithFollowSymlinks(),
+				WithSilentFileErrors(),
+			)
+			require.NoError(t, err)
+			require.Equal(
+				t,
+				svalue.Values{
+					"k1": {
+						Location: "kfile[" + tempDir + "]:SUB/K1",
+						Value:    "v1",
+					},
+				},
+				provider.GetStringValues(),
+			)
+		},
+	)
+
+	t.Run(
+		"symlink to walked directory", func(t *testing.T) {
+			t.Parallel()
+
+			tempDir := t.TempDir()
+
+			require.NoError(
+				t, os.MkdirAll(filepath.Join(tempDir, "A"), 0o700),
+			)
+			require.NoError(
+				t, os.WriteFile(
+					filepath.Join(tempDir, "A", "K1"),
+					[]byte("v1"),
+					0o600,
+				),
+			)
+			require.NoError(
+				t, os.Symlink("A", filepath.Join(tempDir, "B")),
+			)
+
+			provider, err := NewEntriesProvider(
+				tempDir,
+				WithFollowSymlinks(),
+			)
+			require.NoError(t, err)
+			require.Equal(
+				t,
+				svalue.Values{
+					"k1": {
+						Location: "kfile[" + tempDir + "]:B/K1",
+						Value:    "v1",
+					},
+				},
+				provider.GetStringValues(),
+			)
+		},
+	)
+}
+
+func TestNewEntriesProvider_context(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+
+	require.NoError(
+		t, os.WriteFile(
+			filepath.Join(tempDir, "K1"),
+			[]byte("content1"),
+			0o600,
+		),
+	)
+
+	provider, err := NewEntriesProvider(
+		tempDir,
+		WithContext(context.Background()),
+	)
+	require.NoError(t, err)
+	require.Len(t, provider.GetStringValues(), 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	provider, err = NewEntriesProvider(tempDir, WithContext(ctx))
+	require.ErrorIs(t, err, context.Canceled)
+	require.Nil(t, provider)
+}
@@ -43,6 +43,11 @@ func (m MError) As(errAs any) bool {
 	return false
 }
 
+// Errors returns the aggregated errors.
+func (m MError) Errors() []error {
+	return m
+}
+
 func (m *MError) Add(err error) {
 	*m = append(*m, err)
 }
@@ -63,7 +63,11 @@ The package analyzes struct fields for configuration mapping:
 
 - **Pointer types**: *string, *int, *bool, *time.Duration, etc.
 - **Slice types**: []string, []int, etc.
-- **Map types**: map[string]string, map[string]interface{}, etc.
+- **Indexed slices**: slices of struct pointers ([]*Listener), every
+  element being addressed by its index
+- **Map types**: string keyed maps of any supported type, including plain
+  registered values (map[string]string) and struct pointers
+  (map[string]*Backend)
 - **Nested structs**: *DatabaseConfig, *ServerConfig, etc.
 - **Embedded structs**: Anonymous struct fields
 
@@ -142,14 +146,88 @@ The model uses a tree structure of nodes for efficient field access:
 		BuildExpandList(list *ExpandList)
 		FeedFieldValues(id string, values fvalue.Values, value reflect.Value)
 		Fill(value reflect.Value, layers []fvalue.Values) (plocation.Locations, error)
+		Validate(value reflect.Value) []Violation
 	}
 
 ### Node Types
 
 - **StructNode**: Represents struct types with nested fields
 - **ValueNode**: Represents leaf fields (actual configuration values)
-- **SliceNode**: Represents slice/array fields
-- **MapNode**: Represents map fields
+- **SliceNode**: Represents slices of struct pointers, every element being
+  filled through an entry sub-model keyed by its index
+- **MapNode**: Represents map fields, every entry being filled through an
+  entry sub-model so entries from several layers merge leaf by leaf
+
+## Merge Policies
+
+The dsco struct tag selects how map entries and slice elements provided by
+several layers are combined:
+
+	type Config struct {
+		Backends map[string]*Backend                  // key-wise (default)
+		Labels   map[string]string `dsco:"merge=replace"` // first layer wins
+	}
+
+- **MergeKeys**: entries are merged key by key, each entry leaf being taken
+  from the first layer providing it
+- **MergeIndex**: slice elements are merged index by index (slice default)
+- **MergeAppend**: slice elements of all layers are concatenated, lowest
+  precedence layer first
+- **MergeReplace**: the whole map or slice of the first layer providing it
+  is used
+
+## Validation Rules
+
+The dsco struct tag also holds validation rules, checked by Model.Validate
+once the struct is filled:
+
+	type Config struct {
+		Port  *int    `dsco:"min=1,max=65535"`
+		Level *string `dsco:"oneof=debug info warn"`
+		Name  *string `dsco:"regexp=^[a-z]+$"`
+	}
+
+- **min/max**: bounds of numbers and durations, length of strings,
+  slices and maps
+- **oneof**: space separated list of accepted values
+- **regexp**: regular expression, taking the rest of the tag
+
+Structs implementing Validate() error are validated after their fields.
+Every failure is returned as a Violation holding the field path.
+
+## Optional Fields
+
+The optional option of the dsco struct tag lets Fill leave a field nil
+instead of reporting an uninitialized key. An optional struct is left nil
+when no layer provides any field of its sub-tree. Model.IsOptional reports
+optional leaves, fields of optional structs included.
+
+## Secret Fields
+
+The secret option marks fields whose values must be redacted in outputs.
+Fill flags their locations as secret and Model.IsSecret reports secret
+leaves, fields of secret structs included.
+
+## Deprecated Fields
+
+The deprecated option marks fields still accepted but due for removal.
+Model.IsDeprecated reports deprecated leaves, fields of deprecated structs
+included, so dsco can warn when a layer provides them.
+
+## Descriptions
+
+The desc struct tag, separate from the dsco tag, describes a field in the
+generated usage text. Model.Description returns it by field path:
+
+	type Config struct {
+		Host *string `desc:"Database host name"`
+	}
+
+## Key Tags
+
+The env, flag, file and secret struct tags set the key of a leaf, map or
+slice field in the matching layers. Model.TaggedKeys returns them by field path.
+Key tags on a struct field are an InvalidTagError.
 
 # Field Path Generation
 
@@ -161,6 +239,8 @@ Configuration paths use dot notation for nested fields:
 	database.host           # Nested field
 	database.pool.size      # Deeply nested field
 	servers.primary.port    # Complex nesting
+	backends[eu].url        # Map entry field
+	listeners[0].port       # Slice element field
 
 ## YAML Tag Processing
 
@@ -189,6 +269,7 @@ Model building errors are aggregated:
 - **InvalidEmbedded**: Embedded pointer structs not supported
 - **FieldNameCollision**: Multiple fields resolve to same name
 - **UnsupportedType**: Field type not supported for configuration
+- **InvalidTag**: Malformed dsco struct tag
 
 ### Error Checking
 
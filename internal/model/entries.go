@@ -0,0 +1,227 @@
+package model
+
+import (
+	"reflect"
+
+	"github.com/byte4ever/dsco/internal"
+	"github.com/byte4ever/dsco/internal/fvalue"
+	"github.com/byte4ever/dsco/internal/merror"
+	"github.com/byte4ever/dsco/registry"
+)
+
+// IsIndexedSlice returns true when _type is a slice of struct pointers,
+// modelled element by element instead of as a single value.
+func IsIndexedSlice(_type reflect.Type) bool {
+	if _type.Kind() != reflect.Slice {
+		return false
+	}
+
+	elem := _type.Elem()
+
+	return elem.Kind() == reflect.Pointer &&
+		elem.Elem().Kind() == reflect.Struct &&
+		!registry.TypeIsDecodable(elem)
+}
+
+// entryPath returns the visible path of the entry key of the collection
+// located at path.
+func entryPath(path, key string) string {
+	return path + "[" + key + "]"
+}
+
+// entryNode returns the sub-model of the entry key of the collection located
+// at path.
+//
+//nolint:ireturn // expected to build abstract tree nodes
+func entryNode(path string, elemType reflect.Type, key string) Node {
+	var uid uint
+
+	// element type is checked when scanning the collection
+	node, _ := scanEntry(
+		&uid,
+		entryPath(path, key),
+		elemType,
+	)
+
+	return node
+}
+
+// entriesInfo holds what collection nodes share to get their entries.
+type entriesInfo struct {
+	// element relative paths, see internal.EntriesGetter
+	leaves   []string
+	prefixes []string
+}
+
+// scanElem scans the element type of a collection and collects its relative
+// paths.
+func (e *entriesInfo) scanElem(elemType reflect.Type) merror.MError {
+	var elemUID uint
+
+	elemNode, errs := scanEntry(&elemUID, "", elemType)
+	if !errs.None() {
+		return errs
+	}
+
+	collectSubPaths(elemNode, &e.leaves, &e.prefixes)
+
+	return nil
+}
+
+// getEntries gets the value of the collection located at path: a value
+// provided as a whole by g completed with the entries g provides
+// individually. Only keys accepted by accept are processed.
+func (e *entriesInfo) getEntries(
+	g internal.ValueGetter,
+	path string,
+	_type reflect.Type,
+	accept func(key string) bool,
+) (*fvalue.Value, error) {
+	fieldValue, err := g.Get(path, _type)
+	if err != nil {
+		return nil, err //nolint:wrapcheck // don't wan to wrap
+	}
+
+	entriesGetter, ok := g.(internal.EntriesGetter)
+	if !ok {
+		return fieldValue, nil
+	}
+
+	var errs ApplyError
+
+	for _, key := range entriesGetter.GetEntryKeys(
+		path,
+		e.leaves,
+		e.prefixes,
+	) {
+		if !accept(key) {
+			continue
+		}
+
+		// entries from an expanded value take precedence
+		if fieldValue != nil {
+			if _, found := fieldValue.Entries[key]; found {
+				continue
+			}
+		}
+
+		entryValues, err := getEntry(
+			entriesGetter,
+			entryNode(path, _type.Elem(), key),
+		)
+		if err != nil {
+			errs.Add(err)
+			continue
+		}
+
+		if len(entryValues) == 0 {
+			continue
+		}
+
+		if fieldValue == nil {
+			fieldValue = &fvalue.Value{}
+		}
+
+		if fieldValue.Entries == nil {
+			fieldValue.Entries = make(map[string]fvalue.Values)
+		}
+
+		fieldValue.Entries[key] = entryValues
+	}
+
+	if !errs.None() {
+		return nil, errs
+	}
+
+	if fieldValue != nil {
+		fieldValue.Path = path
+	}
+
+	return fieldValue, nil
+}
+
+// getEntry gets the values of an entry by applying its sub-model on g.
+func getEntry(
+	g internal.EntriesGetter,
+	node Node,
+) (fvalue.Values, error) {
+	var expandList ExpandList
+
+	node.BuildExpandList(&expandList)
+
+	if err := expandList.ApplyOn(g); err != nil {
+		return nil, err
+	}
+
+	var getList GetList
+
+	node.BuildGetList(&getList)
+
+	return getList.ApplyOn(g)
+}
+
+// feedEntry feeds the values of the entry key of the collection located at
+// path from the struct value.
+func feedEntry(
+	srcID, path string,
+	elemType reflect.Type,
+	key string,
+	value reflect.Value,
+	entries map[string]fvalue.Values,
+) {
+	entryValues := make(fvalue.Values)
+
+	entryNode(path, elemType, key).FeedFieldValues(srcID, entryValues, value)
+
+	if len(entryValues) > 0 {
+		entries[key] = entryValues
+	}
+}
+
+// sourcesOf returns the values of field uid provided by the layers, in
+// precedence order.
+func sourcesOf(uid uint, layers []fvalue.Values) []*fvalue.Value {
+	var sources []*fvalue.Value
+
+	for _, layer := range layers {
+		if fieldValue := layer[uid]; fieldValue != nil {
+			sources = append(sources, fieldValue)
+		}
+	}
+
+	return sources
+}
+
+// entryLayers returns the values of the entry key provided by the sources.
+func entryLayers(sources []*fvalue.Value, key string) []fvalue.Values {
+	layers := make([]fvalue.Values, 0, len(sources))
+
+	for _, source := range sources {
+		if entryValues, found := source.Entries[key]; found {
+			layers = append(layers, entryValues)
+		}
+	}
+
+	return layers
+}
+
+// releaseEntries removes the consumed entries from the layers, so only
+// overridden ones remain. Fully consumed collection values are removed.
+func releaseEntries(uid uint, layers []fvalue.Values) {
+	for _, layer := range layers {
+		fieldValue := layer[uid]
+		if fieldValue == nil {
+			continue
+		}
+
+		for key, entryValues := range fieldValue.Entries {
+			if len(entryValues) == 0 {
+				delete(fieldValue.Entries, key)
+			}
+		}
+
+		if len(fieldValue.Entries) == 0 {
+			delete(layer, uid)
+		}
+	}
+}
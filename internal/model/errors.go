@@ -34,8 +34,50 @@ func (u UnsupportedTypeError) Error() string {
 	)
 }
 
+// ErrInvalidTag represents an error where a dsco struct tag is invalid.
+var ErrInvalidTag = errors.New("invalid tag")
+
+type InvalidTagError struct {
+	Path   string
+	Tag    string
+	Reason string
+}
+
+func (u InvalidTagError) Error() string {
+	return fmt.Sprintf(
+		"struct field %s with invalid tag %q: %s",
+		u.Path,
+		u.Tag,
+		u.Reason,
+	)
+}
+
+func (InvalidTagError) Is(err error) bool {
+	return errors.Is(err, ErrInvalidTag)
+}
+
 // ErrInvalidEmbedded represent an error where ....
 var ErrInvalidEmbedded = errors.New("invalid embedded")
 
 // ErrUninitializedKey represent an error where ....
 var ErrUninitializedKey = errors.New("uninitialized key")
+
+// UninitializedKeyError represents an error where no layer provides the
+// value of the field of type Type located at Path.
+type UninitializedKeyError struct {
+	Type reflect.Type
+	Path string
+}
+
+func (u UninitializedKeyError) Error() string {
+	return fmt.Sprintf(
+		"%s-[%s]: %s",
+		u.Path,
+		registry.LongTypeName(u.Type),
+		ErrUninitializedKey,
+	)
+}
+
+func (UninitializedKeyError) Is(err error) bool {
+	return errors.Is(err, ErrUninitializedKey)
+}
@@ -80,3 +80,24 @@ func TestUnsupportedTypeError_Error(t *testing.T) {
 		}.Error(),
 	)
 }
+
+func TestInvalidTagError_Error(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(
+		t,
+		`struct field p1 with invalid tag "merge=x": unknown merge policy x`,
+		InvalidTagError{
+			Path:   "p1",
+			Tag:    "merge=x",
+			Reason: "unknown merge policy x",
+		}.Error(),
+	)
+}
+
+func TestInvalidTagError_Is(t *testing.T) {
+	t.Parallel()
+
+	require.ErrorIs(t, InvalidTagError{}, ErrInvalidTag)
+	require.NotErrorIs(t, InvalidTagError{}, ErrInvalidEmbedded)
+}
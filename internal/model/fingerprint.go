@@ -0,0 +1,234 @@
+package model
+
+import (
+	"encoding"
+	"fmt"
+	"hash"
+	"reflect"
+	"strconv"
+
+	"github.com/byte4ever/dsco/hit"
+)
+
+// fingerprintSalt separates fingerprint hashes from other hit trees.
+var fingerprintSalt = []byte("dsco-fingerprint-8f0c2b6e") //nolint:gochecknoglobals // constant salt
+
+// Fingerprint returns the Merkle tree hashes of the filled struct value by
+// path, the root hash being at the empty path. Every sub-tree is keyed by
+// its path so moved values change the hashes, nil values are left out and
+// hashes of secret sub-trees are not reported.
+func (m *Model) Fingerprint(
+	hashProvider hit.HashProvider[hash.Hash],
+	value reflect.Value,
+) map[string][]byte {
+	f := fingerprinter{
+		hashProvider: hashProvider,
+		hashes:       make(map[string][]byte),
+	}
+
+	root := f.node(m.accelerator, value, false)
+	if root == nil {
+		root = hit.NewParentNode(hashProvider, fingerprintSalt, "")
+	}
+
+	f.hashes[""] = root.GetHash()
+
+	return f.hashes
+}
+
+type fingerprinter struct {
+	hashProvider hit.HashProvider[hash.Hash]
+	hashes       map[string][]byte
+}
+
+// node returns the Merkle node of the model node filled with value, or nil
+// when value is nil. Secret is true within a secret struct or collection.
+//
+//nolint:ireturn // expected to build abstract tree nodes
+func (f *fingerprinter) node(
+	node Node,
+	value reflect.Value,
+	secret bool,
+) hit.MerkelNode {
+	if isNil(value) {
+		return nil
+	}
+
+	var (
+		path   string
+		result hit.MerkelNode
+	)
+
+	// hashes of secret sub-trees are not reported
+	hidden := secret
+
+	switch n := node.(type) {
+	case *StructNode:
+		path = n.VisiblePath
+		hidden = hidden || n.Secret
+
+		children := make([]hit.MerkelNode, 0, len(n.Index))
+
+		for _, index := range n.Index {
+			children = f.appendChild(
+				children,
+				index.Node,
+				value.Elem().FieldByIndex(index.Index),
+				hidden,
+			)
+		}
+
+		result = f.parent(path, children)
+
+	case *MapNode:
+		path = n.VisiblePath
+		hidden = hidden || n.Secret
+
+		children := make([]hit.MerkelNode, 0, value.Len())
+
+		iter := value.MapRange()
+		for iter.Next() {
+			children = f.appendChild(
+				children,
+				entryNode(path, n.Type.Elem(), iter.Key().String()),
+				iter.Value(),
+				hidden,
+			)
+		}
+
+		result = f.parent(path, children)
+
+	case *SliceNode:
+		path = n.VisiblePath
+		hidden = hidden || n.Secret
+
+		children := make([]hit.MerkelNode, 0, value.Len())
+
+		for i := 0; i < value.Len(); i++ {
+			children = f.appendChild(
+				children,
+				entryNode(path, n.Type.Elem(), strconv.Itoa(i)),
+				value.Index(i),
+				hidden,
+			)
+		}
+
+		result = f.parent(path, children)
+
+	case *ValueNode:
+		path = n.VisiblePath
+		hidden = hidden || n.Secret
+		result = f.leaf(path, value)
+	}
+
+	if !hidden {
+		f.hashes[path] = result.GetHash()
+	}
+
+	return result
+}
+
+// appendChild appends the node of the child filled with value, keyed by the
+// child path, to children.
+func (f *fingerprinter) appendChild(
+	children []hit.MerkelNode,
+	child Node,
+	value reflect.Value,
+	secret bool,
+) []hit.MerkelNode {
+	childNode := f.node(child, value, secret)
+	if childNode == nil {
+		return children
+	}
+
+	return append(
+		children,
+		hit.NewKeyedNode(
+			f.hashProvider,
+			fingerprintSalt,
+			childNode.GetID(),
+			hit.NewStringNode(
+				f.hashProvider,
+				fingerprintSalt,
+				childNode.GetID(),
+				childNode.GetID(),
+			),
+			childNode,
+		),
+	)
+}
+
+//nolint:ireturn // expected to build abstract tree nodes
+func (f *fingerprinter) parent(
+	path string,
+	children []hit.MerkelNode,
+) hit.MerkelNode {
+	return hit.NewParentNode(
+		f.hashProvider,
+		fingerprintSalt,
+		path,
+		children...,
+	)
+}
+
+// leaf returns the Merkle node of a registered type value. Values without
+// a dedicated node are hashed by their text form.
+//
+//nolint:ireturn // expected to build abstract tree nodes
+func (f *fingerprinter) leaf(path string, value reflect.Value) hit.MerkelNode {
+	hp, salt := f.hashProvider, fingerprintSalt
+
+	if isNil(value) {
+		return hit.NewParentNode(hp, salt, path)
+	}
+
+	if value.CanInterface() {
+		switch v := value.Interface().(type) {
+		case encoding.TextMarshaler:
+			if text, err := v.MarshalText(); err == nil {
+				return hit.NewStringNode(hp, salt, path, string(text))
+			}
+		case fmt.Stringer:
+			return hit.NewStringNode(hp, salt, path, v.String())
+		}
+	}
+
+	value = reflect.Indirect(value)
+
+	switch value.Kind() { //nolint:exhaustive // other kinds are hashed as text
+	case reflect.Bool:
+		return hit.NewBoolNode(hp, salt, path, value.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Int64:
+		return hit.NewIntNode(hp, salt, path, int(value.Int()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64:
+		return hit.NewUintNode(hp, salt, path, value.Uint())
+	case reflect.Float32, reflect.Float64:
+		return hit.NewFloatNode(hp, salt, path, value.Float())
+	case reflect.String:
+		return hit.NewStringNode(hp, salt, path, value.String())
+	case reflect.Slice, reflect.Array:
+		children := make([]hit.MerkelNode, 0, value.Len())
+
+		for i := 0; i < value.Len(); i++ {
+			elemPath := entryPath(path, strconv.Itoa(i))
+
+			children = append(
+				children,
+				hit.NewKeyedNode(
+					hp, salt, elemPath,
+					hit.NewIntNode(hp, salt, elemPath, i),
+					f.leaf(elemPath, value.Index(i)),
+				),
+			)
+		}
+
+		return f.parent(path, children)
+	default:
+		return hit.NewStringNode(
+			hp, salt, path,
+			fmt.Sprintf("%v", value.Interface()),
+		)
+	}
+}
@@ -0,0 +1,165 @@
+package model
+
+import (
+	"crypto/sha256"
+	"hash"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/byte4ever/dsco/hit/hprovider"
+	"github.com/byte4ever/dsco/ref"
+)
+
+type fingerprintBackend struct {
+	URL *string
+}
+
+type fingerprintSub struct {
+	Enabled *bool
+	Ratio   *float64
+}
+
+type fingerprintRoot struct {
+	Name     *string
+	Port     *uint16
+	Timeout  *time.Duration
+	At       *time.Time
+	Tags     []string
+	Sub      *fingerprintSub
+	Backends map[string]*fingerprintBackend
+	Nodes    []*fingerprintBackend
+	Password *string `dsco:"secret"`
+}
+
+func newFingerprintRoot() *fingerprintRoot {
+	return &fingerprintRoot{
+		Name:    ref.R("app"),
+		Port:    ref.R(uint16(80)),
+		Timeout: ref.R(time.Second),
+		At:      ref.R(time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)),
+		Tags:    []string{"a", "b"},
+		Sub: &fingerprintSub{
+			Enabled: ref.R(true),
+			Ratio:   ref.R(0.5),
+		},
+		Backends: map[string]*fingerprintBackend{
+			"eu": {URL: ref.R("http://eu")},
+			"us": {URL: ref.R("http://us")},
+		},
+		Nodes: []*fingerprintBackend{
+			{URL: ref.R("http://n0")},
+		},
+		Password: ref.R("secret"),
+	}
+}
+
+func TestModel_Fingerprint(t *testing.T) {
+	t.Parallel()
+
+	hp := hprovider.New(func() hash.Hash { return sha256.New() }, 2)
+
+	m, err := NewModel(reflect.TypeOf(&fingerprintRoot{}))
+	require.NoError(t, err)
+
+	fingerprint := func(v *fingerprintRoot) map[string][]byte {
+		return m.Fingerprint(hp, reflect.ValueOf(v))
+	}
+
+	reference := fingerprint(newFingerprintRoot())
+
+	t.Run(
+		"paths", func(t *testing.T) {
+			t.Parallel()
+
+			paths := make([]string, 0, len(reference))
+			for path := range reference {
+				paths = append(paths, path)
+			}
+
+			require.ElementsMatch(
+				t,
+				[]string{
+					"", "Name", "Port", "Timeout", "At", "Tags", "Sub",
+					"Sub.Enabled", "Sub.Ratio", "Backends",
+					"Backends[eu]", "Backends[eu].URL",
+					"Backends[us]", "Backends[us].URL",
+					"Nodes", "Nodes[0]", "Nodes[0].URL",
+				},
+				paths,
+			)
+		},
+	)
+
+	t.Run(
+		"deterministic", func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, reference, fingerprint(newFingerprintRoot()))
+		},
+	)
+
+	t.Run(
+		"changed subtree", func(t *testing.T) {
+			t.Parallel()
+
+			v := newFingerprintRoot()
+			v.Backends["us"].URL = ref.R("http://us2")
+
+			got := fingerprint(v)
+
+			for path, sum := range reference {
+				switch path {
+				case "", "Backends", "Backends[us]", "Backends[us].URL":
+					require.NotEqual(t, sum, got[path], path)
+				default:
+					require.Equal(t, sum, got[path], path)
+				}
+			}
+		},
+	)
+
+	t.Run(
+		"secret", func(t *testing.T) {
+			t.Parallel()
+
+			v := newFingerprintRoot()
+			v.Password = ref.R("rotated")
+
+			got := fingerprint(v)
+
+			require.NotEqual(t, reference[""], got[""])
+			require.NotContains(t, got, "Password")
+		},
+	)
+
+	t.Run(
+		"nil values", func(t *testing.T) {
+			t.Parallel()
+
+			v := newFingerprintRoot()
+			v.Sub = nil
+			v.Tags = nil
+
+			got := fingerprint(v)
+
+			require.NotEqual(t, reference[""], got[""])
+			require.NotContains(t, got, "Sub")
+			require.NotContains(t, got, "Sub.Enabled")
+			require.NotContains(t, got, "Tags")
+		},
+	)
+
+	t.Run(
+		"nil root", func(t *testing.T) {
+			t.Parallel()
+
+			got := fingerprint(nil)
+
+			require.Len(t, got, 1)
+			require.NotEmpty(t, got[""])
+		},
+	)
+}
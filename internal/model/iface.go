@@ -20,6 +20,7 @@ type Node interface {
 		layers []fvalue.Values,
 	) (plocation.Locations, error)
 	BuildExpandList(e *ExpandList)
+	Validate(value reflect.Value) []Violation
 }
 
 type GetListInterface interface {
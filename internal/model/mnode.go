@@ -0,0 +1,203 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/byte4ever/dsco/internal"
+	"github.com/byte4ever/dsco/internal/fvalue"
+	"github.com/byte4ever/dsco/internal/merror"
+	"github.com/byte4ever/dsco/internal/plocation"
+)
+
+// MapNode is a map field. Every map entry is modelled by an entry sub-model
+// rooted at the entry path (i.e. "Backends[eu]"), so entries provided by
+// several layers can be merged leaf by leaf.
+type MapNode struct {
+	entriesInfo
+	Type        reflect.Type
+	VisiblePath string
+	UID         uint
+	Merge       MergePolicy
+	Rules       Rules
+	Optional    bool
+	Secret      bool
+	Deprecated  bool
+	Description string
+	Keys        map[string]string
+}
+
+type MapNodeError struct {
+	merror.MError
+}
+
+var ErrMapNode = errors.New("")
+
+func (MapNodeError) Is(err error) bool {
+	return errors.Is(err, ErrMapNode)
+}
+
+func (n *MapNode) Fill(
+	value reflect.Value, layers []fvalue.Values,
+) (plocation.Locations, error) {
+	sources := sourcesOf(n.UID, layers)
+
+	if len(sources) == 0 && n.Optional {
+		return nil, nil
+	}
+
+	if len(sources) == 0 {
+		return nil, UninitializedKeyError{
+			Type: value.Type(),
+			Path: n.VisiblePath,
+		}
+	}
+
+	if n.Merge == MergeReplace {
+		sources = sources[:1]
+	}
+
+	keys := entryKeys(sources)
+
+	var (
+		pl   plocation.Locations
+		errs MapNodeError
+	)
+
+	result := reflect.MakeMapWithSize(n.Type, len(keys))
+
+	for _, key := range keys {
+		elem := reflect.New(n.Type.Elem()).Elem()
+
+		pln, err := entryNode(n.VisiblePath, n.Type.Elem(), key).Fill(
+			elem,
+			entryLayers(sources, key),
+		)
+		if err != nil {
+			errs.Add(err)
+		}
+
+		// entry locations are reported for the map field
+		for i := range pln {
+			pln[i].UID = n.UID
+			pln[i].Secret = pln[i].Secret || n.Secret
+		}
+
+		pl.Append(pln)
+
+		result.SetMapIndex(
+			reflect.ValueOf(key).Convert(n.Type.Key()),
+			elem,
+		)
+	}
+
+	value.Set(result)
+
+	releaseEntries(n.UID, layers)
+
+	if errs.None() {
+		return pl, nil
+	}
+
+	return pl, errs
+}
+
+// entryKeys returns the sorted union of the sources' entry keys.
+func entryKeys(sources []*fvalue.Value) []string {
+	dedup := make(map[string]struct{})
+
+	for _, source := range sources {
+		for key := range source.Entries {
+			dedup[key] = struct{}{}
+		}
+	}
+
+	keys := make([]string, 0, len(dedup))
+	for key := range dedup {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+func (n *MapNode) FeedFieldValues(
+	srcID string,
+	fieldValues fvalue.Values,
+	value reflect.Value,
+) {
+	if value.IsNil() {
+		return
+	}
+
+	entries := make(map[string]fvalue.Values, value.Len())
+
+	iter := value.MapRange()
+	for iter.Next() {
+		feedEntry(
+			srcID,
+			n.VisiblePath,
+			n.Type.Elem(),
+			iter.Key().String(),
+			iter.Value(),
+			entries,
+		)
+	}
+
+	fieldValues[n.UID] = &fvalue.Value{
+		Value: value,
+		Location: fmt.Sprintf(
+			"struct[%s]:%s",
+			srcID,
+			n.VisiblePath,
+		),
+		Path:    n.VisiblePath,
+		Entries: entries,
+	}
+}
+
+func (n *MapNode) BuildGetList(s *GetList) {
+	s.Push(
+		func(g internal.ValueGetter) (uint, *fvalue.Value, error) {
+			fieldValue, err := n.getEntries(
+				g,
+				n.VisiblePath,
+				n.Type,
+				func(string) bool { return true },
+			)
+
+			return n.UID, fieldValue, err
+		},
+	)
+}
+
+func (*MapNode) BuildExpandList(*ExpandList) {}
+
+func (n *MapNode) Validate(value reflect.Value) []Violation {
+	if value.IsNil() {
+		return nil
+	}
+
+	violations := checkRules(n.VisiblePath, n.Rules, value)
+
+	keys := value.MapKeys()
+	sort.Slice(
+		keys, func(i, j int) bool {
+			return keys[i].String() < keys[j].String()
+		},
+	)
+
+	for _, key := range keys {
+		violations = append(
+			violations,
+			entryNode(n.VisiblePath, n.Type.Elem(), key.String()).Validate(
+				value.MapIndex(key),
+			)...,
+		)
+	}
+
+	return violations
+}
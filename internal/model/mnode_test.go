@@ -0,0 +1,417 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/byte4ever/dsco/internal/fvalue"
+	"github.com/byte4ever/dsco/internal/plocation"
+	"github.com/byte4ever/dsco/ref"
+)
+
+type mapBackend struct {
+	URL  *string
+	Port *int
+}
+
+func Test_scanMap(t *testing.T) {
+	t.Parallel()
+
+	t.Run(
+		"success", func(t *testing.T) {
+			t.Parallel()
+
+			type Root struct {
+				Backends map[string]*mapBackend `dsco:"merge=replace"`
+				Labels   map[string]string
+				Nested   map[string]map[string]*int
+			}
+
+			var maxUID uint
+
+			node, errs := scan(&maxUID, "", reflect.TypeOf(&Root{}))
+			require.True(t, errs.None())
+			require.Equal(t, uint(3), maxUID)
+
+			structNode, ok := node.(*StructNode)
+			require.True(t, ok)
+
+			require.Equal(
+				t,
+				&MapNode{
+					Type:        reflect.TypeOf(map[string]*mapBackend{}),
+					VisiblePath: "Backends",
+					UID:         0,
+					Merge:       MergeReplace,
+					entriesInfo: entriesInfo{
+						leaves: []string{"URL", "Port"},
+					},
+				},
+				structNode.Index[0].Node,
+			)
+			require.Equal(
+				t,
+				&MapNode{
+					Type:        reflect.TypeOf(map[string]string{}),
+					VisiblePath: "Labels",
+					UID:         1,
+					Merge:       MergeKeys,
+				},
+				structNode.Index[1].Node,
+			)
+			require.Equal(
+				t,
+				&MapNode{
+					Type:        reflect.TypeOf(map[string]map[string]*int{}),
+					VisiblePath: "Nested",
+					UID:         2,
+					Merge:       MergeKeys,
+					entriesInfo: entriesInfo{
+						prefixes: []string{""},
+					},
+				},
+				structNode.Index[2].Node,
+			)
+		},
+	)
+
+	t.Run(
+		"unsupported", func(t *testing.T) {
+			t.Parallel()
+
+			type Root struct {
+				IntKeys  map[int]*string
+				BadElem  map[string]complex64
+				BadMerge *int              `dsco:"merge=keys"`
+				BadTag   map[string]string `dsco:"merge=x"`
+			}
+
+			var maxUID uint
+
+			_, errs := scan(&maxUID, "", reflect.TypeOf(&Root{}))
+			require.Equal(t, 4, errs.Count())
+			require.Equal(
+				t,
+				UnsupportedTypeError{
+					Path: "IntKeys",
+					Type: reflect.TypeOf(map[int]*string{}),
+				},
+				errs[0],
+			)
+			require.Equal(
+				t,
+				UnsupportedTypeError{
+					Type: reflect.TypeOf(complex64(0)),
+				},
+				errs[1],
+			)
+			require.ErrorIs(t, errs[2], ErrInvalidTag)
+			require.ErrorIs(t, errs[3], ErrInvalidTag)
+		},
+	)
+}
+
+func TestMapNode_Fill(t *testing.T) {
+	t.Parallel()
+
+	newNode := func(merge MergePolicy) *MapNode {
+		return &MapNode{
+			Type:        reflect.TypeOf(map[string]*mapBackend{}),
+			VisiblePath: "Backends",
+			UID:         7,
+			Merge:       merge,
+		}
+	}
+
+	newLayers := func() []fvalue.Values {
+		return []fvalue.Values{
+			{
+				7: {
+					Entries: map[string]fvalue.Values{
+						"eu": {
+							0: {
+								Value:    reflect.ValueOf(ref.R("url-eu-0")),
+								Location: "l0-eu-url",
+							},
+						},
+					},
+				},
+			},
+			{},
+			{
+				7: {
+					Entries: map[string]fvalue.Values{
+						"eu": {
+							0: {
+								Value:    reflect.ValueOf(ref.R("url-eu-2")),
+								Location: "l2-eu-url",
+							},
+							1: {
+								Value:    reflect.ValueOf(ref.R(1)),
+								Location: "l2-eu-port",
+							},
+						},
+						"us": {
+							0: {
+								Value:    reflect.ValueOf(ref.R("url-us-2")),
+								Location: "l2-us-url",
+							},
+							1: {
+								Value:    reflect.ValueOf(ref.R(2)),
+								Location: "l2-us-port",
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	t.Run(
+		"merge keys", func(t *testing.T) {
+			t.Parallel()
+
+			var backends map[string]*mapBackend
+
+			layers := newLayers()
+
+			pl, err := newNode(MergeKeys).Fill(
+				reflect.ValueOf(&backends).Elem(),
+				layers,
+			)
+			require.NoError(t, err)
+			require.Equal(
+				t,
+				map[string]*mapBackend{
+					"eu": {URL: ref.R("url-eu-0"), Port: ref.R(1)},
+					"us": {URL: ref.R("url-us-2"), Port: ref.R(2)},
+				},
+				backends,
+			)
+			require.Equal(
+				t,
+				plocation.Locations{
+					{Path: "Backends[eu].URL", Location: "l0-eu-url", UID: 7},
+					{Path: "Backends[eu].Port", Location: "l2-eu-port", UID: 7},
+					{Path: "Backends[us].URL", Location: "l2-us-url", UID: 7},
+					{Path: "Backends[us].Port", Location: "l2-us-port", UID: 7},
+				},
+				pl,
+			)
+
+			// only the overridden entry leaf remains
+			require.Empty(t, layers[0])
+			require.Len(t, layers[2][7].Entries, 1)
+			require.Len(t, layers[2][7].Entries["eu"], 1)
+			require.Equal(
+				t,
+				"l2-eu-url",
+				layers[2][7].Entries["eu"][0].Location,
+			)
+		},
+	)
+
+	t.Run(
+		"replace", func(t *testing.T) {
+			t.Parallel()
+
+			var backends map[string]*mapBackend
+
+			layers := newLayers()
+
+			pl, err := newNode(MergeReplace).Fill(
+				reflect.ValueOf(&backends).Elem(),
+				layers,
+			)
+			require.Len(t, pl, 1)
+			require.ErrorContains(
+				t,
+				err,
+				"Backends[eu].Port-[*int]: uninitialized key",
+			)
+			require.Empty(t, layers[0])
+			require.Len(t, layers[2][7].Entries, 2)
+		},
+	)
+
+	t.Run(
+		"uninitialized", func(t *testing.T) {
+			t.Parallel()
+
+			var backends map[string]*mapBackend
+
+			_, err := newNode(MergeKeys).Fill(
+				reflect.ValueOf(&backends).Elem(),
+				[]fvalue.Values{{}},
+			)
+			require.ErrorIs(t, err, ErrUninitializedKey)
+		},
+	)
+
+	t.Run(
+		"empty map", func(t *testing.T) {
+			t.Parallel()
+
+			var backends map[string]*mapBackend
+
+			layers := []fvalue.Values{{7: {Location: "blob"}}}
+
+			pl, err := newNode(MergeKeys).Fill(
+				reflect.ValueOf(&backends).Elem(),
+				layers,
+			)
+			require.NoError(t, err)
+			require.Empty(t, pl)
+			require.NotNil(t, backends)
+			require.Empty(t, backends)
+			require.Empty(t, layers[0])
+		},
+	)
+}
+
+func TestMapNode_FeedFieldValues(t *testing.T) {
+	t.Parallel()
+
+	n := &MapNode{
+		Type:        reflect.TypeOf(map[string]string{}),
+		VisiblePath: "Labels",
+		UID:         3,
+	}
+
+	fieldValues := make(fvalue.Values)
+
+	n.FeedFieldValues(
+		"defaults",
+		fieldValues,
+		reflect.ValueOf(map[string]string(nil)),
+	)
+	require.Empty(t, fieldValues)
+
+	labels := map[string]string{"team": "core"}
+
+	n.FeedFieldValues("defaults", fieldValues, reflect.ValueOf(labels))
+	require.Len(t, fieldValues, 1)
+
+	fieldValue := fieldValues[3]
+	require.Equal(t, "struct[defaults]:Labels", fieldValue.Location)
+	require.Equal(t, "Labels", fieldValue.Path)
+	require.Equal(t, labels, fieldValue.Value.Interface())
+	require.Len(t, fieldValue.Entries, 1)
+	require.Equal(
+		t,
+		"struct[defaults]:Labels[team]",
+		fieldValue.Entries["team"][0].Location,
+	)
+	require.Equal(t, "core", fieldValue.Entries["team"][0].Value.Interface())
+}
+
+// entriesGetterStub serves string values keyed by visible path.
+type entriesGetterStub struct {
+	values   map[string]string
+	keys     []string
+	expanded []string
+}
+
+func (g *entriesGetterStub) Get(
+	path string,
+	_ reflect.Type,
+) (*fvalue.Value, error) {
+	value, found := g.values[path]
+	if !found {
+		return nil, nil //nolint:nilnil // nothing found
+	}
+
+	return &fvalue.Value{
+		Value:    reflect.ValueOf(ref.R(value)),
+		Location: "stub:" + path,
+	}, nil
+}
+
+func (g *entriesGetterStub) ExpandStruct(path string, _ reflect.Type) error {
+	g.expanded = append(g.expanded, path)
+	return nil
+}
+
+func (g *entriesGetterStub) GetEntryKeys(
+	string,
+	[]string,
+	[]string,
+) []string {
+	return g.keys
+}
+
+func TestMapNode_BuildGetList(t *testing.T) {
+	t.Parallel()
+
+	n := &MapNode{
+		Type:        reflect.TypeOf(map[string]*mapBackend{}),
+		VisiblePath: "Backends",
+		UID:         2,
+	}
+
+	var gl GetList
+
+	n.BuildGetList(&gl)
+	require.Len(t, gl, 1)
+
+	t.Run(
+		"entries", func(t *testing.T) {
+			t.Parallel()
+
+			g := &entriesGetterStub{
+				values: map[string]string{
+					"Backends[eu].URL": "https://eu.local",
+				},
+				keys: []string{"eu", "us"},
+			}
+
+			uid, fv, err := gl[0](g)
+			require.NoError(t, err)
+			require.Equal(t, uint(2), uid)
+			require.Equal(t, "Backends", fv.Path)
+			require.Len(t, fv.Entries, 1)
+			require.Equal(
+				t,
+				"stub:Backends[eu].URL",
+				fv.Entries["eu"][0].Location,
+			)
+			require.Equal(
+				t,
+				"Backends[eu].URL",
+				fv.Entries["eu"][0].Path,
+			)
+			require.Equal(
+				t,
+				[]string{"Backends[eu]", "Backends[us]"},
+				g.expanded,
+			)
+		},
+	)
+
+	t.Run(
+		"nothing", func(t *testing.T) {
+			t.Parallel()
+
+			uid, fv, err := gl[0](&entriesGetterStub{})
+			require.NoError(t, err)
+			require.Equal(t, uint(2), uid)
+			require.Nil(t, fv)
+		},
+	)
+
+	t.Run(
+		"plain getter", func(t *testing.T) {
+			t.Parallel()
+
+			g := NewMockGetter(t)
+			g.On("Get", "Backends", n.Type).Return(nil, nil).Once()
+
+			uid, fv, err := gl[0](g)
+			require.NoError(t, err)
+			require.Equal(t, uint(2), uid)
+			require.Nil(t, fv)
+		},
+	)
+}
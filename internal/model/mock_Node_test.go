@@ -184,6 +184,54 @@ func (_c *MockNode_Fill_Call) RunAndReturn(run func(reflect.Value, []fvalue.Valu
 	return _c
 }
 
+// Validate provides a mock function with given fields: value
+func (_m *MockNode) Validate(value reflect.Value) []Violation {
+	ret := _m.Called(value)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Validate")
+	}
+
+	var r0 []Violation
+	if rf, ok := ret.Get(0).(func(reflect.Value) []Violation); ok {
+		r0 = rf(value)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]Violation)
+		}
+	}
+
+	return r0
+}
+
+// MockNode_Validate_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Validate'
+type MockNode_Validate_Call struct {
+	*mock.Call
+}
+
+// Validate is a helper method to define mock.On call
+//   - value reflect.Value
+func (_e *MockNode_Expecter) Validate(value interface{}) *MockNode_Validate_Call {
+	return &MockNode_Validate_Call{Call: _e.mock.On("Validate", value)}
+}
+
+func (_c *MockNode_Validate_Call) Run(run func(value reflect.Value)) *MockNode_Validate_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(reflect.Value))
+	})
+	return _c
+}
+
+func (_c *MockNode_Validate_Call) Return(_a0 []Violation) *MockNode_Validate_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockNode_Validate_Call) RunAndReturn(run func(reflect.Value) []Violation) *MockNode_Validate_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewMockNode creates a new instance of MockNode. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewMockNode(t interface {
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"reflect"
 	"sort"
+	"strings"
 
 	"github.com/byte4ever/dsco/internal"
 	"github.com/byte4ever/dsco/internal/fvalue"
@@ -17,6 +18,12 @@ type Model struct {
 	accelerator Node
 	getList     GetListInterface
 	expandList  ExpandListInterface
+	optional    map[string]struct{}
+	secret      map[string]struct{}
+	deprecated  map[string]struct{}
+	description map[string]string
+	keys        map[string]map[string]string
+	elements    map[string]*Model
 	typeName    string
 	fieldCount  uint
 }
@@ -65,13 +72,31 @@ func NewModel(inputModelType reflect.Type) (*Model, error) {
 
 	accelerator.BuildExpandList(&expandList)
 
-	return &Model{
-		fieldCount:  maxUID,
-		typeName:    registry.LongTypeName(inputModelType),
+	m := newFlaggedModel(accelerator)
+	m.fieldCount = maxUID
+	m.typeName = registry.LongTypeName(inputModelType)
+	m.getList = &getList
+	m.expandList = &expandList
+
+	return m, nil
+}
+
+// newFlaggedModel returns a model holding the flags collected from the node
+// sub-tree.
+func newFlaggedModel(accelerator Node) *Model {
+	m := &Model{
+		optional:    make(map[string]struct{}),
+		secret:      make(map[string]struct{}),
+		deprecated:  make(map[string]struct{}),
+		description: make(map[string]string),
+		keys:        make(map[string]map[string]string),
+		elements:    make(map[string]*Model),
 		accelerator: accelerator,
-		getList:     &getList,
-		expandList:  &expandList,
-	}, nil
+	}
+
+	m.collectFlagged(accelerator, false, false, false)
+
+	return m
 }
 
 func (m *Model) ApplyOn(g internal.ValueGetter) (fvalue.Values, error) {
@@ -108,6 +133,155 @@ func (m *Model) Fill(
 	)
 }
 
+// IsOptional returns true when the field located at path may be left nil,
+// being optional or part of an optional struct.
+func (m *Model) IsOptional(path string) bool {
+	_, found := m.optional[path]
+
+	return found
+}
+
+// IsSecret returns true when the value of the field located at path must
+// be redacted, being secret or part of a secret struct. Paths of collection
+// elements (i.e. Backends[eu].Password) are resolved on the element
+// sub-model.
+func (m *Model) IsSecret(path string) bool {
+	if _, found := m.secret[path]; found {
+		return true
+	}
+
+	collection, rest, found := splitEntryPath(path)
+	if !found {
+		return false
+	}
+
+	if _, found := m.secret[collection]; found {
+		return true
+	}
+
+	element := m.elements[collection]
+
+	return element != nil && element.IsSecret(rest)
+}
+
+// splitEntryPath splits the path of a collection element field into the
+// collection path and the element relative path.
+func splitEntryPath(path string) (collection, rest string, found bool) {
+	open := strings.IndexByte(path, '[')
+	if open < 0 {
+		return "", "", false
+	}
+
+	closing := strings.IndexByte(path[open:], ']')
+	if closing < 0 {
+		return "", "", false
+	}
+
+	return path[:open],
+		strings.TrimPrefix(path[open+closing+1:], "."),
+		true
+}
+
+// IsDeprecated returns true when the field located at path is deprecated,
+// being tagged deprecated or part of a deprecated struct. Fields of
+// collection elements are not included.
+func (m *Model) IsDeprecated(path string) bool {
+	_, found := m.deprecated[path]
+
+	return found
+}
+
+// Description returns the desc tag of the field located at path, or an
+// empty string.
+func (m *Model) Description(path string) string {
+	return m.description[path]
+}
+
+// TaggedKeys returns the keys set by the tag key tag (EnvTag, FlagTag or
+// FileTag) by field path. Fields of collection elements are not included.
+func (m *Model) TaggedKeys(tag string) map[string]string {
+	return m.keys[tag]
+}
+
+// collectFlagged collects the paths of the optional, secret and deprecated
+// fields of the node sub-tree, fields of flagged structs being flagged as
+// well, the field descriptions and key tags.
+func (m *Model) collectFlagged(node Node, optional, secret, deprecated bool) {
+	var (
+		path, description string
+		keys              map[string]string
+	)
+
+	switch n := node.(type) {
+	case *StructNode:
+		for _, index := range n.Index {
+			m.collectFlagged(
+				index.Node,
+				optional || n.Optional,
+				secret || n.Secret,
+				deprecated || n.Deprecated,
+			)
+		}
+
+		return
+	case *ValueNode:
+		path, description, keys = n.VisiblePath, n.Description, n.Keys
+		optional, secret = optional || n.Optional, secret || n.Secret
+		deprecated = deprecated || n.Deprecated
+	case *MapNode:
+		path, description, keys = n.VisiblePath, n.Description, n.Keys
+		optional, secret = optional || n.Optional, secret || n.Secret
+		deprecated = deprecated || n.Deprecated
+		m.collectElement(path, n.Type.Elem())
+	case *SliceNode:
+		path, description, keys = n.VisiblePath, n.Description, n.Keys
+		optional, secret = optional || n.Optional, secret || n.Secret
+		deprecated = deprecated || n.Deprecated
+		m.collectElement(path, n.Type.Elem())
+	}
+
+	if description != "" {
+		m.description[path] = description
+	}
+
+	for tag, key := range keys {
+		if m.keys[tag] == nil {
+			m.keys[tag] = make(map[string]string)
+		}
+
+		m.keys[tag][path] = key
+	}
+
+	if optional {
+		m.optional[path] = struct{}{}
+	}
+
+	if secret {
+		m.secret[path] = struct{}{}
+	}
+
+	if deprecated {
+		m.deprecated[path] = struct{}{}
+	}
+}
+
+// collectElement records the flags of the elements of the collection
+// located at path.
+func (m *Model) collectElement(path string, elemType reflect.Type) {
+	var uid uint
+
+	// element type is checked when scanning the collection
+	if elem, _ := scanEntry(&uid, "", elemType); elem != nil {
+		m.elements[path] = newFlaggedModel(elem)
+	}
+}
+
+// Validate checks the filled value against the field rules and calls the
+// Validate method of every struct providing one.
+func (m *Model) Validate(inputModelValue reflect.Value) []Violation {
+	return m.accelerator.Validate(inputModelValue)
+}
+
 func (s *stackEmbed) pushToStack(
 	index []int, depth int, path string, _type reflect.Type,
 ) error {
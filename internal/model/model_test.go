@@ -10,6 +10,7 @@ import (
 
 	"github.com/byte4ever/dsco/internal/fvalue"
 	"github.com/byte4ever/dsco/internal/plocation"
+	"github.com/byte4ever/dsco/ref"
 )
 
 func Test_stackEmbed_pushToStack(t *testing.T) {
@@ -756,3 +757,300 @@ func TestModel_GetFieldValuesFor(t *testing.T) {
 
 	require.Empty(t, gotFvs)
 }
+
+func TestModel_optional(t *testing.T) {
+	t.Parallel()
+
+	type TLS struct {
+		Cert *string
+		Key  *string
+	}
+
+	type Root struct {
+		Host      *string
+		Proxy     *string             `dsco:"optional"`
+		TLS       *TLS                `dsco:"optional"`
+		Labels    map[string]string   `dsco:"optional"`
+		Listeners []*TLS              `dsco:"optional"`
+		Required  map[string]*float64 `dsco:"min=1"`
+	}
+
+	m, err := NewModel(reflect.TypeOf(&Root{}))
+	require.NoError(t, err)
+
+	for path, want := range map[string]bool{
+		"Host":      false,
+		"Proxy":     true,
+		"TLS.Cert":  true,
+		"TLS.Key":   true,
+		"Labels":    true,
+		"Listeners": true,
+		"Required":  false,
+	} {
+		require.Equal(t, want, m.IsOptional(path), path)
+	}
+
+	t.Run(
+		"left nil", func(t *testing.T) {
+			t.Parallel()
+
+			var root *Root
+
+			_, err := m.Fill(
+				reflect.ValueOf(&root).Elem(),
+				[]fvalue.Values{
+					{
+						0: {Value: reflect.ValueOf(ref.R("h")), Location: "l0"},
+						6: {
+							Value:    reflect.ValueOf(map[string]*float64{}),
+							Location: "l6",
+						},
+					},
+				},
+			)
+			require.NoError(t, err)
+			require.Equal(t, "h", *root.Host)
+			require.Nil(t, root.Proxy)
+			require.Nil(t, root.TLS)
+			require.Nil(t, root.Labels)
+			require.Nil(t, root.Listeners)
+		},
+	)
+
+	t.Run(
+		"partial optional struct", func(t *testing.T) {
+			t.Parallel()
+
+			var root *Root
+
+			_, err := m.Fill(
+				reflect.ValueOf(&root).Elem(),
+				[]fvalue.Values{
+					{
+						0: {Value: reflect.ValueOf(ref.R("h")), Location: "l0"},
+						2: {Value: reflect.ValueOf(ref.R("c")), Location: "l2"},
+						6: {
+							Value:    reflect.ValueOf(map[string]*float64{}),
+							Location: "l6",
+						},
+					},
+				},
+			)
+			require.ErrorContains(t, err, "TLS.Key-[*string]: uninitialized key")
+			require.Equal(t, "c", *root.TLS.Cert)
+		},
+	)
+}
+
+func TestModel_IsDeprecated(t *testing.T) {
+	t.Parallel()
+
+	type DB struct {
+		User *string
+		Host *string `dsco:"deprecated"`
+	}
+
+	type Root struct {
+		DB      *DB
+		Tokens  map[string]string `dsco:"deprecated"`
+		Legacy  *DB               `dsco:"deprecated"`
+		Servers []*DB
+	}
+
+	m, err := NewModel(reflect.TypeOf(&Root{}))
+	require.NoError(t, err)
+
+	for path, want := range map[string]bool{
+		"DB.User":     false,
+		"DB.Host":     true,
+		"Tokens":      true,
+		"Legacy.User": true,
+		"Legacy.Host": true,
+		"Servers":     false,
+	} {
+		require.Equal(t, want, m.IsDeprecated(path), path)
+	}
+}
+
+func TestModel_IsSecret_elements(t *testing.T) {
+	t.Parallel()
+
+	type DB struct {
+		User     *string
+		Password *string `dsco:"secret"`
+	}
+
+	type Root struct {
+		Tokens   map[string]string `dsco:"secret"`
+		Backends map[string]*DB
+		Replicas []*DB
+		Zones    map[string]map[string]*DB
+	}
+
+	m, err := NewModel(reflect.TypeOf(&Root{}))
+	require.NoError(t, err)
+
+	for path, want := range map[string]bool{
+		"Tokens[api]":           true,
+		"Backends[eu].User":     false,
+		"Backends[eu].Password": true,
+		"Replicas[0].User":      false,
+		"Replicas[0].Password":  true,
+		"Zones[eu][a].User":     false,
+		"Zones[eu][a].Password": true,
+	} {
+		require.Equal(t, want, m.IsSecret(path), path)
+	}
+}
+
+func TestModel_secret(t *testing.T) {
+	t.Parallel()
+
+	type DB struct {
+		User     *string
+		Password *string `dsco:"secret"`
+	}
+
+	type Root struct {
+		DB     *DB
+		Tokens map[string]string `dsco:"secret"`
+		Keys   *DB               `dsco:"secret"`
+	}
+
+	m, err := NewModel(reflect.TypeOf(&Root{}))
+	require.NoError(t, err)
+
+	for path, want := range map[string]bool{
+		"DB.User":       false,
+		"DB.Password":   true,
+		"Tokens":        true,
+		"Keys.User":     true,
+		"Keys.Password": true,
+	} {
+		require.Equal(t, want, m.IsSecret(path), path)
+	}
+
+	var root *Root
+
+	str := func(s string) *fvalue.Value {
+		return &fvalue.Value{Value: reflect.ValueOf(ref.R(s)), Location: s}
+	}
+
+	pl, err := m.Fill(
+		reflect.ValueOf(&root).Elem(),
+		[]fvalue.Values{
+			{
+				0: str("user"),
+				1: str("password"),
+				2: {
+					Entries: map[string]fvalue.Values{
+						"a": {
+							0: {
+								Value:    reflect.ValueOf("token"),
+								Location: "token",
+							},
+						},
+					},
+				},
+				3: str("key-user"),
+				4: str("key-password"),
+			},
+		},
+	)
+	require.NoError(t, err)
+
+	secret := make(map[string]bool)
+	for _, location := range pl {
+		secret[location.Path] = location.Secret
+	}
+
+	require.Equal(
+		t,
+		map[string]bool{
+			"DB.User":       false,
+			"DB.Password":   true,
+			"Tokens[a]":     true,
+			"Keys.User":     true,
+			"Keys.Password": true,
+		},
+		secret,
+	)
+}
+
+func TestModel_Description(t *testing.T) {
+	t.Parallel()
+
+	type Listener struct {
+		Port *int `desc:"Listening port"`
+	}
+
+	type Root struct {
+		Host      *string           `desc:" Host name "`
+		Password  *string           `dsco:"secret" desc:"Database password"`
+		Backends  map[string]string `desc:"Backend URLs"`
+		Listeners []*Listener       `desc:"Listeners"`
+		Plain     *string
+		Nested    *Listener
+	}
+
+	m, err := NewModel(reflect.TypeOf(&Root{}))
+	require.NoError(t, err)
+
+	for path, want := range map[string]string{
+		"Host":        "Host name",
+		"Password":    "Database password",
+		"Backends":    "Backend URLs",
+		"Listeners":   "Listeners",
+		"Plain":       "",
+		"Nested.Port": "Listening port",
+		"Unknown":     "",
+	} {
+		require.Equal(t, want, m.Description(path), path)
+	}
+}
+
+func TestModel_TaggedKeys(t *testing.T) {
+	t.Parallel()
+
+	type Listener struct {
+		Port *int `flag:"port"`
+	}
+
+	type Database struct {
+		URL *string `env:"DATABASE_URL" flag:"db"`
+	}
+
+	type Root struct {
+		Database  *Database
+		Backends  map[string]string `file:"upstreams"`
+		Listeners []*Listener
+		Plain     *string
+	}
+
+	m, err := NewModel(reflect.TypeOf(&Root{}))
+	require.NoError(t, err)
+
+	require.Equal(
+		t,
+		map[string]string{"Database.URL": "DATABASE_URL"},
+		m.TaggedKeys(EnvTag),
+	)
+	require.Equal(
+		t,
+		map[string]string{"Database.URL": "db"},
+		m.TaggedKeys(FlagTag),
+	)
+	require.Equal(
+		t,
+		map[string]string{"Backends": "upstreams"},
+		m.TaggedKeys(FileTag),
+	)
+
+	type Invalid struct {
+		Database *Database `flag:"db" env:"DB"`
+	}
+
+	_, err = NewModel(reflect.TypeOf(&Invalid{}))
+	require.ErrorContains(t, err, `"env:DB flag:db"`)
+	require.ErrorContains(t, err, "key tags are not supported by structs")
+}
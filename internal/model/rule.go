@@ -0,0 +1,296 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	ruleMin    = "min"
+	ruleMax    = "max"
+	ruleOneOf  = "oneof"
+	ruleRegexp = "regexp"
+
+	// ruleValidate is the rule name reported for Validate method failures.
+	ruleValidate = "Validate()"
+
+	oneOfSep = " "
+)
+
+// errBadRule represents an error where a rule cannot be applied on a field.
+var errBadRule = errors.New("")
+
+// ErrRule represents an error where a value does not satisfy a rule.
+var ErrRule = errors.New("rule violated")
+
+// validator is implemented by structs providing their own validation.
+type validator interface {
+	Validate() error
+}
+
+// Rule is a declarative validation rule of a dsco struct tag (i.e. min=1).
+type Rule struct {
+	Name  string
+	Value string
+}
+
+func (r Rule) String() string {
+	return r.Name + tagValueSep + r.Value
+}
+
+// Rules is the list of rules of a field.
+type Rules []Rule
+
+// Violation is a validation failure of the value located at Path.
+type Violation struct {
+	Path string
+	Rule string
+	Err  error
+}
+
+// supports returns an error when the rule cannot be applied on a field of
+// type _type (i.e. unsupported kind, invalid bound or regular expression).
+func (r Rule) supports(_type reflect.Type) error {
+	if _type.Kind() == reflect.Pointer &&
+		_type.Elem().Kind() == reflect.Struct {
+		return fmt.Errorf("rule %s not supported by struct", r.Name)
+	}
+
+	value := reflect.New(_type).Elem()
+	if _type.Kind() == reflect.Pointer {
+		value = reflect.New(_type.Elem())
+	}
+
+	if err := r.check(value); errors.Is(err, errBadRule) {
+		return err
+	}
+
+	return nil
+}
+
+// check returns an ErrRule error when value does not satisfy the rule, or
+// an errBadRule error when the rule cannot be applied on value.
+func (r Rule) check(value reflect.Value) error {
+	if value.Kind() == reflect.Pointer {
+		value = value.Elem()
+	}
+
+	switch r.Name {
+	case ruleMin, ruleMax:
+		return r.checkBound(value)
+	case ruleOneOf:
+		return r.checkOneOf(value)
+	default:
+		return r.checkRegexp(value)
+	}
+}
+
+func (r Rule) checkBound(value reflect.Value) error {
+	cmp, length, err := compareTo(value, r.Value)
+	if err != nil {
+		return err
+	}
+
+	var subject string
+	if length {
+		subject = "length "
+	}
+
+	switch {
+	case r.Name == ruleMin && cmp < 0:
+		return fmt.Errorf("%smust be at least %s: %w", subject, r.Value, ErrRule)
+	case r.Name == ruleMax && cmp > 0:
+		return fmt.Errorf("%smust be at most %s: %w", subject, r.Value, ErrRule)
+	default:
+		return nil
+	}
+}
+
+func (r Rule) checkOneOf(value reflect.Value) error {
+	options := strings.Fields(r.Value)
+	if len(options) == 0 {
+		return fmt.Errorf("rule %s without value%w", r.Name, errBadRule)
+	}
+
+	switch value.Kind() { //nolint:exhaustive // other kinds are not supported
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64, reflect.Float32, reflect.Float64:
+	default:
+		return fmt.Errorf(
+			"rule %s not supported by %s%w",
+			r.Name,
+			value.Kind(),
+			errBadRule,
+		)
+	}
+
+	text := fmt.Sprint(value.Interface())
+
+	for _, option := range options {
+		if option == text {
+			return nil
+		}
+	}
+
+	return fmt.Errorf(
+		"must be one of %s: %w",
+		strings.Join(options, oneOfSep),
+		ErrRule,
+	)
+}
+
+func (r Rule) checkRegexp(value reflect.Value) error {
+	if value.Kind() != reflect.String {
+		return fmt.Errorf(
+			"rule %s not supported by %s%w",
+			r.Name,
+			value.Kind(),
+			errBadRule,
+		)
+	}
+
+	exp, err := regexp.Compile(r.Value)
+	if err != nil {
+		return fmt.Errorf("%s%w", err.Error(), errBadRule)
+	}
+
+	if !exp.MatchString(value.String()) {
+		return fmt.Errorf("must match %s: %w", r.Value, ErrRule)
+	}
+
+	return nil
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// compareTo compares value to bound, returning -1, 0 or 1. Strings, slices
+// and maps are compared by length, in which case length is true.
+func compareTo(value reflect.Value, bound string) (int, bool, error) {
+	badBound := func() (int, bool, error) {
+		return 0, false, fmt.Errorf("invalid bound %q%w", bound, errBadRule)
+	}
+
+	switch value.Kind() { //nolint:exhaustive // other kinds are not supported
+	case reflect.String, reflect.Slice, reflect.Map:
+		b, err := strconv.Atoi(bound)
+		if err != nil || b < 0 {
+			return badBound()
+		}
+
+		return compare(value.Len(), b), true, nil
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Int64:
+		var (
+			b   int64
+			err error
+		)
+
+		if value.Type() == durationType {
+			var d time.Duration
+
+			d, err = time.ParseDuration(bound)
+			b = int64(d)
+		} else {
+			b, err = strconv.ParseInt(bound, 10, 64)
+		}
+
+		if err != nil {
+			return badBound()
+		}
+
+		return compare(value.Int(), b), false, nil
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64:
+		b, err := strconv.ParseUint(bound, 10, 64)
+		if err != nil {
+			return badBound()
+		}
+
+		return compare(value.Uint(), b), false, nil
+
+	case reflect.Float32, reflect.Float64:
+		b, err := strconv.ParseFloat(bound, 64)
+		if err != nil {
+			return badBound()
+		}
+
+		return compare(value.Float(), b), false, nil
+
+	default:
+		return 0, false, fmt.Errorf(
+			"bound not supported by %s%w",
+			value.Kind(),
+			errBadRule,
+		)
+	}
+}
+
+func compare[T int | int64 | uint64 | float64](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// checkRules checks the value located at path against rules. Nil values
+// are not checked.
+func checkRules(path string, rules Rules, value reflect.Value) []Violation {
+	if len(rules) == 0 || isNil(value) {
+		return nil
+	}
+
+	var violations []Violation
+
+	for _, rule := range rules {
+		if err := rule.check(value); err != nil {
+			violations = append(
+				violations,
+				Violation{
+					Path: path,
+					Rule: rule.String(),
+					Err:  err,
+				},
+			)
+		}
+	}
+
+	return violations
+}
+
+// validateStruct calls the Validate method of the struct value located at
+// path, if any.
+func validateStruct(path string, value reflect.Value) []Violation {
+	if !value.CanInterface() || isNil(value) {
+		return nil
+	}
+
+	v, ok := value.Interface().(validator)
+	if !ok {
+		return nil
+	}
+
+	if err := v.Validate(); err != nil {
+		return []Violation{
+			{
+				Path: path,
+				Rule: ruleValidate,
+				Err:  err,
+			},
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,171 @@
+package model
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/byte4ever/dsco/ref"
+)
+
+func TestRule_check(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range []struct {
+		rule    Rule
+		value   any
+		wantErr string
+	}{
+		{Rule{ruleMin, "1"}, ref.R(1), ""},
+		{Rule{ruleMin, "1"}, ref.R(0), "must be at least 1"},
+		{Rule{ruleMax, "65535"}, ref.R(70000), "must be at most 65535"},
+		{Rule{ruleMax, "10"}, ref.R(uint8(11)), "must be at most 10"},
+		{Rule{ruleMin, "0.5"}, ref.R(0.25), "must be at least 0.5"},
+		{Rule{ruleMin, "1s"}, ref.R(time.Second), ""},
+		{Rule{ruleMax, "1s"}, ref.R(time.Minute), "must be at most 1s"},
+		{Rule{ruleMin, "2"}, ref.R("a"), "length must be at least 2"},
+		{Rule{ruleMax, "1"}, []string{"a", "b"}, "length must be at most 1"},
+		{Rule{ruleOneOf, "debug info"}, ref.R("info"), ""},
+		{Rule{ruleOneOf, "debug info"}, ref.R("warn"), "must be one of debug info"},
+		{Rule{ruleOneOf, "1s 5s"}, ref.R(5 * time.Second), ""},
+		{Rule{ruleRegexp, "^[a-z]+$"}, ref.R("abc"), ""},
+		{Rule{ruleRegexp, "^[a-z]+$"}, ref.R("a1"), "must match ^[a-z]+$"},
+	} {
+		err := tt.rule.check(reflect.ValueOf(tt.value))
+		if tt.wantErr == "" {
+			require.NoError(t, err, tt.rule)
+			continue
+		}
+
+		require.ErrorIs(t, err, ErrRule, tt.rule)
+		require.ErrorContains(t, err, tt.wantErr, tt.rule)
+	}
+}
+
+func TestRule_supports(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range []struct {
+		rule    Rule
+		_type   reflect.Type
+		wantErr string
+	}{
+		{Rule{ruleMin, "1"}, reflect.TypeOf(ref.R(0)), ""},
+		{Rule{ruleMin, "1"}, reflect.TypeOf(map[string]string{}), ""},
+		{Rule{ruleMax, "2"}, reflect.TypeOf([]*struct{}{}), ""},
+		{Rule{ruleMin, "x"}, reflect.TypeOf(ref.R(0)), `invalid bound "x"`},
+		{Rule{ruleMin, "-1"}, reflect.TypeOf(ref.R("")), `invalid bound "-1"`},
+		{Rule{ruleMin, "1m"}, reflect.TypeOf(ref.R(uint(0))), `invalid bound "1m"`},
+		{Rule{ruleMin, "1m"}, reflect.TypeOf(ref.R(0.0)), `invalid bound "1m"`},
+		{Rule{ruleMin, "1"}, reflect.TypeOf(ref.R(true)), "bound not supported by bool"},
+		{Rule{ruleMin, "1"}, reflect.TypeOf(&struct{}{}), "rule min not supported by struct"},
+		{Rule{ruleOneOf, ""}, reflect.TypeOf(ref.R("")), "rule oneof without value"},
+		{Rule{ruleOneOf, "a"}, reflect.TypeOf([]string{}), "rule oneof not supported by slice"},
+		{Rule{ruleRegexp, "("}, reflect.TypeOf(ref.R("")), "missing closing )"},
+		{Rule{ruleRegexp, "a"}, reflect.TypeOf(ref.R(0)), "rule regexp not supported by int"},
+	} {
+		err := tt.rule.supports(tt._type)
+		if tt.wantErr == "" {
+			require.NoError(t, err, tt.rule)
+			continue
+		}
+
+		require.ErrorContains(t, err, tt.wantErr, tt.rule)
+	}
+}
+
+type validatedBackend struct {
+	Port *int `dsco:"max=100"`
+}
+
+var errNoPort = errors.New("no port")
+
+func (b *validatedBackend) Validate() error {
+	if *b.Port == 0 {
+		return errNoPort
+	}
+
+	return nil
+}
+
+func TestModel_Validate(t *testing.T) {
+	t.Parallel()
+
+	type Root struct {
+		Name     *string                      `dsco:"regexp=^[a-z]+$"`
+		Backends map[string]*validatedBackend `dsco:"min=1"`
+		Backend  *validatedBackend
+		Extra    []*validatedBackend `dsco:"max=1"`
+		Opt      *int                `dsco:"min=1"`
+	}
+
+	m, err := NewModel(reflect.TypeOf(&Root{}))
+	require.NoError(t, err)
+
+	root := &Root{
+		Name: ref.R("A"),
+		Backends: map[string]*validatedBackend{
+			"b": {Port: ref.R(101)},
+			"a": {Port: ref.R(0)},
+		},
+		Backend: &validatedBackend{Port: ref.R(1)},
+		Extra: []*validatedBackend{
+			{Port: ref.R(1)},
+			{Port: ref.R(0)},
+		},
+	}
+
+	violations := m.Validate(reflect.ValueOf(root))
+
+	type result struct {
+		path, rule string
+	}
+
+	var got []result
+	for _, violation := range violations {
+		require.Error(t, violation.Err)
+		got = append(got, result{violation.Path, violation.Rule})
+	}
+
+	require.Equal(
+		t,
+		[]result{
+			{"Name", "regexp=^[a-z]+$"},
+			{"Backends[a]", "Validate()"},
+			{"Backends[b].Port", "max=100"},
+			{"Extra", "max=1"},
+			{"Extra[1]", "Validate()"},
+		},
+		got,
+	)
+	require.ErrorIs(t, violations[1].Err, errNoPort)
+
+	require.Empty(t, m.Validate(reflect.ValueOf((*Root)(nil))))
+	require.Empty(
+		t,
+		m.Validate(
+			reflect.ValueOf(
+				&Root{Backend: &validatedBackend{Port: ref.R(1)}},
+			),
+		),
+	)
+}
+
+func Test_scanField_invalidRule(t *testing.T) {
+	t.Parallel()
+
+	type Root struct {
+		Port *int `dsco:"min=x"`
+	}
+
+	_, err := NewModel(reflect.TypeOf(&Root{}))
+	require.ErrorIs(t, err, ErrModel)
+	require.ErrorContains(
+		t,
+		err,
+		`struct field Port with invalid tag "min=x": invalid bound "x"`,
+	)
+}
@@ -1,6 +1,7 @@
 package model
 
 import (
+	"fmt"
 	"reflect"
 
 	"github.com/byte4ever/dsco/internal/merror"
@@ -13,23 +14,83 @@ func scan(
 	path string,
 	_type reflect.Type,
 ) (Node, merror.MError) {
+	return scanField(uid, path, _type, fieldTag{})
+}
+
+//nolint:ireturn // expected to build abstract tree nodes
+func scanField(
+	uid *uint,
+	path string,
+	_type reflect.Type,
+	tag fieldTag,
+) (Node, merror.MError) {
+	if tag.merge != "" && !tag.merge.supports(_type) {
+		return nil, merror.MError{
+			InvalidTagError{
+				Path: path,
+				Tag:  tagMerge + tagValueSep + string(tag.merge),
+				Reason: fmt.Sprintf(
+					"merge policy not supported by %s",
+					registry.LongTypeName(_type),
+				),
+			},
+		}
+	}
+
+	for _, rule := range tag.rules {
+		if err := rule.supports(_type); err != nil {
+			return nil, merror.MError{
+				InvalidTagError{
+					Path:   path,
+					Tag:    rule.String(),
+					Reason: err.Error(),
+				},
+			}
+		}
+	}
+
 	switch {
-	case _type.Kind() == reflect.Slice || registry.TypeIsRegistered(_type):
+	case IsIndexedSlice(_type):
+		return scanSlice(uid, path, _type, tag)
+
+	case _type.Kind() == reflect.Slice || registry.TypeIsDecodable(_type):
 		valueNode := &ValueNode{
 			UID:         *uid,
 			Type:        _type,
 			VisiblePath: path,
+			Rules:       tag.rules,
+			Optional:    tag.optional,
+			Secret:      tag.secret,
+			Deprecated:  tag.deprecated,
+			Description: tag.desc,
+			Keys:        tag.keys,
 		}
 		*uid++
 
 		return valueNode, nil
 
+	case _type.Kind() == reflect.Map:
+		return scanMap(uid, path, _type, tag)
+
 	case _type.Kind() == reflect.Pointer && _type.Elem().Kind() == reflect.Struct:
 		var errs merror.MError
 
+		if len(tag.keys) > 0 {
+			return nil, merror.MError{
+				InvalidTagError{
+					Path:   path,
+					Tag:    tag.keysString(),
+					Reason: "key tags are not supported by structs",
+				},
+			}
+		}
+
 		structNode := &StructNode{
 			Type:        _type,
 			VisiblePath: path,
+			Optional:    tag.optional,
+			Secret:      tag.secret,
+			Deprecated:  tag.deprecated,
 		}
 
 		visibleFields, lErrs := getVisibleFieldList(path, _type)
@@ -38,11 +99,22 @@ func scan(
 		}
 
 		for _, field := range visibleFields {
-			subNode, subErrs := scan(
-				uid, pathTo(
-					path,
-					field.field.Name,
-				), field.field.Type,
+			fieldPath := pathTo(
+				path,
+				field.field.Name,
+			)
+
+			fieldTag, err := parseTag(fieldPath, field.field)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+
+			subNode, subErrs := scanField(
+				uid,
+				fieldPath,
+				field.field.Type,
+				fieldTag,
 			)
 
 			if !subErrs.None() {
@@ -64,3 +136,141 @@ func scan(
 		}
 	}
 }
+
+// scanMap scans a map field. Keys must be strings and the element type is
+// scanned once to check it is supported and to collect the element relative
+// paths.
+//
+//nolint:ireturn // expected to build abstract tree nodes
+func scanMap(
+	uid *uint,
+	path string,
+	_type reflect.Type,
+	tag fieldTag,
+) (Node, merror.MError) {
+	if _type.Key().Kind() != reflect.String {
+		return nil, merror.MError{
+			UnsupportedTypeError{
+				Path: path,
+				Type: _type,
+			},
+		}
+	}
+
+	mapNode := &MapNode{
+		UID:         *uid,
+		Type:        _type,
+		VisiblePath: path,
+		Merge:       tag.merge,
+		Rules:       tag.rules,
+		Optional:    tag.optional,
+		Secret:      tag.secret,
+		Deprecated:  tag.deprecated,
+		Description: tag.desc,
+		Keys:        tag.keys,
+	}
+
+	if errs := mapNode.scanElem(_type.Elem()); !errs.None() {
+		return nil, errs
+	}
+
+	*uid++
+
+	if mapNode.Merge == "" {
+		mapNode.Merge = MergeKeys
+	}
+
+	return mapNode, nil
+}
+
+// scanSlice scans a slice of struct pointers.
+//
+//nolint:ireturn // expected to build abstract tree nodes
+func scanSlice(
+	uid *uint,
+	path string,
+	_type reflect.Type,
+	tag fieldTag,
+) (Node, merror.MError) {
+	sliceNode := &SliceNode{
+		UID:         *uid,
+		Type:        _type,
+		VisiblePath: path,
+		Merge:       tag.merge,
+		Rules:       tag.rules,
+		Optional:    tag.optional,
+		Secret:      tag.secret,
+		Deprecated:  tag.deprecated,
+		Description: tag.desc,
+		Keys:        tag.keys,
+	}
+
+	if errs := sliceNode.scanElem(_type.Elem()); !errs.None() {
+		return nil, errs
+	}
+
+	*uid++
+
+	if sliceNode.Merge == "" {
+		sliceNode.Merge = MergeIndex
+	}
+
+	return sliceNode, nil
+}
+
+// scanEntry scans a collection element. Unlike struct fields, elements can
+// be plain values of registered types (i.e. string in map[string]string).
+//
+//nolint:ireturn // expected to build abstract tree nodes
+func scanEntry(
+	uid *uint,
+	path string,
+	_type reflect.Type,
+) (Node, merror.MError) {
+	if _type.Kind() != reflect.Pointer &&
+		registry.TypeIsDecodable(reflect.PointerTo(_type)) {
+		valueNode := &ValueNode{
+			UID:         *uid,
+			Type:        _type,
+			VisiblePath: path,
+		}
+		*uid++
+
+		return valueNode, nil
+	}
+
+	return scan(uid, path, _type)
+}
+
+// collectSubPaths collects the relative visible paths of an element node.
+// Every path is a leaf and map paths are prefixes as well.
+func collectSubPaths(node Node, leaves, prefixes *[]string) {
+	switch n := node.(type) {
+	case *StructNode:
+		if n.VisiblePath != "" {
+			*leaves = append(*leaves, n.VisiblePath)
+		}
+
+		for _, index := range n.Index {
+			collectSubPaths(index.Node, leaves, prefixes)
+		}
+	case *MapNode:
+		collectCollectionPath(n.VisiblePath, leaves, prefixes)
+	case *SliceNode:
+		collectCollectionPath(n.VisiblePath, leaves, prefixes)
+	case *ValueNode:
+		if n.VisiblePath != "" {
+			*leaves = append(*leaves, n.VisiblePath)
+		}
+	}
+}
+
+// collectCollectionPath collects the relative path of a collection, an
+// empty prefix standing for an element that is a collection itself.
+func collectCollectionPath(path string, leaves, prefixes *[]string) {
+	if path != "" {
+		*leaves = append(*leaves, path)
+	}
+
+	*prefixes = append(*prefixes, path)
+}
@@ -0,0 +1,220 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+
+	"github.com/byte4ever/dsco/internal"
+	"github.com/byte4ever/dsco/internal/fvalue"
+	"github.com/byte4ever/dsco/internal/merror"
+	"github.com/byte4ever/dsco/internal/plocation"
+)
+
+// SliceNode is a slice of struct pointers. Every element is modelled by an
+// entry sub-model rooted at the element path (i.e. "Listeners[0]"), so
+// elements provided by several layers can be merged leaf by leaf.
+type SliceNode struct {
+	entriesInfo
+	Type        reflect.Type
+	VisiblePath string
+	UID         uint
+	Merge       MergePolicy
+	Rules       Rules
+	Optional    bool
+	Secret      bool
+	Deprecated  bool
+	Description string
+	Keys        map[string]string
+}
+
+type SliceNodeError struct {
+	merror.MError
+}
+
+var ErrSliceNode = errors.New("")
+
+func (SliceNodeError) Is(err error) bool {
+	return errors.Is(err, ErrSliceNode)
+}
+
+// isIndex returns true when key is the canonical form of a slice index.
+func isIndex(key string) bool {
+	index, err := strconv.Atoi(key)
+
+	return err == nil && index >= 0 && strconv.Itoa(index) == key
+}
+
+// indexes returns the sorted indexes of the source entries.
+func indexes(source *fvalue.Value) []int {
+	result := make([]int, 0, len(source.Entries))
+
+	for key := range source.Entries {
+		index, _ := strconv.Atoi(key)
+		result = append(result, index)
+	}
+
+	sort.Ints(result)
+
+	return result
+}
+
+func (n *SliceNode) Fill(
+	value reflect.Value, layers []fvalue.Values,
+) (plocation.Locations, error) {
+	sources := sourcesOf(n.UID, layers)
+
+	if len(sources) == 0 && n.Optional {
+		return nil, nil
+	}
+
+	if len(sources) == 0 {
+		return nil, UninitializedKeyError{
+			Type: value.Type(),
+			Path: n.VisiblePath,
+		}
+	}
+
+	if n.Merge == MergeReplace {
+		sources = sources[:1]
+	}
+
+	// element layers of every element of the resulting slice
+	var elems [][]fvalue.Values
+
+	if n.Merge == MergeAppend {
+		for i := len(sources) - 1; i >= 0; i-- {
+			for _, index := range indexes(sources[i]) {
+				elems = append(
+					elems,
+					[]fvalue.Values{
+						sources[i].Entries[strconv.Itoa(index)],
+					},
+				)
+			}
+		}
+	} else {
+		length := 0
+
+		for _, source := range sources {
+			if sourceIndexes := indexes(source); len(sourceIndexes) > 0 {
+				length = max(length, sourceIndexes[len(sourceIndexes)-1]+1)
+			}
+		}
+
+		for i := 0; i < length; i++ {
+			elems = append(elems, entryLayers(sources, strconv.Itoa(i)))
+		}
+	}
+
+	var (
+		pl   plocation.Locations
+		errs SliceNodeError
+	)
+
+	result := reflect.MakeSlice(n.Type, len(elems), len(elems))
+
+	for i, elemLayers := range elems {
+		pln, err := entryNode(
+			n.VisiblePath,
+			n.Type.Elem(),
+			strconv.Itoa(i),
+		).Fill(
+			result.Index(i),
+			elemLayers,
+		)
+		if err != nil {
+			errs.Add(err)
+		}
+
+		// element locations are reported for the slice field
+		for j := range pln {
+			pln[j].UID = n.UID
+			pln[j].Secret = pln[j].Secret || n.Secret
+		}
+
+		pl.Append(pln)
+	}
+
+	value.Set(result)
+
+	releaseEntries(n.UID, layers)
+
+	if errs.None() {
+		return pl, nil
+	}
+
+	return pl, errs
+}
+
+func (n *SliceNode) FeedFieldValues(
+	srcID string,
+	fieldValues fvalue.Values,
+	value reflect.Value,
+) {
+	if value.IsNil() {
+		return
+	}
+
+	entries := make(map[string]fvalue.Values, value.Len())
+
+	for i := 0; i < value.Len(); i++ {
+		feedEntry(
+			srcID,
+			n.VisiblePath,
+			n.Type.Elem(),
+			strconv.Itoa(i),
+			value.Index(i),
+			entries,
+		)
+	}
+
+	fieldValues[n.UID] = &fvalue.Value{
+		Value: value,
+		Location: fmt.Sprintf(
+			"struct[%s]:%s",
+			srcID,
+			n.VisiblePath,
+		),
+		Path:    n.VisiblePath,
+		Entries: entries,
+	}
+}
+
+func (n *SliceNode) BuildGetList(s *GetList) {
+	s.Push(
+		func(g internal.ValueGetter) (uint, *fvalue.Value, error) {
+			fieldValue, err := n.getEntries(
+				g,
+				n.VisiblePath,
+				n.Type,
+				isIndex,
+			)
+
+			return n.UID, fieldValue, err
+		},
+	)
+}
+
+func (*SliceNode) BuildExpandList(*ExpandList) {}
+
+func (n *SliceNode) Validate(value reflect.Value) []Violation {
+	if value.IsNil() {
+		return nil
+	}
+
+	violations := checkRules(n.VisiblePath, n.Rules, value)
+
+	for i := 0; i < value.Len(); i++ {
+		violations = append(
+			violations,
+			entryNode(n.VisiblePath, n.Type.Elem(), strconv.Itoa(i)).Validate(
+				value.Index(i),
+			)...,
+		)
+	}
+
+	return violations
+}
@@ -0,0 +1,280 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/byte4ever/dsco/internal/fvalue"
+	"github.com/byte4ever/dsco/ref"
+)
+
+type sliceListener struct {
+	Port *int
+}
+
+func TestIsIndexedSlice(t *testing.T) {
+	t.Parallel()
+
+	require.True(t, IsIndexedSlice(reflect.TypeOf([]*sliceListener{})))
+	require.False(t, IsIndexedSlice(reflect.TypeOf([]sliceListener{})))
+	require.False(t, IsIndexedSlice(reflect.TypeOf([]*time.Time{})))
+	require.False(t, IsIndexedSlice(reflect.TypeOf([]string{})))
+	require.False(t, IsIndexedSlice(reflect.TypeOf(&sliceListener{})))
+}
+
+func Test_isIndex(t *testing.T) {
+	t.Parallel()
+
+	require.True(t, isIndex("0"))
+	require.True(t, isIndex("12"))
+	require.False(t, isIndex("01"))
+	require.False(t, isIndex("-1"))
+	require.False(t, isIndex("eu"))
+}
+
+func Test_scanSlice(t *testing.T) {
+	t.Parallel()
+
+	type Root struct {
+		Listeners []*sliceListener
+		Appended  []*sliceListener `dsco:"merge=append"`
+		Times     []*time.Time
+		BadMerge  []*sliceListener `dsco:"merge=keys"`
+	}
+
+	var maxUID uint
+
+	node, errs := scan(&maxUID, "", reflect.TypeOf(&Root{}))
+	require.Equal(t, 1, errs.Count())
+	require.ErrorIs(t, errs[0], ErrInvalidTag)
+	require.Equal(t, uint(3), maxUID)
+
+	structNode, ok := node.(*StructNode)
+	require.True(t, ok)
+
+	require.Equal(
+		t,
+		&SliceNode{
+			Type:        reflect.TypeOf([]*sliceListener{}),
+			VisiblePath: "Listeners",
+			UID:         0,
+			Merge:       MergeIndex,
+			entriesInfo: entriesInfo{
+				leaves: []string{"Port"},
+			},
+		},
+		structNode.Index[0].Node,
+	)
+	require.Equal(
+		t,
+		MergeAppend,
+		structNode.Index[1].Node.(*SliceNode).Merge,
+	)
+	require.IsType(t, &ValueNode{}, structNode.Index[2].Node)
+}
+
+func TestSliceNode_Fill(t *testing.T) {
+	t.Parallel()
+
+	newNode := func(merge MergePolicy) *SliceNode {
+		return &SliceNode{
+			Type:        reflect.TypeOf([]*sliceListener{}),
+			VisiblePath: "Listeners",
+			UID:         4,
+			Merge:       merge,
+		}
+	}
+
+	entry := func(port int, location string) fvalue.Values {
+		return fvalue.Values{
+			0: {
+				Value:    reflect.ValueOf(ref.R(port)),
+				Location: location,
+			},
+		}
+	}
+
+	newLayers := func() []fvalue.Values {
+		return []fvalue.Values{
+			{
+				4: {
+					Entries: map[string]fvalue.Values{
+						"1": entry(11, "l0-1"),
+					},
+				},
+			},
+			{
+				4: {
+					Entries: map[string]fvalue.Values{
+						"0": entry(20, "l1-0"),
+						"1": entry(21, "l1-1"),
+					},
+				},
+			},
+		}
+	}
+
+	ports := func(listeners []*sliceListener) []int {
+		result := make([]int, 0, len(listeners))
+
+		for _, listener := range listeners {
+			result = append(result, *listener.Port)
+		}
+
+		return result
+	}
+
+	t.Run(
+		"index", func(t *testing.T) {
+			t.Parallel()
+
+			var listeners []*sliceListener
+
+			layers := newLayers()
+
+			pl, err := newNode(MergeIndex).Fill(
+				reflect.ValueOf(&listeners).Elem(),
+				layers,
+			)
+			require.NoError(t, err)
+			require.Equal(t, []int{20, 11}, ports(listeners))
+			require.Equal(t, "Listeners[1].Port", pl[1].Path)
+			require.Equal(t, "l0-1", pl[1].Location)
+			require.Equal(t, uint(4), pl[1].UID)
+			require.Empty(t, layers[0])
+			require.Len(t, layers[1][4].Entries, 1)
+			require.Contains(t, layers[1][4].Entries, "1")
+		},
+	)
+
+	t.Run(
+		"append", func(t *testing.T) {
+			t.Parallel()
+
+			var listeners []*sliceListener
+
+			layers := newLayers()
+
+			pl, err := newNode(MergeAppend).Fill(
+				reflect.ValueOf(&listeners).Elem(),
+				layers,
+			)
+			require.NoError(t, err)
+			require.Equal(t, []int{20, 21, 11}, ports(listeners))
+			require.Equal(t, "Listeners[2].Port", pl[2].Path)
+			require.Equal(t, "l0-1", pl[2].Location)
+			require.Empty(t, layers[0])
+			require.Empty(t, layers[1])
+		},
+	)
+
+	t.Run(
+		"replace", func(t *testing.T) {
+			t.Parallel()
+
+			var listeners []*sliceListener
+
+			layers := newLayers()
+
+			_, err := newNode(MergeReplace).Fill(
+				reflect.ValueOf(&listeners).Elem(),
+				layers,
+			)
+			require.ErrorContains(
+				t,
+				err,
+				"Listeners[0].Port-[*int]: uninitialized key",
+			)
+			require.Len(t, listeners, 2)
+			require.Empty(t, layers[0])
+			require.Len(t, layers[1][4].Entries, 2)
+		},
+	)
+
+	t.Run(
+		"uninitialized", func(t *testing.T) {
+			t.Parallel()
+
+			var listeners []*sliceListener
+
+			_, err := newNode(MergeIndex).Fill(
+				reflect.ValueOf(&listeners).Elem(),
+				[]fvalue.Values{{}},
+			)
+			require.ErrorIs(t, err, ErrUninitializedKey)
+		},
+	)
+}
+
+func TestSliceNode_FeedFieldValues(t *testing.T) {
+	t.Parallel()
+
+	n := &SliceNode{
+		Type:        reflect.TypeOf([]*sliceListener{}),
+		VisiblePath: "Listeners",
+		UID:         1,
+	}
+
+	fieldValues := make(fvalue.Values)
+
+	n.FeedFieldValues(
+		"defaults",
+		fieldValues,
+		reflect.ValueOf([]*sliceListener(nil)),
+	)
+	require.Empty(t, fieldValues)
+
+	listeners := []*sliceListener{{Port: ref.R(80)}, nil}
+
+	n.FeedFieldValues("defaults", fieldValues, reflect.ValueOf(listeners))
+	require.Len(t, fieldValues, 1)
+
+	fieldValue := fieldValues[1]
+	require.Equal(t, "struct[defaults]:Listeners", fieldValue.Location)
+	require.Len(t, fieldValue.Entries, 1)
+	require.Equal(
+		t,
+		"struct[defaults]:Listeners[0].Port",
+		fieldValue.Entries["0"][0].Location,
+	)
+}
+
+func TestSliceNode_BuildGetList(t *testing.T) {
+	t.Parallel()
+
+	n := &SliceNode{
+		Type:        reflect.TypeOf([]*sliceListener{}),
+		VisiblePath: "Listeners",
+		UID:         2,
+	}
+
+	var gl GetList
+
+	n.BuildGetList(&gl)
+	require.Len(t, gl, 1)
+
+	g := &entriesGetterStub{
+		values: map[string]string{
+			"Listeners[0].Port": "80",
+			"Listeners[x].Port": "81",
+		},
+		keys: []string{"0", "x"},
+	}
+
+	uid, fv, err := gl[0](g)
+	require.NoError(t, err)
+	require.Equal(t, uint(2), uid)
+	require.Len(t, fv.Entries, 1)
+	require.Equal(t, "stub:Listeners[0].Port", fv.Entries["0"][0].Location)
+	require.Equal(t, []string{"Listeners[0]"}, g.expanded)
+}
+
+func TestSliceNodeError_Is(t *testing.T) {
+	t.Parallel()
+
+	require.ErrorIs(t, SliceNodeError{}, ErrSliceNode)
+	require.ErrorIs(t, MapNodeError{}, ErrMapNode)
+}
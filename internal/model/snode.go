@@ -14,6 +14,9 @@ type StructNode struct {
 	Type        reflect.Type
 	VisiblePath string
 	Index       IndexedSubNodes
+	Optional    bool
+	Secret      bool
+	Deprecated  bool
 }
 
 type StructNodeError struct {
@@ -29,6 +32,11 @@ func (StructNodeError) Is(err error) bool {
 func (n StructNode) Fill(
 	value reflect.Value, layers []fvalue.Values,
 ) (plocation.Locations, error) {
+	// optional structs no layer provides a field of are left nil
+	if n.Optional && !provided(&n, layers) {
+		return nil, nil
+	}
+
 	var (
 		pl   plocation.Locations
 		errs StructNodeError
@@ -50,6 +58,12 @@ func (n StructNode) Fill(
 		pl.Append(pln)
 	}
 
+	if n.Secret {
+		for i := range pl {
+			pl[i].Secret = true
+		}
+	}
+
 	if errs.None() {
 		return pl, nil
 	}
@@ -74,6 +88,27 @@ func (n *StructNode) FeedFieldValues(
 	}
 }
 
+// provided returns true when a layer provides a value for a field of the
+// node sub-tree.
+func provided(node Node, layers []fvalue.Values) bool {
+	switch n := node.(type) {
+	case *StructNode:
+		for _, index := range n.Index {
+			if provided(index.Node, layers) {
+				return true
+			}
+		}
+	case *ValueNode:
+		return len(sourcesOf(n.UID, layers)) > 0
+	case *MapNode:
+		return len(sourcesOf(n.UID, layers)) > 0
+	case *SliceNode:
+		return len(sourcesOf(n.UID, layers)) > 0
+	}
+
+	return false
+}
+
 type IndexedSubNodes []*IndexedSubNode
 
 type IndexedSubNode struct {
@@ -120,3 +155,22 @@ func (n *StructNode) BuildExpandList(el *ExpandList) {
 		index.Node.BuildExpandList(el)
 	}
 }
+
+// Validate checks the fields first, then calls the Validate method of the
+// struct if any.
+func (n *StructNode) Validate(value reflect.Value) []Violation {
+	if value.IsNil() {
+		return nil
+	}
+
+	var violations []Violation
+
+	for _, index := range n.Index {
+		violations = append(
+			violations,
+			index.Node.Validate(value.Elem().FieldByIndex(index.Index))...,
+		)
+	}
+
+	return append(violations, validateStruct(n.VisiblePath, value)...)
+}
@@ -0,0 +1,188 @@
+package model
+
+import (
+	"reflect"
+	"strings"
+)
+
+const (
+	tagName     = "dsco"
+	tagSep      = ","
+	tagValueSep = "="
+	tagMerge    = "merge"
+	tagOptional = "optional"
+	tagSecret   = "secret"
+
+	tagDeprecated = "deprecated"
+
+	// descTagName is the tag holding the field description shown in the
+	// usage text.
+	descTagName = "desc"
+)
+
+// Key tags bind a field to a key of a layer kind instead of the key derived
+// from its path.
+const (
+	// EnvTag holds the environment variable name of the field.
+	EnvTag = "env"
+
+	// FlagTag holds the command line key of the field.
+	FlagTag = "flag"
+
+	// FileTag holds the dotted configuration file key of the field.
+	FileTag = "file"
+
+	// SecretTag holds the path#field reference of the field in a secret
+	// store.
+	SecretTag = "secret"
+)
+
+// keyTags are the key tag names.
+var keyTags = []string{EnvTag, FlagTag, FileTag, SecretTag} //nolint:gochecknoglobals // constant list
+
+// MergePolicy defines how values of a collection field provided by several
+// layers are combined.
+type MergePolicy string
+
+const (
+	// MergeKeys merges map entries key by key, every entry leaf being
+	// provided by the first layer defining it. It is the default policy for
+	// maps.
+	MergeKeys MergePolicy = "keys"
+
+	// MergeIndex merges slice elements index by index, every element leaf
+	// being provided by the first layer defining it. It is the default
+	// policy for slices of structs.
+	MergeIndex MergePolicy = "index"
+
+	// MergeAppend concatenates the slice elements of all layers, from the
+	// lowest to the highest precedence layer.
+	MergeAppend MergePolicy = "append"
+
+	// MergeReplace uses the whole collection of the first layer providing
+	// it.
+	MergeReplace MergePolicy = "replace"
+)
+
+// supports returns true when the policy can be applied on a field of type
+// _type.
+func (p MergePolicy) supports(_type reflect.Type) bool {
+	switch {
+	case _type.Kind() == reflect.Map:
+		return p == MergeKeys || p == MergeReplace
+	case IsIndexedSlice(_type):
+		return p == MergeIndex || p == MergeAppend || p == MergeReplace
+	default:
+		return false
+	}
+}
+
+// fieldTag holds the options of a dsco struct tag.
+type fieldTag struct {
+	merge      MergePolicy
+	rules      Rules
+	keys       map[string]string
+	desc       string
+	optional   bool
+	secret     bool
+	deprecated bool
+}
+
+// keysString returns the key tags in struct tag form.
+func (t fieldTag) keysString() string {
+	var tags []string
+
+	for _, name := range keyTags {
+		if key, found := t.keys[name]; found {
+			tags = append(tags, name+":"+key)
+		}
+	}
+
+	return strings.Join(tags, " ")
+}
+
+// parseTag parses the dsco tag of the field located at path.
+func parseTag(path string, field reflect.StructField) (fieldTag, error) {
+	result := fieldTag{
+		desc: strings.TrimSpace(field.Tag.Get(descTagName)),
+	}
+
+	for _, name := range keyTags {
+		key := strings.TrimSpace(field.Tag.Get(name))
+		if key == "" {
+			continue
+		}
+
+		if result.keys == nil {
+			result.keys = make(map[string]string, len(keyTags))
+		}
+
+		result.keys[name] = key
+	}
+
+	tag, found := field.Tag.Lookup(tagName)
+	if !found || tag == "" {
+		return result, nil
+	}
+
+	options := strings.Split(tag, tagSep)
+
+	for i, option := range options {
+		name, value, _ := strings.Cut(strings.TrimSpace(option), tagValueSep)
+
+		switch name {
+		case tagMerge:
+			policy := MergePolicy(value)
+
+			switch policy {
+			case MergeKeys, MergeIndex, MergeAppend, MergeReplace:
+			default:
+				return result, InvalidTagError{
+					Path:   path,
+					Tag:    tag,
+					Reason: "unknown merge policy " + value,
+				}
+			}
+
+			result.merge = policy
+		case tagOptional, tagSecret, tagDeprecated:
+			if value != "" {
+				return result, InvalidTagError{
+					Path:   path,
+					Tag:    tag,
+					Reason: "option " + name + " takes no value",
+				}
+			}
+
+			switch name {
+			case tagOptional:
+				result.optional = true
+			case tagSecret:
+				result.secret = true
+			default:
+				result.deprecated = true
+			}
+		case ruleMin, ruleMax, ruleOneOf:
+			result.rules = append(result.rules, Rule{Name: name, Value: value})
+		case ruleRegexp:
+			// a regular expression may contain the option separator, so it
+			// takes the rest of the tag
+			_, value, _ = strings.Cut(
+				strings.Join(options[i:], tagSep),
+				tagValueSep,
+			)
+
+			result.rules = append(result.rules, Rule{Name: name, Value: value})
+
+			return result, nil
+		default:
+			return result, InvalidTagError{
+				Path:   path,
+				Tag:    tag,
+				Reason: "unknown option " + name,
+			}
+		}
+	}
+
+	return result, nil
+}
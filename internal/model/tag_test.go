@@ -0,0 +1,182 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseTag(t *testing.T) {
+	t.Parallel()
+
+	type Root struct {
+		NoTag   map[string]string
+		Empty   map[string]string `dsco:""`
+		Keys    map[string]string `dsco:"merge=keys"`
+		Replace map[string]string `dsco:" merge=replace "`
+		Index   []*struct{}       `dsco:"merge=index"`
+		Append  []*struct{}       `dsco:"merge=append"`
+		Policy  map[string]string `dsco:"merge=x"`
+		Unknown map[string]string `dsco:"foo=bar"`
+		Rules   *string           `dsco:"min=1, max=8,oneof=a b"`
+		Regexp  *string           `dsco:"max=8,regexp=^[a-z]{1,3}$"`
+		Opt     *string           `dsco:"optional,min=1"`
+		OptVal  *string           `dsco:"optional=yes"`
+		Secret  *string           `dsco:"secret,optional"`
+		SecVal  *string           `dsco:"secret=yes"`
+		Depr    *string           `dsco:"deprecated,optional"`
+		Desc    *string           `desc:"a field"`
+		DescOpt *string           `dsco:"optional" desc:" an optional field "`
+		Keys2   *string           `env:"DATABASE_URL" flag:" db " file:"db.url"`
+	}
+
+	rootType := reflect.TypeOf(Root{})
+
+	field := func(name string) reflect.StructField {
+		f, found := rootType.FieldByName(name)
+		require.True(t, found)
+
+		return f
+	}
+
+	for _, tt := range []struct {
+		name string
+		want fieldTag
+	}{
+		{name: "NoTag"},
+		{name: "Empty"},
+		{name: "Keys", want: fieldTag{merge: MergeKeys}},
+		{name: "Replace", want: fieldTag{merge: MergeReplace}},
+		{name: "Index", want: fieldTag{merge: MergeIndex}},
+		{name: "Append", want: fieldTag{merge: MergeAppend}},
+		{
+			name: "Rules",
+			want: fieldTag{
+				rules: Rules{
+					{Name: "min", Value: "1"},
+					{Name: "max", Value: "8"},
+					{Name: "oneof", Value: "a b"},
+				},
+			},
+		},
+		{
+			name: "Regexp",
+			want: fieldTag{
+				rules: Rules{
+					{Name: "max", Value: "8"},
+					{Name: "regexp", Value: "^[a-z]{1,3}$"},
+				},
+			},
+		},
+		{
+			name: "Opt",
+			want: fieldTag{
+				optional: true,
+				rules:    Rules{{Name: "min", Value: "1"}},
+			},
+		},
+		{
+			name: "Secret",
+			want: fieldTag{optional: true, secret: true},
+		},
+		{
+			name: "Depr",
+			want: fieldTag{optional: true, deprecated: true},
+		},
+		{name: "Desc", want: fieldTag{desc: "a field"}},
+		{
+			name: "DescOpt",
+			want: fieldTag{desc: "an optional field", optional: true},
+		},
+		{
+			name: "Keys2",
+			want: fieldTag{
+				keys: map[string]string{
+					EnvTag:  "DATABASE_URL",
+					FlagTag: "db",
+					FileTag: "db.url",
+				},
+			},
+		},
+	} {
+		tag, err := parseTag("X."+tt.name, field(tt.name))
+		require.NoError(t, err)
+		require.Equal(t, tt.want, tag)
+	}
+
+	_, err := parseTag("X.Policy", field("Policy"))
+	require.Equal(
+		t,
+		InvalidTagError{
+			Path:   "X.Policy",
+			Tag:    "merge=x",
+			Reason: "unknown merge policy x",
+		},
+		err,
+	)
+
+	_, err = parseTag("X.OptVal", field("OptVal"))
+	require.Equal(
+		t,
+		InvalidTagError{
+			Path:   "X.OptVal",
+			Tag:    "optional=yes",
+			Reason: "option optional takes no value",
+		},
+		err,
+	)
+
+	_, err = parseTag("X.SecVal", field("SecVal"))
+	require.Equal(
+		t,
+		InvalidTagError{
+			Path:   "X.SecVal",
+			Tag:    "secret=yes",
+			Reason: "option secret takes no value",
+		},
+		err,
+	)
+
+	_, err = parseTag("X.Unknown", field("Unknown"))
+	require.Equal(
+		t,
+		InvalidTagError{
+			Path:   "X.Unknown",
+			Tag:    "foo=bar",
+			Reason: "unknown option foo",
+		},
+		err,
+	)
+}
+
+func TestMergePolicy_supports(t *testing.T) {
+	t.Parallel()
+
+	mapType := reflect.TypeOf(map[string]string{})
+	sliceType := reflect.TypeOf([]*struct{}{})
+	valueSliceType := reflect.TypeOf([]string{})
+
+	for _, tt := range []struct {
+		policy MergePolicy
+		_type  reflect.Type
+		want   bool
+	}{
+		{MergeKeys, mapType, true},
+		{MergeReplace, mapType, true},
+		{MergeIndex, mapType, false},
+		{MergeAppend, mapType, false},
+		{MergeKeys, sliceType, false},
+		{MergeIndex, sliceType, true},
+		{MergeAppend, sliceType, true},
+		{MergeReplace, sliceType, true},
+		{MergeReplace, valueSliceType, false},
+	} {
+		require.Equal(
+			t,
+			tt.want,
+			tt.policy.supports(tt._type),
+			"%s on %s", tt.policy, tt._type,
+		)
+	}
+}
@@ -7,13 +7,18 @@ import (
 	"github.com/byte4ever/dsco/internal"
 	"github.com/byte4ever/dsco/internal/fvalue"
 	"github.com/byte4ever/dsco/internal/plocation"
-	"github.com/byte4ever/dsco/registry"
 )
 
 type ValueNode struct {
 	Type        reflect.Type
 	VisiblePath string
 	UID         uint
+	Rules       Rules
+	Optional    bool
+	Secret      bool
+	Deprecated  bool
+	Description string
+	Keys        map[string]string
 }
 
 func (n *ValueNode) Fill(
@@ -34,16 +39,21 @@ func (n *ValueNode) Fill(
 				fieldValue.Location,
 			)
 
+			pl[0].Secret = n.Secret || fieldValue.Secret
+			pl[0].References = fieldValue.References
+
 			return pl, nil
 		}
 	}
 
-	return nil, fmt.Errorf(
-		"%s-[%s]: %w",
-		n.VisiblePath,
-		registry.LongTypeName(value.Type()),
-		ErrUninitializedKey,
-	)
+	if n.Optional {
+		return nil, nil
+	}
+
+	return nil, UninitializedKeyError{
+		Type: value.Type(),
+		Path: n.VisiblePath,
+	}
 }
 
 func (n *ValueNode) FeedFieldValues(
@@ -51,7 +61,7 @@ func (n *ValueNode) FeedFieldValues(
 	fieldValues fvalue.Values,
 	value reflect.Value,
 ) {
-	if value.IsNil() {
+	if isNil(value) {
 		return
 	}
 
@@ -70,6 +80,9 @@ func (n *ValueNode) BuildGetList(s *GetList) {
 	s.Push(
 		func(g internal.ValueGetter) (uint, *fvalue.Value, error) {
 			fieldValue, err := g.Get(n.VisiblePath, n.Type)
+			if fieldValue != nil {
+				fieldValue.Path = n.VisiblePath
+			}
 
 			return n.UID, fieldValue, err //nolint:wrapcheck // don't wan to wrap
 		},
@@ -77,3 +90,18 @@ func (n *ValueNode) BuildGetList(s *GetList) {
 }
 
 func (*ValueNode) BuildExpandList(*ExpandList) {}
+
+func (n *ValueNode) Validate(value reflect.Value) []Violation {
+	return checkRules(n.VisiblePath, n.Rules, value)
+}
+
+// isNil reports whether value is nil. Plain values (i.e. map[string]string
+// elements) are never nil.
+func isNil(value reflect.Value) bool {
+	switch value.Kind() { //nolint:exhaustive // only nillable kinds matter
+	case reflect.Pointer, reflect.Slice, reflect.Map, reflect.Interface:
+		return value.IsNil()
+	default:
+		return false
+	}
+}
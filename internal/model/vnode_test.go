@@ -250,6 +250,7 @@ func TestValueNode_BuildGetList(t *testing.T) {
 				fvalue.Value{
 					Value:    someValue,
 					Location: location,
+					Path:     path,
 				},
 				*fv,
 			)
@@ -20,18 +20,28 @@ Location represents a single configuration value's source information:
 		Path     string  // Configuration path (e.g., "database.host")
 		Location string  // Source location (e.g., "env[MYAPP-DATABASE-HOST]")
 		UID      uint    // Unique identifier for the field
+		Secret   bool    // Value must be redacted (dsco:"secret" fields, secret layers)
+		References []string // References expanded by interpolation
 	}
 
 ### Fields
 
-- **Path**: The configuration path identifying the field within the struct
-  hierarchy. Uses dot notation for nested fields (e.g., "server.timeout").
+  - **Path**: The configuration path identifying the field within the struct
+    hierarchy. Uses dot notation for nested fields (e.g., "server.timeout").
 
-- **Location**: A human-readable string indicating where the value came from.
-  Format varies by source type (cmdline, env, struct, file).
+  - **Location**: A human-readable string indicating where the value came from.
+    Format varies by source type (cmdline, env, struct, file).
 
-- **UID**: A unique identifier assigned during model building that corresponds
-  to a specific field in the configuration structure.
+  - **UID**: A unique identifier assigned during model building that corresponds
+    to a specific field in the configuration structure.
+
+  - **Secret**: True when the field is tagged dsco:"secret" or its value
+    comes from a secret layer. Locations never hold values; Dump flags
+    secret rows with "(secret)".
+
+  - **References**: The ${env:..}, ${file:..} and ${ref:..} references
+    expanded to build the value when its layer interpolates. Dump lists them
+    after "via".
 
 ## Locations
 
@@ -3,9 +3,14 @@ package plocation
 import (
 	"fmt"
 	"io"
+	"strings"
 	"text/tabwriter"
 )
 
+// secretMark flags the locations of secret values in dumps, which never
+// hold values.
+const secretMark = "(secret)"
+
 // Locations contains all fillHelper Location for every key path.
 type Locations []Location
 
@@ -14,6 +19,13 @@ type Location struct {
 	Path     string
 	Location string
 	UID      uint
+
+	// Secret is true when the value of the field must be redacted.
+	Secret bool
+
+	// References lists the references expanded by interpolation to build
+	// the value, Location being the location of the raw value.
+	References []string
 }
 
 // Dump writes filling report in writer.
@@ -31,8 +43,17 @@ func (f *Locations) Dump(writer io.Writer) {
 
 	//nolint:gocritic // don't care it is error processing
 	for _, entry := range *f {
+		location := entry.Location
+		if len(entry.References) > 0 {
+			location += " via " + strings.Join(entry.References, ", ")
+		}
+
+		if entry.Secret {
+			location += " " + secretMark
+		}
+
 		_, _ = fmt.Fprintf(
-			tabWriter, "  %s\t  %s\n", entry.Path, entry.Location,
+			tabWriter, "  %s\t  %s\n", entry.Path, location,
 		)
 	}
 
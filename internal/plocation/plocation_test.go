@@ -24,6 +24,17 @@ func TestPathLocations_Dump(t *testing.T) {
 					UID:      1,
 					Path:     "path1",
 					Location: "loc1",
+					References: []string{
+						"env:HOME",
+						"ref:a from loc0",
+					},
+				},
+				{
+					UID:        2,
+					Path:       "path2",
+					Location:   "loc2",
+					Secret:     true,
+					References: []string{"file:/secret"},
 				},
 			}
 
@@ -34,7 +45,8 @@ func TestPathLocations_Dump(t *testing.T) {
 			expectedString := `  path   |  Location
   ----   |  --------
   path0  |  loc0
-  path1  |  loc1
+  path1  |  loc1 via env:HOME, ref:a from loc0
+  path2  |  loc2 via file:/secret (secret)
 `
 
 			require.Equal(
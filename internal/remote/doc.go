@@ -0,0 +1,69 @@
+/*
+Package remote provides HTTP configuration document value extraction for
+dsco's configuration system.
+
+# Overview
+
+The remote package implements a fetcher that gets a JSON, YAML or TOML
+configuration document over HTTP and flattens it into the dash separated
+keys used by every string based dsco layer, exactly like the file package
+does for local configuration files.
+
+# Formats
+
+The format is given by the response content type:
+
+	application/json, application/*+json        → JSON
+	application/yaml, text/yaml, text/x-yaml    → YAML
+	application/toml                            → TOML
+
+Any other content type falls back on the URL extension, then on JSON.
+
+# Locations
+
+Every value location holds the URL, credentials redacted, and the JSON
+pointer of the key in the document:
+
+	http[https://config.local/myapp.json]:/database/host
+
+# Fetching
+
+A Fetcher keeps the last fetched document. When the server sent an ETag,
+the next fetch is a conditional request (If-None-Match) and a 304 answer
+reuses the document. Options tune the fetch:
+
+  - WithClient sends requests with a custom http.Client
+  - WithTimeout bounds each attempt (DefaultTimeout by default)
+  - WithRetries retries network errors, 429 and 5xx with a backoff
+  - WithBearerToken and WithBasicAuth authenticate requests
+  - WithCacheFile stores the last known good document on disk
+
+With a cache file, a failed fetch provides the values of the cached
+document instead, and EntriesProvider.Stale, like Fetcher.Stale until the
+next fetch, returns the fetch error.
+
+# Usage Examples
+
+	fetcher := remote.NewFetcher(
+		"https://config.local/myapp.json",
+		remote.WithRetries(3, time.Second),
+		remote.WithCacheFile("/var/cache/myapp/config.json"),
+	)
+
+	provider, err := fetcher.Fetch(ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	values := provider.GetStringValues()
+
+# Error Handling
+
+The fetcher reports:
+
+  - ErrInvalidURL when the URL is not an absolute http or https URL
+  - StatusError (ErrUnexpectedStatus) when the server answers another
+    status than 200 or 304
+  - the file package errors when the document is invalid
+*/
+package remote
@@ -0,0 +1,33 @@
+package remote
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidURL represents an error when the configuration URL is not an
+// absolute http or https URL.
+var ErrInvalidURL = errors.New("invalid configuration url")
+
+// ErrUnexpectedStatus represents an error when the server answers with a
+// status that does not provide the configuration document.
+var ErrUnexpectedStatus = errors.New("unexpected http status")
+
+// ErrUnsupportedContentType represents an error when the content type of
+// the response matches no supported document format.
+var ErrUnsupportedContentType = errors.New("unsupported content type")
+
+// StatusError represents an error when the server answers URL with the
+// unexpected Status code.
+type StatusError struct {
+	URL    string
+	Status int
+}
+
+func (e StatusError) Error() string {
+	return fmt.Sprintf("%s: status %d", e.URL, e.Status)
+}
+
+func (StatusError) Is(err error) bool {
+	return errors.Is(err, ErrUnexpectedStatus)
+}
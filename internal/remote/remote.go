@@ -0,0 +1,433 @@
+package remote
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/byte4ever/dsco/internal/file"
+	"github.com/byte4ever/dsco/svalue"
+)
+
+const (
+	locationFmt = "http[%s]:%s"
+
+	// DefaultTimeout is the default timeout of a fetch attempt.
+	DefaultTimeout = 30 * time.Second
+
+	accept = "application/json, application/yaml;q=0.9, application/toml;q=0.8"
+)
+
+// EntriesProvider is an entries' provider holding the values of a
+// configuration document fetched over HTTP.
+type EntriesProvider struct {
+	values svalue.Values
+	stale  error
+	name   string
+	url    string
+}
+
+// GetName returns the provider name.
+func (e *EntriesProvider) GetName() string {
+	return e.name
+}
+
+// GetURL returns the URL of the configuration document, credentials
+// redacted.
+func (e *EntriesProvider) GetURL() string {
+	return e.url
+}
+
+// GetStringValues implements svalue.Provider interface.
+func (e *EntriesProvider) GetStringValues() svalue.Values {
+	return e.values
+}
+
+// Stale returns the fetch error when the values come from the last known
+// good document of the cache file, nil otherwise.
+func (e *EntriesProvider) Stale() error {
+	return e.stale
+}
+
+type options struct {
+	client    *http.Client
+	auth      func(req *http.Request)
+	cacheFile string
+	timeout   time.Duration
+	backoff   time.Duration
+	retries   int
+}
+
+// Option is an HTTP entries' provider option.
+type Option func(opt *options)
+
+// WithClient makes the fetcher send its requests with client instead of
+// http.DefaultClient.
+func WithClient(client *http.Client) Option {
+	return func(opt *options) {
+		opt.client = client
+	}
+}
+
+// WithTimeout sets the timeout of each fetch attempt, DefaultTimeout by
+// default. A zero timeout disables it.
+func WithTimeout(timeout time.Duration) Option {
+	return func(opt *options) {
+		opt.timeout = timeout
+	}
+}
+
+// WithRetries retries a failed fetch up to retries times, the first retry
+// waiting backoff and every next one twice as long. Network errors, 429 and
+// 5xx statuses are retried, other statuses are not.
+func WithRetries(retries int, backoff time.Duration) Option {
+	return func(opt *options) {
+		opt.retries = retries
+		opt.backoff = backoff
+	}
+}
+
+// WithBearerToken authenticates requests with the bearer token.
+func WithBearerToken(token string) Option {
+	return func(opt *options) {
+		opt.auth = func(req *http.Request) {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+	}
+}
+
+// WithBasicAuth authenticates requests with the basic authentication
+// credentials.
+func WithBasicAuth(username, password string) Option {
+	return func(opt *options) {
+		opt.auth = func(req *http.Request) {
+			req.SetBasicAuth(username, password)
+		}
+	}
+}
+
+// WithCacheFile stores every fetched document in the file at path. When a
+// fetch fails, the values of this last known good document are provided
+// instead, and its ETag is sent in the first request.
+func WithCacheFile(path string) Option {
+	return func(opt *options) {
+		opt.cacheFile = path
+	}
+}
+
+// document is a fetched configuration document.
+type document struct {
+	// URL is the redacted URL, so the cache file holds no credentials.
+	URL     string      `json:"url"`
+	ETag    string      `json:"etag,omitempty"`
+	Format  file.Format `json:"format"`
+	Content []byte      `json:"content"`
+}
+
+// Fetcher fetches a configuration document over HTTP. It keeps the last
+// fetched document, so later fetches are conditional requests
+// (If-None-Match) when the server sent an ETag.
+type Fetcher struct {
+	last  *document
+	stale error
+	opt   options
+	url   string
+	mu    sync.Mutex
+}
+
+// NewFetcher creates a fetcher of the configuration document located at
+// rawURL.
+func NewFetcher(rawURL string, opts ...Option) *Fetcher {
+	opt := options{
+		client:  http.DefaultClient,
+		timeout: DefaultTimeout,
+	}
+
+	for _, o := range opts {
+		o(&opt)
+	}
+
+	return &Fetcher{
+		url: rawURL,
+		opt: opt,
+	}
+}
+
+// Fetch fetches the configuration document and returns an entries'
+// provider of its values, flattened like configuration file ones. The
+// format is given by the response content type, or by the URL extension
+// when the type is not specific, JSON being the default.
+func (f *Fetcher) Fetch(ctx context.Context) (*EntriesProvider, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	u, err := url.Parse(f.url)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") ||
+		u.Host == "" {
+		return nil, fmt.Errorf("%q: %w", f.url, ErrInvalidURL)
+	}
+
+	redacted := u.Redacted()
+
+	f.stale = nil
+
+	if f.last == nil && f.opt.cacheFile != "" {
+		f.last = f.loadCache()
+	}
+
+	doc, err := f.download(ctx, redacted)
+	if err == nil {
+		var values svalue.Values
+
+		values, err = parseDocument(redacted, doc)
+		if err == nil {
+			if doc != f.last {
+				f.last = doc
+
+				if err := f.saveCache(doc); err != nil {
+					return nil, err
+				}
+			}
+
+			return newEntriesProvider(redacted, values, nil), nil
+		}
+	}
+
+	if f.opt.cacheFile == "" || f.last == nil {
+		return nil, err
+	}
+
+	values, lerr := parseDocument(redacted, f.last)
+	if lerr != nil {
+		return nil, err
+	}
+
+	f.stale = err
+
+	return newEntriesProvider(redacted, values, err), nil
+}
+
+// RedactedURL returns the URL of the configuration document, credentials
+// redacted, empty when it is not a valid URL.
+func (f *Fetcher) RedactedURL() string {
+	u, err := url.Parse(f.url)
+	if err != nil {
+		return ""
+	}
+
+	return u.Redacted()
+}
+
+// Stale returns the fetch error when the values of the last Fetch come from
+// the last known good document of the cache file, nil otherwise.
+func (f *Fetcher) Stale() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.stale
+}
+
+func newEntriesProvider(
+	redacted string,
+	values svalue.Values,
+	stale error,
+) *EntriesProvider {
+	return &EntriesProvider{
+		values: values,
+		stale:  stale,
+		name:   fmt.Sprintf("http(%s)", redacted),
+		url:    redacted,
+	}
+}
+
+func parseDocument(redacted string, doc *document) (svalue.Values, error) {
+	values, err := file.ParseValues(
+		doc.Format,
+		doc.Content,
+		func(pointer string, _, _ int) string {
+			return fmt.Sprintf(locationFmt, redacted, pointer)
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", redacted, err)
+	}
+
+	return values, nil
+}
+
+// download gets the document, retrying failed attempts with an exponential
+// backoff.
+func (f *Fetcher) download(
+	ctx context.Context,
+	redacted string,
+) (*document, error) {
+	for attempt := 0; ; attempt++ {
+		doc, retry, err := f.get(ctx, redacted)
+		if err == nil {
+			return doc, nil
+		}
+
+		if !retry || attempt >= f.opt.retries {
+			return nil, err
+		}
+
+		timer := time.NewTimer(f.opt.backoff << attempt)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+
+			return nil, fmt.Errorf("%s: %w", redacted, ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
+
+// get runs a single fetch attempt and tells whether a failure is worth a
+// retry. The last document is returned when the server answers it is not
+// modified.
+func (f *Fetcher) get(
+	ctx context.Context,
+	redacted string,
+) (*document, bool, error) {
+	if f.opt.timeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, f.opt.timeout)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, f.url, nil)
+	if err != nil {
+		return nil, false, fmt.Errorf("%s: %w", redacted, err)
+	}
+
+	req.Header.Set("Accept", accept)
+
+	if f.last != nil && f.last.ETag != "" {
+		req.Header.Set("If-None-Match", f.last.ETag)
+	}
+
+	if f.opt.auth != nil {
+		f.opt.auth(req)
+	}
+
+	resp, err := f.opt.client.Do(req)
+	if err != nil {
+		return nil, true, err //nolint:wrapcheck // url.Error holds the url
+	}
+
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && f.last != nil:
+		return f.last, false, nil
+	case resp.StatusCode != http.StatusOK:
+		return nil,
+			resp.StatusCode == http.StatusTooManyRequests ||
+				resp.StatusCode >= http.StatusInternalServerError,
+			StatusError{URL: redacted, Status: resp.StatusCode}
+	}
+
+	content, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, true, fmt.Errorf("%s: %w", redacted, err)
+	}
+
+	return &document{
+		URL:     redacted,
+		ETag:    resp.Header.Get("ETag"),
+		Format:  formatOf(resp),
+		Content: content,
+	}, false, nil
+}
+
+// formatOf returns the document format given by the response content type,
+// the URL extension or JSON by default.
+func formatOf(resp *http.Response) file.Format {
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+
+	switch {
+	case mediaType == "application/json" ||
+		strings.HasSuffix(mediaType, "+json"):
+		return file.FormatJSON
+	case strings.HasSuffix(mediaType, "/yaml") ||
+		strings.HasSuffix(mediaType, "/x-yaml") ||
+		strings.HasSuffix(mediaType, "+yaml"):
+		return file.FormatYAML
+	case strings.HasSuffix(mediaType, "/toml"):
+		return file.FormatTOML
+	}
+
+	if format, err := file.FormatFromPath(resp.Request.URL.Path); err == nil {
+		return format
+	}
+
+	return file.FormatJSON
+}
+
+// loadCache returns the document of the cache file, or nil when it is
+// missing, unreadable or stores another URL. URLs are compared redacted.
+func (f *Fetcher) loadCache() *document {
+	content, err := os.ReadFile(f.opt.cacheFile)
+	if err != nil {
+		return nil
+	}
+
+	var doc document
+
+	if err := json.Unmarshal(content, &doc); err != nil ||
+		doc.URL != f.RedactedURL() {
+		return nil
+	}
+
+	return &doc
+}
+
+// saveCache atomically replaces the cache file with the document.
+func (f *Fetcher) saveCache(doc *document) error {
+	if f.opt.cacheFile == "" {
+		return nil
+	}
+
+	content, err := json.Marshal(doc)
+	if err != nil {
+		return fmt.Errorf("cache file: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(
+		filepath.Dir(f.opt.cacheFile),
+		filepath.Base(f.opt.cacheFile)+".*",
+	)
+	if err != nil {
+		return fmt.Errorf("cache file: %w", err)
+	}
+
+	_, err = tmp.Write(content)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+
+	if err == nil {
+		err = os.Rename(tmp.Name(), f.opt.cacheFile)
+	}
+
+	if err != nil {
+		return errors.Join(
+			fmt.Errorf("cache file: %w", err),
+			os.Remove(tmp.Name()),
+		)
+	}
+
+	return nil
+}
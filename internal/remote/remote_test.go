@@ -0,0 +1,313 @@
+package remote
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/byte4ever/dsco/svalue"
+)
+
+const jsonDocument = `{"database": {"host": "db.local", "maxConns": 10}}`
+
+func TestFetcher_Fetch(t *testing.T) {
+	t.Parallel()
+
+	var requests, notModified atomic.Int32
+
+	server := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				requests.Add(1)
+
+				if r.Header.Get("If-None-Match") == `"v1"` {
+					notModified.Add(1)
+					w.WriteHeader(http.StatusNotModified)
+
+					return
+				}
+
+				w.Header().Set("Content-Type", "application/json")
+				w.Header().Set("ETag", `"v1"`)
+				_, _ = w.Write([]byte(jsonDocument))
+			},
+		),
+	)
+	defer server.Close()
+
+	fetcher := NewFetcher(server.URL + "/myapp")
+
+	expected := svalue.Values{
+		"database-host": {
+			Location: "http[" + server.URL + "/myapp]:/database/host",
+			Value:    "db.local",
+		},
+		"database-max_conns": {
+			Location: "http[" + server.URL + "/myapp]:/database/maxConns",
+			Value:    "10",
+		},
+	}
+
+	for i := 0; i < 2; i++ {
+		provider, err := fetcher.Fetch(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, expected, provider.GetStringValues())
+		require.Equal(t, "http("+server.URL+"/myapp)", provider.GetName())
+		require.NoError(t, provider.Stale())
+	}
+
+	require.Equal(t, int32(2), requests.Load())
+	require.Equal(t, int32(1), notModified.Load())
+}
+
+func TestFetcher_Fetch_formats(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/typed":
+					w.Header().Set("Content-Type", "application/yaml")
+				case "/config.toml":
+					w.Header().Set("Content-Type", "text/plain")
+					_, _ = w.Write([]byte("[database]\nhost = \"db.local\"\n"))
+
+					return
+				}
+
+				_, _ = w.Write([]byte("database:\n  host: db.local\n"))
+			},
+		),
+	)
+	defer server.Close()
+
+	for _, path := range []string{"/typed", "/config.toml", "/config.yaml"} {
+		provider, err := NewFetcher(server.URL + path).
+			Fetch(context.Background())
+		require.NoError(t, err, path)
+		require.Equal(
+			t,
+			"db.local",
+			provider.GetStringValues()["database-host"].Value,
+			path,
+		)
+	}
+}
+
+func TestFetcher_Fetch_auth(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				user, password, ok := r.BasicAuth()
+				if r.Header.Get("Authorization") != "Bearer token" &&
+					(!ok || user != "user" || password != "secret") {
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+
+				_, _ = w.Write([]byte(jsonDocument))
+			},
+		),
+	)
+	defer server.Close()
+
+	_, err := NewFetcher(server.URL).Fetch(context.Background())
+	require.ErrorIs(t, err, ErrUnexpectedStatus)
+	require.ErrorContains(t, err, "status 401")
+
+	_, err = NewFetcher(server.URL, WithBearerToken("token")).
+		Fetch(context.Background())
+	require.NoError(t, err)
+
+	_, err = NewFetcher(server.URL, WithBasicAuth("user", "secret")).
+		Fetch(context.Background())
+	require.NoError(t, err)
+}
+
+func TestFetcher_Fetch_retries(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+
+	server := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case r.URL.Path == "/missing":
+					requests.Add(1)
+					w.WriteHeader(http.StatusNotFound)
+				case requests.Add(1) < 3:
+					w.WriteHeader(http.StatusServiceUnavailable)
+				default:
+					_, _ = w.Write([]byte(jsonDocument))
+				}
+			},
+		),
+	)
+	defer server.Close()
+
+	_, err := NewFetcher(server.URL).Fetch(context.Background())
+	require.ErrorIs(t, err, ErrUnexpectedStatus)
+
+	requests.Store(0)
+
+	_, err = NewFetcher(server.URL, WithRetries(2, time.Millisecond)).
+		Fetch(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, int32(3), requests.Load())
+
+	requests.Store(0)
+
+	// client errors are not retried
+	_, err = NewFetcher(server.URL+"/missing", WithRetries(2, time.Millisecond)).
+		Fetch(context.Background())
+	require.Equal(t, StatusError{URL: server.URL + "/missing", Status: 404}, err)
+	require.Equal(t, int32(1), requests.Load())
+}
+
+func TestFetcher_Fetch_timeout(t *testing.T) {
+	t.Parallel()
+
+	release := make(chan struct{})
+
+	server := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				select {
+				case <-release:
+				case <-r.Context().Done():
+				}
+			},
+		),
+	)
+	defer server.Close()
+	defer close(release)
+
+	_, err := NewFetcher(server.URL, WithTimeout(10*time.Millisecond)).
+		Fetch(context.Background())
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestFetcher_Fetch_cacheFile(t *testing.T) {
+	t.Parallel()
+
+	var down atomic.Bool
+
+	server := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				if down.Load() {
+					w.WriteHeader(http.StatusBadGateway)
+					return
+				}
+
+				if r.Header.Get("If-None-Match") == `"v1"` {
+					w.WriteHeader(http.StatusNotModified)
+					return
+				}
+
+				w.Header().Set("ETag", `"v1"`)
+				_, _ = w.Write([]byte(jsonDocument))
+			},
+		),
+	)
+	defer server.Close()
+
+	cacheFile := filepath.Join(t.TempDir(), "cache.json")
+
+	provider, err := NewFetcher(server.URL, WithCacheFile(cacheFile)).
+		Fetch(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, provider.Stale())
+
+	// a new fetcher revalidates the cached document
+	provider, err = NewFetcher(server.URL, WithCacheFile(cacheFile)).
+		Fetch(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, provider.Stale())
+	require.Equal(t, "db.local", provider.GetStringValues()["database-host"].Value)
+
+	down.Store(true)
+
+	fetcher := NewFetcher(server.URL, WithCacheFile(cacheFile))
+
+	provider, err = fetcher.Fetch(context.Background())
+	require.NoError(t, err)
+	require.ErrorIs(t, provider.Stale(), ErrUnexpectedStatus)
+	require.ErrorIs(t, fetcher.Stale(), ErrUnexpectedStatus)
+	require.Equal(t, "db.local", provider.GetStringValues()["database-host"].Value)
+
+	down.Store(false)
+
+	_, err = fetcher.Fetch(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, fetcher.Stale())
+
+	down.Store(true)
+
+	// the cache of another url is not used
+	_, err = NewFetcher(server.URL+"/other", WithCacheFile(cacheFile)).
+		Fetch(context.Background())
+	require.ErrorIs(t, err, ErrUnexpectedStatus)
+}
+
+func TestFetcher_Fetch_invalidURL(t *testing.T) {
+	t.Parallel()
+
+	for _, rawURL := range []string{"", "config.json", "ftp://host/a", "http://"} {
+		_, err := NewFetcher(rawURL).Fetch(context.Background())
+		require.ErrorIs(t, err, ErrInvalidURL, rawURL)
+	}
+}
+
+func TestFetcher_Fetch_redacted(t *testing.T) {
+	t.Parallel()
+
+	var down atomic.Bool
+
+	server := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, _ *http.Request) {
+				if down.Load() {
+					w.WriteHeader(http.StatusServiceUnavailable)
+					return
+				}
+
+				_, _ = w.Write([]byte(`{"host": "h"}`))
+			},
+		),
+	)
+	defer server.Close()
+
+	rawURL := "http://user:secret@" + server.Listener.Addr().String()
+	cacheFile := filepath.Join(t.TempDir(), "cache.json")
+
+	provider, err := NewFetcher(rawURL, WithCacheFile(cacheFile)).
+		Fetch(context.Background())
+	require.NoError(t, err)
+	require.NotContains(t, provider.GetStringValues()["host"].Location, "secret")
+	require.NotContains(t, provider.GetName(), "secret")
+
+	content, err := os.ReadFile(cacheFile)
+	require.NoError(t, err)
+	require.NotContains(t, string(content), "secret")
+
+	down.Store(true)
+
+	// the cache is found back by the redacted url
+	provider, err = NewFetcher(rawURL, WithCacheFile(cacheFile)).
+		Fetch(context.Background())
+	require.NoError(t, err)
+	require.ErrorIs(t, provider.Stale(), ErrUnexpectedStatus)
+	require.Equal(t, "h", provider.GetStringValues()["host"].Value)
+}
@@ -0,0 +1,430 @@
+package dsco
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/byte4ever/dsco/internal/fvalue"
+	"github.com/byte4ever/dsco/svalue"
+)
+
+const (
+	refOpen   = "${"
+	refClose  = '}'
+	refEscape = "$${"
+
+	schemeEnv  = "env"
+	schemeFile = "file"
+	schemeRef  = "ref"
+)
+
+var (
+	// ErrInterpolation represents an error indicating that the references
+	// of a value cannot be expanded.
+	ErrInterpolation = errors.New("interpolation error")
+
+	// ErrInvalidReference represents an error where a reference is
+	// malformed or uses an unknown scheme.
+	ErrInvalidReference = errors.New("invalid reference")
+
+	// ErrUnresolvedReference represents an error where a referenced
+	// variable, file or field does not exist.
+	ErrUnresolvedReference = errors.New("unresolved reference")
+
+	// ErrReferenceCycle represents an error where fields reference each
+	// other.
+	ErrReferenceCycle = errors.New("reference cycle")
+)
+
+// InterpolationError represents an error when expanding Reference in the
+// value located at Location.
+type InterpolationError struct {
+	Err       error
+	Location  string
+	Reference string
+}
+
+func (a InterpolationError) Error() string {
+	return fmt.Sprintf(
+		"interpolation error in %s on %s: %v",
+		a.Location,
+		a.Reference,
+		a.Err,
+	)
+}
+
+func (InterpolationError) Is(err error) bool {
+	return errors.Is(err, ErrInterpolation)
+}
+
+func (a InterpolationError) Unwrap() error {
+	return a.Err
+}
+
+type interpolationOption struct{}
+
+func (interpolationOption) apply(opts *internalOpts) error {
+	opts.interpolate = true
+	return nil
+}
+
+// WithInterpolation returns an option expanding the references of the layer
+// values before they are parsed:
+//
+//   - ${env:NAME} is the NAME environment variable, see
+//     WithInterpolationEnviron,
+//   - ${file:/path} is the content of a file, without trailing newlines,
+//   - ${ref:database.host} is the value of another field, resolved across
+//     the layers like Fill does.
+//
+// "$${" is a literal "${". Expanded references are reported in the
+// References of the value location.
+func WithInterpolation() Option {
+	return interpolationOption{}
+}
+
+type interpolationEnvironOption func() []string
+
+func (o interpolationEnvironOption) apply(opts *internalOpts) error {
+	opts.environ = o
+	return nil
+}
+
+// WithInterpolationEnviron makes the ${env:NAME} references of the layer
+// read the KEY=value entries returned by environ instead of the process
+// environment. Environment layers read the entries set by WithEnviron or
+// WithEnvironFunc by default.
+func WithInterpolationEnviron(environ func() []string) Option {
+	return interpolationEnvironOption(environ)
+}
+
+// referenceResolver expands the references of layer values. Field
+// references are resolved against the raw values of the layers, in layer
+// order.
+type referenceResolver struct {
+	model  ModelInterface
+	layers []FieldValuesGetter
+
+	// converted paths of the fields being expanded, to detect cycles
+	pending []string
+	// paths of the fields being expanded, as reported in cycle errors
+	pendingPaths []string
+}
+
+// shareReferences sets a resolver over all the layers to the interpolating
+// ones, so field references are resolved like Fill does.
+func (c *dscoContext) shareReferences() {
+	if c.err.None() {
+		resolver := &referenceResolver{
+			model: c.model,
+		}
+
+		for _, builder := range c.builders {
+			layer := builder.getFieldValuesGetter()
+			resolver.layers = append(resolver.layers, layer)
+
+			if sb, ok := layer.(*StringBasedBuilder); ok && sb.interpolate {
+				sb.references = resolver
+			}
+		}
+	}
+}
+
+// interpolate expands the references of the raw value of the field located
+// at path, environment references being looked up with lookupEnv. It
+// returns the expanded value and the expanded references.
+func (r *referenceResolver) interpolate(
+	path string,
+	raw *svalue.Value,
+	lookupEnv func(name string) (string, bool),
+) (string, []string, error) {
+	value, references, err := r.expandField(path, raw.Value, lookupEnv)
+	if err != nil {
+		interpolationError := InterpolationError{
+			Location: raw.Location,
+			Err:      err,
+		}
+
+		var refErr referenceError
+		if errors.As(err, &refErr) {
+			interpolationError.Reference = refErr.reference
+			interpolationError.Err = refErr.err
+		}
+
+		return "", nil, interpolationError
+	}
+
+	return value, references, nil
+}
+
+// referenceError is an error when expanding reference.
+type referenceError struct {
+	err       error
+	reference string
+}
+
+func (a referenceError) Error() string {
+	return a.reference + ": " + a.err.Error()
+}
+
+func (a referenceError) Unwrap() error {
+	return a.err
+}
+
+// expandField expands value, the raw value of the field located at path,
+// detecting reference cycles.
+func (r *referenceResolver) expandField(
+	path, value string,
+	lookupEnv func(name string) (string, bool),
+) (string, []string, error) {
+	converted := convert(path)
+
+	for i, pending := range r.pending {
+		if pending == converted {
+			return "", nil, fmt.Errorf(
+				"%s -> %s: %w",
+				strings.Join(r.pendingPaths[i:], " -> "),
+				path,
+				ErrReferenceCycle,
+			)
+		}
+	}
+
+	r.pending = append(r.pending, converted)
+	r.pendingPaths = append(r.pendingPaths, path)
+
+	defer func() {
+		r.pending = r.pending[:len(r.pending)-1]
+		r.pendingPaths = r.pendingPaths[:len(r.pendingPaths)-1]
+	}()
+
+	return r.expand(value, lookupEnv)
+}
+
+// expand replaces every reference of value by its value.
+func (r *referenceResolver) expand(
+	value string,
+	lookupEnv func(name string) (string, bool),
+) (string, []string, error) {
+	var (
+		sb         strings.Builder
+		references []string
+	)
+
+	for {
+		start := strings.Index(value, refOpen)
+		if start < 0 {
+			sb.WriteString(value)
+			return sb.String(), references, nil
+		}
+
+		if start > 0 && strings.HasPrefix(value[start-1:], refEscape) {
+			sb.WriteString(value[:start-1])
+			sb.WriteString(refOpen)
+			value = value[start+len(refOpen):]
+
+			continue
+		}
+
+		sb.WriteString(value[:start])
+
+		end := strings.IndexByte(value[start:], refClose)
+		if end < 0 {
+			return "", nil, referenceError{
+				reference: value[start:],
+				err: fmt.Errorf(
+					"unterminated reference: %w",
+					ErrInvalidReference,
+				),
+			}
+		}
+
+		reference := value[start : start+end+1]
+
+		expanded, subReferences, err := r.expandReference(
+			reference[len(refOpen):len(reference)-1],
+			lookupEnv,
+		)
+		if err != nil {
+			return "", nil, referenceError{
+				reference: reference,
+				err:       err,
+			}
+		}
+
+		sb.WriteString(expanded)
+
+		references = append(references, subReferences...)
+		value = value[start+end+1:]
+	}
+}
+
+// expandReference returns the value of the reference scheme:argument and
+// the references it expands.
+func (r *referenceResolver) expandReference(
+	reference string,
+	lookupEnv func(name string) (string, bool),
+) (string, []string, error) {
+	scheme, argument, found := strings.Cut(reference, ":")
+	if !found || argument == "" {
+		return "", nil, fmt.Errorf(
+			"expecting scheme:argument: %w",
+			ErrInvalidReference,
+		)
+	}
+
+	scheme = strings.ToLower(scheme)
+	references := []string{scheme + ":" + argument}
+
+	switch scheme {
+	case schemeEnv:
+		value, found := lookupEnv(argument)
+		if !found {
+			return "", nil, fmt.Errorf(
+				"environment variable %s: %w",
+				argument,
+				ErrUnresolvedReference,
+			)
+		}
+
+		return value, references, nil
+
+	case schemeFile:
+		content, err := os.ReadFile(argument)
+		if err != nil {
+			return "", nil, fmt.Errorf(
+				"%w: %w",
+				err,
+				ErrUnresolvedReference,
+			)
+		}
+
+		return strings.TrimRight(string(content), "\r\n"), references, nil
+
+	case schemeRef:
+		value, location, subReferences, err := r.resolve(argument)
+		if err != nil {
+			return "", nil, err
+		}
+
+		references[0] += " from " + location
+
+		return value, append(references, subReferences...), nil
+
+	default:
+		return "", nil, fmt.Errorf(
+			"unknown scheme %q: %w",
+			scheme,
+			ErrInvalidReference,
+		)
+	}
+}
+
+// resolve returns the value of the field located at path provided by the
+// first layer, its location and the references it expands.
+func (r *referenceResolver) resolve(
+	path string,
+) (string, string, []string, error) {
+	converted := convert(path)
+
+	for _, layer := range r.layers {
+		switch l := layer.(type) {
+		case *StringBasedBuilder:
+			raw, found := l.raw[converted]
+			if !found {
+				continue
+			}
+
+			if !l.interpolate {
+				return raw.Value, raw.Location, nil, nil
+			}
+
+			value, references, err := r.expandField(
+				path,
+				raw.Value,
+				l.lookupEnv,
+			)
+
+			return value, raw.Location, references, err
+
+		case *StructBuilder:
+			if r.model == nil {
+				continue
+			}
+
+			fieldValue := findFieldValue(
+				r.model.GetFieldValuesFor(l.id, l.value),
+				converted,
+			)
+			if fieldValue != nil {
+				return textOf(fieldValue.Value), fieldValue.Location, nil, nil
+			}
+		}
+	}
+
+	return "", "", nil, fmt.Errorf(
+		"field %s: %w",
+		path,
+		ErrUnresolvedReference,
+	)
+}
+
+// findFieldValue returns the leaf value located at the converted path, or
+// nil.
+func findFieldValue(values fvalue.Values, converted string) *fvalue.Value {
+	for _, fieldValue := range values {
+		if fieldValue.Entries == nil {
+			if convert(fieldValue.Path) == converted {
+				return fieldValue
+			}
+
+			continue
+		}
+
+		for _, entryValues := range fieldValue.Entries {
+			if found := findFieldValue(entryValues, converted); found != nil {
+				return found
+			}
+		}
+	}
+
+	return nil
+}
+
+// textOf returns the text form of a field value, parsable back as YAML.
+// Collections are rendered in YAML flow style, i.e. [a, b].
+func textOf(value reflect.Value) string {
+	if value.CanInterface() {
+		switch v := value.Interface().(type) {
+		case encoding.TextMarshaler:
+			if text, err := v.MarshalText(); err == nil {
+				return string(text)
+			}
+		case fmt.Stringer:
+			return v.String()
+		}
+	}
+
+	indirect := reflect.Indirect(value)
+
+	switch indirect.Kind() { //nolint:exhaustive // scalars are printed
+	case reflect.Slice, reflect.Array, reflect.Map, reflect.Struct:
+		var node yaml.Node
+
+		if err := node.Encode(indirect.Interface()); err == nil {
+			node.Style = yaml.FlowStyle
+
+			if text, err := yaml.Marshal(&node); err == nil {
+				return strings.TrimRight(string(text), "\n")
+			}
+		}
+	}
+
+	return fmt.Sprint(indirect.Interface())
+}
@@ -0,0 +1,355 @@
+package dsco
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/byte4ever/dsco/internal/plocation"
+	"github.com/byte4ever/dsco/svalue"
+)
+
+type interpolatedDatabase struct {
+	Host     *string
+	Port     *int
+	Password *string `dsco:"secret"`
+}
+
+type interpolatedRoot struct {
+	Database *interpolatedDatabase
+	URL      *string
+	Home     *string
+	Literal  *string
+}
+
+//nolint:paralleltest // uses t.Setenv
+func TestFill_interpolation(t *testing.T) {
+	secretFile := filepath.Join(t.TempDir(), "password")
+	require.NoError(t, os.WriteFile(secretFile, []byte("hunter2\n"), 0o600))
+
+	t.Setenv("ITP-DATABASE-HOST", "db.local")
+	t.Setenv("ITP-DATABASE-PASSWORD", "${file:"+secretFile+"}")
+	t.Setenv(
+		"ITP-URL",
+		"postgres://${ref:database.host}:${ref:database.port}/app",
+	)
+	t.Setenv("ITP-HOME", "${env:INTERPOLATED_HOME}")
+	t.Setenv("INTERPOLATED_HOME", "/home/app")
+	t.Setenv("ITP-LITERAL", "$${ref:database.host}")
+
+	var pp *interpolatedRoot
+
+	locations, err := Fill(
+		&pp,
+		WithEnvLayer("ITP", WithInterpolation()),
+		WithStructLayer(
+			&interpolatedRoot{
+				Database: &interpolatedDatabase{Port: R(5432)},
+			},
+			"defaults",
+		),
+	)
+	require.NoError(t, err)
+
+	require.Equal(t, "db.local", *pp.Database.Host)
+	require.Equal(t, "hunter2", *pp.Database.Password)
+	require.Equal(t, "postgres://db.local:5432/app", *pp.URL)
+	require.Equal(t, "/home/app", *pp.Home)
+	require.Equal(t, "${ref:database.host}", *pp.Literal)
+
+	require.Equal(
+		t,
+		[]string{
+			"ref:database.host from env[ITP-DATABASE-HOST]",
+			"ref:database.port from struct[defaults]:Database.Port",
+		},
+		locationOf(t, locations, "URL").References,
+	)
+	require.Equal(
+		t,
+		[]string{"file:" + secretFile},
+		locationOf(t, locations, "Database.Password").References,
+	)
+	require.Equal(t, "env[ITP-HOME]", locationOf(t, locations, "Home").Location)
+	require.Nil(t, locationOf(t, locations, "Literal").References)
+}
+
+func TestFill_interpolationEnviron(t *testing.T) {
+	t.Parallel()
+
+	type Root struct {
+		Home *string
+		User *string
+	}
+
+	var pp *Root
+
+	_, err := Fill(
+		&pp,
+		WithEnvLayer(
+			"ITP",
+			WithEnviron(
+				map[string]string{
+					"ITP-HOME":             "${env:INTERPOLATED_ENVIRON}",
+					"INTERPOLATED_ENVIRON": "/home/injected",
+				},
+			),
+			WithInterpolation(),
+		),
+		WithCmdlineLayer(
+			WithArgs("--user=${env:INTERPOLATED_USER}"),
+			WithInterpolation(),
+			WithInterpolationEnviron(
+				func() []string {
+					return []string{"INTERPOLATED_USER=app"}
+				},
+			),
+		),
+	)
+	require.NoError(t, err)
+	require.Equal(t, "/home/injected", *pp.Home)
+	require.Equal(t, "app", *pp.User)
+
+	_, err = Fill(
+		&pp,
+		WithCmdlineLayer(
+			WithArgs("--home=/home", "--user=${env:INTERPOLATED_USER}"),
+			WithInterpolation(),
+			WithInterpolationEnviron(func() []string { return nil }),
+		),
+	)
+	require.ErrorContains(
+		t,
+		err,
+		"environment variable INTERPOLATED_USER: unresolved reference",
+	)
+}
+
+//nolint:paralleltest // uses t.Setenv
+func TestFill_withoutInterpolation(t *testing.T) {
+	t.Setenv("ITQ-HOME", "${env:HOME}")
+
+	type Root struct {
+		Home *string
+	}
+
+	var pp *Root
+
+	_, err := Fill(&pp, WithEnvLayer("ITQ"))
+	require.NoError(t, err)
+	require.Equal(t, "${env:HOME}", *pp.Home)
+}
+
+//nolint:paralleltest // uses t.Setenv
+func TestFill_interpolationErrors(t *testing.T) {
+	type Root struct {
+		A *string
+		B *string
+	}
+
+	tests := []struct {
+		name      string
+		a         string
+		b         string
+		reference string
+		err       error
+		contains  string
+	}{
+		{
+			name:      "cycle",
+			a:         "${ref:b}",
+			b:         "x${ref:a}",
+			reference: "${ref:b}",
+			err:       ErrReferenceCycle,
+			contains:  "A -> b -> a",
+		},
+		{
+			name:      "self",
+			a:         "${ref:a}",
+			b:         "b",
+			reference: "${ref:a}",
+			err:       ErrReferenceCycle,
+		},
+		{
+			name:      "unknown scheme",
+			a:         "${http:x}",
+			b:         "b",
+			reference: "${http:x}",
+			err:       ErrInvalidReference,
+		},
+		{
+			name:      "unterminated",
+			a:         "${env:HOME",
+			b:         "b",
+			reference: "${env:HOME",
+			err:       ErrInvalidReference,
+		},
+		{
+			name:      "missing field",
+			a:         "${ref:c}",
+			b:         "b",
+			reference: "${ref:c}",
+			err:       ErrUnresolvedReference,
+		},
+		{
+			name:      "missing variable",
+			a:         "${env:ITR_UNDEFINED_VARIABLE}",
+			b:         "b",
+			reference: "${env:ITR_UNDEFINED_VARIABLE}",
+			err:       ErrUnresolvedReference,
+		},
+		{
+			name:      "missing file",
+			a:         "${file:/non/existent/file}",
+			b:         "b",
+			reference: "${file:/non/existent/file}",
+			err:       ErrUnresolvedReference,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				t.Setenv("ITR-A", tt.a)
+				t.Setenv("ITR-B", tt.b)
+
+				var pp *Root
+
+				_, err := Fill(&pp, WithEnvLayer("ITR", WithInterpolation()))
+
+				var interpolationError InterpolationError
+
+				require.ErrorAs(t, err, &interpolationError)
+				require.ErrorIs(t, interpolationError, ErrInterpolation)
+				require.ErrorIs(t, interpolationError, tt.err)
+				require.Equal(t, "env[ITR-A]", interpolationError.Location)
+				require.Equal(t, tt.reference, interpolationError.Reference)
+
+				if tt.contains != "" {
+					require.ErrorContains(t, err, tt.contains)
+				}
+			},
+		)
+	}
+}
+
+func Test_referenceResolver_expand(t *testing.T) {
+	t.Parallel()
+
+	builder, err := NewStringBasedBuilder(
+		&stubValuesProvider{
+			values: svalue.Values{
+				"host": {Location: "stub[host]", Value: "h"},
+				"url":  {Location: "stub[url]", Value: "x${ref:host}y"},
+			},
+		},
+		WithInterpolation(),
+	)
+	require.NoError(t, err)
+
+	resolver := &referenceResolver{layers: []FieldValuesGetter{builder}}
+
+	for value, expected := range map[string]string{
+		"":                         "",
+		"plain":                    "plain",
+		"$plain":                   "$plain",
+		"a${ref:host}b":            "ahb",
+		"${ref:host}${ref:host}":   "hh",
+		"${ref:url}":               "xhy",
+		"$${ref:host}":             "${ref:host}",
+		"a$${ref:host}${ref:host}": "a${ref:host}h",
+	} {
+		got, _, err := resolver.expand(value, builder.lookupEnv)
+		require.NoError(t, err, value)
+		require.Equal(t, expected, got, value)
+	}
+
+	_, references, err := resolver.expand("${REF:url}", builder.lookupEnv)
+	require.NoError(t, err)
+	require.Equal(
+		t,
+		[]string{"ref:url from stub[url]", "ref:host from stub[host]"},
+		references,
+	)
+}
+
+func TestFill_interpolationCollections(t *testing.T) {
+	t.Parallel()
+
+	type Root struct {
+		Tags []string
+		Copy []string
+	}
+
+	var pp *Root
+
+	_, err := Fill(
+		&pp,
+		WithCmdlineLayer(
+			WithArgs("--copy=${ref:tags}"),
+			WithInterpolation(),
+		),
+		WithStructLayer(
+			&Root{Tags: []string{"a b", "c,d"}},
+			"defaults",
+		),
+	)
+	require.NoError(t, err)
+	require.Equal(t, []string{"a b", "c,d"}, pp.Copy)
+}
+
+func Test_textOf(t *testing.T) {
+	t.Parallel()
+
+	for expected, value := range map[string]any{
+		"8080":          R(8080),
+		"a b":           R("a b"),
+		"1s":            R(time.Second),
+		"[a, b]":        []string{"a", "b"},
+		"[a b, 'c,d']":  []string{"a b", "c,d"},
+		"{cpu: 2}":      map[string]int{"cpu": 2},
+		"[[1, 2], [3]]": [][]int{{1, 2}, {3}},
+	} {
+		require.Equal(t, expected, textOf(reflect.ValueOf(value)), expected)
+	}
+}
+
+func TestInterpolationError(t *testing.T) {
+	t.Parallel()
+
+	err := InterpolationError{
+		Location:  "env[A]",
+		Reference: "${ref:b}",
+		Err:       ErrUnresolvedReference,
+	}
+
+	require.ErrorIs(t, err, ErrInterpolation)
+	require.ErrorIs(t, err, ErrUnresolvedReference)
+	require.Equal(
+		t,
+		"interpolation error in env[A] on ${ref:b}: unresolved reference",
+		err.Error(),
+	)
+}
+
+func locationOf(
+	t *testing.T,
+	locations plocation.Locations,
+	path string,
+) plocation.Location {
+	t.Helper()
+
+	for _, location := range locations {
+		if location.Path == path {
+			return location
+		}
+	}
+
+	require.Failf(t, "location not found", "path %s", path)
+
+	return plocation.Location{}
+}
@@ -15,8 +15,8 @@ import (
 
 var update = flag.Bool("update", false, "update golden files")
 
-// fixtureReport returns a deterministic Report covering the three
-// interesting cases (key only, satisfied only, both).
+// fixtureReport returns a deterministic Report covering the interesting
+// cases (key only, satisfied only, both, secret, optional).
 func fixtureReport() *inventory.Report {
 	return &inventory.Report{
 		Type: "github.com/example/myapp.Config",
@@ -28,6 +28,14 @@ func fixtureReport() *inventory.Report {
 					Layer: "env", Key: "MYAPP-DATABASE-HOST",
 				},
 			},
+			{
+				Path:   "Database.Password",
+				GoType: "*string",
+				Secret: true,
+				Satisfied: &inventory.Satisfaction{
+					LayerID: "defaults", Value: "******",
+				},
+			},
 			{
 				Path:   "Database.Port",
 				GoType: "*int",
@@ -38,6 +46,14 @@ func fixtureReport() *inventory.Report {
 					Layer: "cmdline", Key: "--database-port=",
 				},
 			},
+			{
+				Path:     "Proxy.URL",
+				GoType:   "*url.URL",
+				Optional: true,
+				Key: &inventory.KeySpec{
+					Layer: "env", Key: "MYAPP-PROXY-URL",
+				},
+			},
 			{
 				Path:   "Server.Timeout",
 				GoType: "*time.Duration",
@@ -25,10 +25,14 @@ type (
 		Key       *KeySpec      `json:"key,omitempty"       yaml:"key,omitempty"`
 		Path      string        `json:"path"                yaml:"path"`
 		GoType    string        `json:"go_type"             yaml:"go_type"`
+		// Optional is true when Fill may leave the field nil.
+		Optional bool `json:"optional,omitempty" yaml:"optional,omitempty"`
+		// Secret is true when the field value is redacted.
+		Secret bool `json:"secret,omitempty" yaml:"secret,omitempty"`
 	}
 
 	// Satisfaction records that a struct layer already provides a value
-	// for this field.
+	// for this field. Values of secret fields are dsco.SecretMask.
 	Satisfaction struct {
 		Value   any    `json:"value"    yaml:"value"`
 		LayerID string `json:"layer_id" yaml:"layer_id"`
@@ -148,7 +152,12 @@ func reduce(
 	fields := make([]Field, 0, len(leaves))
 
 	for _, lf := range leaves {
-		field := Field{Path: lf.path, GoType: lf.goType}
+		field := Field{
+			Path:     lf.path,
+			GoType:   lf.goType,
+			Optional: mdl.IsOptional(lf.path),
+			Secret:   mdl.IsSecret(lf.path),
+		}
 
 		// Walk layers in declaration order; the first layer that supplies
 		// a Key or Satisfied value wins — matching Fill's first-wins
@@ -160,9 +169,14 @@ func reduce(
 				}
 
 				if field.Satisfied == nil && prov.Value != nil {
+					value := prov.Value
+					if field.Secret {
+						value = dsco.SecretMask
+					}
+
 					field.Satisfied = &Satisfaction{
 						LayerID: trimStructPrefix(inv.Name),
-						Value:   prov.Value,
+						Value:   value,
 					}
 				}
 
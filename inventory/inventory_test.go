@@ -131,3 +131,110 @@ func TestComputeErrorsSatisfyErrFiller(t *testing.T) {
 	require.Error(t, err)
 	assert.ErrorIs(t, err, dsco.ErrFiller)
 }
+
+// TestComputeKFileKeys verifies that kfile layers report the file name
+// expected for every field.
+func TestComputeKFileKeys(t *testing.T) {
+	t.Parallel()
+
+	type sub struct {
+		Password *string
+	}
+	type cfg struct {
+		Database *sub
+		APIKey   *string
+	}
+	var c *cfg
+
+	report, err := inventory.Compute(
+		&c,
+		dsco.WithKFileLayer(t.TempDir()),
+	)
+	require.NoError(t, err)
+	require.Len(t, report.Fields, 2)
+
+	keys := make(map[string]inventory.KeySpec, len(report.Fields))
+	for _, f := range report.Fields {
+		require.NotNil(t, f.Key)
+		keys[f.Path] = *f.Key
+	}
+
+	assert.Equal(
+		t,
+		map[string]inventory.KeySpec{
+			"APIKey":            {Layer: "kfile", Key: "API_KEY"},
+			"Database.Password": {Layer: "kfile", Key: "DATABASE-PASSWORD"},
+		},
+		keys,
+	)
+}
+
+// TestComputeOptionalFields verifies that optional leaves and leaves of
+// optional structs are reported as optional.
+func TestComputeOptionalFields(t *testing.T) {
+	t.Parallel()
+
+	type tls struct {
+		Cert *string
+	}
+
+	type cfg struct {
+		Host  *string
+		Proxy *string `dsco:"optional"`
+		TLS   *tls    `dsco:"optional"`
+	}
+	var c *cfg
+
+	report, err := inventory.Compute(&c, dsco.WithEnvLayer("MYAPP"))
+	require.NoError(t, err)
+
+	optional := make(map[string]bool)
+	for _, field := range report.Fields {
+		optional[field.Path] = field.Optional
+	}
+
+	assert.Equal(
+		t,
+		map[string]bool{"Host": false, "Proxy": true, "TLS.Cert": true},
+		optional,
+	)
+}
+
+// TestComputeSecretFields verifies that secret field defaults are
+// redacted.
+func TestComputeSecretFields(t *testing.T) {
+	t.Parallel()
+
+	type db struct {
+		User     *string
+		Password *string `dsco:"secret"`
+	}
+
+	type cfg struct {
+		DB     *db
+		Tokens *db `dsco:"secret"`
+	}
+	defaults := &cfg{
+		DB:     &db{User: dsco.R("app"), Password: dsco.R("hunter2")},
+		Tokens: &db{User: dsco.R("bot"), Password: dsco.R("s3cr3t")},
+	}
+	var c *cfg
+
+	report, err := inventory.Compute(
+		&c,
+		dsco.WithStructLayer(defaults, "defaults"),
+	)
+	require.NoError(t, err)
+
+	values := make(map[string]any)
+	for _, field := range report.Fields {
+		require.NotNil(t, field.Satisfied)
+		values[field.Path] = field.Satisfied.Value
+		assert.Equal(t, field.Path != "DB.User", field.Secret, field.Path)
+	}
+
+	assert.Equal(t, "******", values["DB.Password"])
+	assert.Equal(t, "******", values["Tokens.User"])
+	assert.Equal(t, "******", values["Tokens.Password"])
+	assert.Equal(t, "app", values["DB.User"])
+}
@@ -23,25 +23,31 @@ type (
 	}
 
 	// fieldJSON is a helper struct for JSON marshaling of Field.
-	// Fields are emitted in human-readable order: path, go_type, satisfied, key.
+	// Fields are emitted in human-readable order: path, go_type, optional,
+	// secret, satisfied, key.
 	// Field order is intentional for output readability; fieldalignment is
 	// secondary to serialization contract.
 	//nolint:govet // fieldalignment: output field order takes priority over struct padding
 	fieldJSON struct {
 		Path      string        `json:"path"`
 		GoType    string        `json:"go_type"`
+		Optional  bool          `json:"optional,omitempty"`
+		Secret    bool          `json:"secret,omitempty"`
 		Satisfied *Satisfaction `json:"satisfied,omitempty"`
 		Key       *KeySpec      `json:"key,omitempty"`
 	}
 
 	// fieldYAML is a helper struct for YAML marshaling of Field.
-	// Fields are emitted in human-readable order: path, go_type, satisfied, key.
+	// Fields are emitted in human-readable order: path, go_type, optional,
+	// secret, satisfied, key.
 	// Field order is intentional for output readability; fieldalignment is
 	// secondary to serialization contract.
 	//nolint:govet // fieldalignment: output field order takes priority over struct padding
 	fieldYAML struct {
 		Path      string        `yaml:"path"`
 		GoType    string        `yaml:"go_type"`
+		Optional  bool          `yaml:"optional,omitempty"`
+		Secret    bool          `yaml:"secret,omitempty"`
 		Satisfied *Satisfaction `yaml:"satisfied,omitempty"`
 		Key       *KeySpec      `yaml:"key,omitempty"`
 	}
@@ -94,11 +100,14 @@ func (s Satisfaction) MarshalYAML() (any, error) {
 }
 
 // MarshalJSON implements json.Marshaler so Field keys are emitted in
-// human-readable order: path, go_type, satisfied, key.
+// human-readable order: path, go_type, optional, secret, satisfied,
+// key.
 func (f Field) MarshalJSON() ([]byte, error) {
 	raw, err := gojson.Marshal(fieldJSON{
 		Path:      f.Path,
 		GoType:    f.GoType,
+		Optional:  f.Optional,
+		Secret:    f.Secret,
 		Satisfied: f.Satisfied,
 		Key:       f.Key,
 	})
@@ -110,11 +119,14 @@ func (f Field) MarshalJSON() ([]byte, error) {
 }
 
 // MarshalYAML implements yaml.InterfaceMarshaler so Field keys are emitted in
-// human-readable order: path, go_type, satisfied, key.
+// human-readable order: path, go_type, optional, secret, satisfied,
+// key.
 func (f Field) MarshalYAML() (any, error) {
 	return fieldYAML{
 		Path:      f.Path,
 		GoType:    f.GoType,
+		Optional:  f.Optional,
+		Secret:    f.Secret,
 		Satisfied: f.Satisfied,
 		Key:       f.Key,
 	}, nil
@@ -8,6 +8,7 @@ import (
 
 const (
 	emDash             = "—"
+	textOptional       = "optional"
 	textMinPath        = 20
 	textMinType        = 10
 	textMinKey         = 20
@@ -15,7 +16,8 @@ const (
 )
 
 // WriteText writes a human-readable, fixed-width-column inventory to writer.
-// Columns: PATH | TYPE | KEY | DEFAULT. Empty cells render as "—".
+// Columns: PATH | TYPE | KEY | DEFAULT. Empty cells render as "—", the
+// DEFAULT cell of optional fields without default renders as "optional".
 // Long default values are truncated with ellipsis. Output ends with a
 // trailing newline.
 func (r *Report) WriteText(writer io.Writer) error {
@@ -66,7 +68,7 @@ func buildTextRows(rep *Report) [][4]string {
 			fld.Path,
 			fld.GoType,
 			renderTextKey(fld.Key),
-			renderTextDefault(fld.Satisfied),
+			renderTextDefault(fld.Satisfied, fld.Optional),
 		})
 	}
 
@@ -83,8 +85,12 @@ func renderTextKey(ks *KeySpec) string {
 }
 
 // renderTextDefault renders a Satisfaction as "<layerID>=<value>" with
-// truncation, or em-dash when nil.
-func renderTextDefault(sat *Satisfaction) string {
+// truncation, or "optional" / em-dash when nil.
+func renderTextDefault(sat *Satisfaction, optional bool) string {
+	if sat == nil && optional {
+		return textOptional
+	}
+
 	if sat == nil {
 		return emDash
 	}
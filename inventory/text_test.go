@@ -66,3 +66,21 @@ func TestWriteTextPropagatesWriterError(t *testing.T) {
 	err := fixtureReport().WriteText(errWriter{err: assert.AnError})
 	require.ErrorIs(t, err, assert.AnError)
 }
+
+// TestWriteTextOptional verifies optional fields without default print
+// "optional" in the DEFAULT column.
+func TestWriteTextOptional(t *testing.T) {
+	t.Parallel()
+
+	rep := &inventory.Report{
+		Type: "Cfg",
+		Fields: []inventory.Field{
+			{Path: "Proxy", GoType: "*string", Optional: true},
+		},
+	}
+	var buf bytes.Buffer
+	require.NoError(t, rep.WriteText(&buf))
+
+	assert.Contains(t, buf.String(), "optional")
+	assert.NotContains(t, buf.String(), "—\n")
+}
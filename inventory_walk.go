@@ -86,7 +86,7 @@ func PrepareInventoryWalk(
 		return nil, fmt.Errorf("%s: %w", errCtx, err)
 	}
 
-	policies, err := Layers(layers).GetPolicies()
+	policies, err := Layers(layers).GetPolicies(mdl)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", errCtx, err)
 	}
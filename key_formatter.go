@@ -1,6 +1,10 @@
 package dsco
 
-import "strings"
+import (
+	"strings"
+
+	"github.com/byte4ever/dsco/internal/env"
+)
 
 type (
 	// KeyFormatter renders a layer-internal alias path into the canonical
@@ -9,7 +13,8 @@ type (
 	// Pattern: Strategy — each layer kind formats keys differently; the
 	// formatter is injected into StringBasedBuilder at construction time.
 	KeyFormatter interface {
-		// LayerKind returns the layer category (e.g. "env", "cmdline", "file").
+		// LayerKind returns the layer category (e.g. "env", "cmdline", "file",
+		// "kfile", "http").
 		// Empty for layers that cannot enumerate keys.
 		LayerKind() string
 
@@ -24,14 +29,39 @@ type (
 	}
 
 	// envKeyFormatter formats keys for environment-variable layers:
-	// PREFIX-UPPER-CASE-DASHED.
+	// PREFIX-UPPER-CASE-DASHED with the default naming scheme.
 	envKeyFormatter struct {
 		prefix string
+		naming env.Naming
 	}
 
 	// cmdlineKeyFormatter formats keys for command-line layers: --name=.
 	cmdlineKeyFormatter struct{}
 
+	// fileKeyFormatter formats keys for configuration file layers:
+	// dotted.lower.case.
+	fileKeyFormatter struct {
+		path string
+	}
+
+	// kfileKeyFormatter formats keys for kfile layers: the expected file
+	// name, UPPER-CASE-DASHED.
+	kfileKeyFormatter struct {
+		dir string
+	}
+
+	// httpKeyFormatter formats keys for HTTP layers: the JSON pointer of the
+	// key in the document, /lower/case.
+	httpKeyFormatter struct {
+		url string
+	}
+
+	// secretKeyFormatter formats keys for secret layers: fields have no
+	// key but the path#field reference set by their mapping.
+	secretKeyFormatter struct {
+		id string
+	}
+
 	// nilKeyFormatter is a no-op formatter for layers (custom string
 	// providers) that cannot enumerate keys statically. LayerKind is empty so
 	// reduce-pass logic skips them when picking a canonical key.
@@ -41,13 +71,36 @@ type (
 )
 
 func newEnvKeyFormatter(prefix string) *envKeyFormatter {
-	return &envKeyFormatter{prefix: prefix}
+	return newEnvKeyFormatterWithNaming(prefix, env.DefaultNaming())
+}
+
+func newEnvKeyFormatterWithNaming(
+	prefix string,
+	naming env.Naming,
+) *envKeyFormatter {
+	return &envKeyFormatter{prefix: strings.ToUpper(prefix), naming: naming}
 }
 
 func newCmdlineKeyFormatter() *cmdlineKeyFormatter {
 	return &cmdlineKeyFormatter{}
 }
 
+func newFileKeyFormatter(path string) *fileKeyFormatter {
+	return &fileKeyFormatter{path: path}
+}
+
+func newKFileKeyFormatter(dir string) *kfileKeyFormatter {
+	return &kfileKeyFormatter{dir: dir}
+}
+
+func newHTTPKeyFormatter(url string) *httpKeyFormatter {
+	return &httpKeyFormatter{url: url}
+}
+
+func newSecretKeyFormatter(id string) *secretKeyFormatter {
+	return &secretKeyFormatter{id: id}
+}
+
 func newNilKeyFormatter(name string) *nilKeyFormatter {
 	return &nilKeyFormatter{name: name}
 }
@@ -57,7 +110,7 @@ func (*envKeyFormatter) LayerKind() string { return "env" }
 func (f *envKeyFormatter) LayerName() string { return "env:" + f.prefix }
 
 func (f *envKeyFormatter) FormatKey(aliasPath string) string {
-	return f.prefix + "-" + strings.ToUpper(aliasPath)
+	return f.naming.FormatKey(f.prefix, aliasPath)
 }
 
 func (*cmdlineKeyFormatter) LayerKind() string { return "cmdline" }
@@ -68,6 +121,36 @@ func (*cmdlineKeyFormatter) FormatKey(aliasPath string) string {
 	return "--" + aliasPath + "="
 }
 
+func (*fileKeyFormatter) LayerKind() string { return "file" }
+
+func (f *fileKeyFormatter) LayerName() string { return "file:" + f.path }
+
+func (*fileKeyFormatter) FormatKey(aliasPath string) string {
+	return strings.ReplaceAll(aliasPath, "-", ".")
+}
+
+func (*kfileKeyFormatter) LayerKind() string { return "kfile" }
+
+func (f *kfileKeyFormatter) LayerName() string { return "kfile:" + f.dir }
+
+func (*kfileKeyFormatter) FormatKey(aliasPath string) string {
+	return strings.ToUpper(aliasPath)
+}
+
+func (*httpKeyFormatter) LayerKind() string { return "http" }
+
+func (f *httpKeyFormatter) LayerName() string { return "http:" + f.url }
+
+func (*httpKeyFormatter) FormatKey(aliasPath string) string {
+	return "/" + strings.ReplaceAll(aliasPath, "-", "/")
+}
+
+func (*secretKeyFormatter) LayerKind() string { return "secret" }
+
+func (f *secretKeyFormatter) LayerName() string { return "secret:" + f.id }
+
+func (*secretKeyFormatter) FormatKey(_ string) string { return "" }
+
 func (*nilKeyFormatter) LayerKind() string { return "" }
 
 func (f *nilKeyFormatter) LayerName() string { return f.name }
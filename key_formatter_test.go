@@ -4,6 +4,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/byte4ever/dsco/internal/env"
 )
 
 // TestEnvKeyFormatter verifies env-layer key formatting:
@@ -18,6 +20,19 @@ func TestEnvKeyFormatter(t *testing.T) {
 	assert.Equal(t, "MYAPP-MAX_RETRY", f.FormatKey("max_retry"))
 }
 
+// TestEnvKeyFormatter_naming verifies env-layer key formatting with a
+// naming scheme.
+func TestEnvKeyFormatter_naming(t *testing.T) {
+	t.Parallel()
+	f := newEnvKeyFormatterWithNaming("myapp", env.Naming(ShellEnvNaming()))
+
+	assert.Equal(t, "env:MYAPP", f.LayerName())
+	assert.Equal(t, "MYAPP_DATABASE__MAX_RETRY", f.FormatKey("database-max_retry"))
+
+	f = newEnvKeyFormatterWithNaming("", env.Naming(ShellEnvNaming()))
+	assert.Equal(t, "DATABASE__HOST", f.FormatKey("database-host"))
+}
+
 // TestCmdlineKeyFormatter verifies cmdline-layer key formatting:
 // dashes between segments, --name= prefix.
 func TestCmdlineKeyFormatter(t *testing.T) {
@@ -29,6 +44,30 @@ func TestCmdlineKeyFormatter(t *testing.T) {
 	assert.Equal(t, "--database-host=", f.FormatKey("database-host"))
 }
 
+// TestFileKeyFormatter verifies file-layer key formatting:
+// dots between segments, lowercase.
+func TestFileKeyFormatter(t *testing.T) {
+	t.Parallel()
+	f := newFileKeyFormatter("/etc/app.yaml")
+
+	assert.Equal(t, "file", f.LayerKind())
+	assert.Equal(t, "file:/etc/app.yaml", f.LayerName())
+	assert.Equal(t, "database.host", f.FormatKey("database-host"))
+	assert.Equal(t, "max_retry", f.FormatKey("max_retry"))
+}
+
+// TestKFileKeyFormatter verifies kfile-layer key formatting: the expected
+// file name, uppercase with dashes between segments.
+func TestKFileKeyFormatter(t *testing.T) {
+	t.Parallel()
+	f := newKFileKeyFormatter("/run/secrets")
+
+	assert.Equal(t, "kfile", f.LayerKind())
+	assert.Equal(t, "kfile:/run/secrets", f.LayerName())
+	assert.Equal(t, "DATABASE-HOST", f.FormatKey("database-host"))
+	assert.Equal(t, "MAX_RETRY", f.FormatKey("max_retry"))
+}
+
 // TestNilKeyFormatter verifies the no-op formatter returned when a layer
 // cannot enumerate keys (custom string providers).
 func TestNilKeyFormatter(t *testing.T) {
@@ -0,0 +1,268 @@
+package dsco
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/byte4ever/dsco/internal/file"
+	"github.com/byte4ever/dsco/internal/model"
+)
+
+// ErrKeyTagCollision represents an error where the key set by a key struct
+// tag is already the key of another field or an alias.
+var ErrKeyTagCollision = errors.New("key tag collision")
+
+// KeyTagCollisionError represents an error where the Key set by the Tag
+// struct tag (env, flag or file) of the field located at Path is already
+// used by Other, another field or an alias.
+type KeyTagCollisionError struct {
+	Tag   string
+	Key   string
+	Path  string
+	Other string
+}
+
+func (a KeyTagCollisionError) Error() string {
+	return fmt.Sprintf(
+		"%s key %q of %s collides with %s",
+		a.Tag,
+		a.Key,
+		a.Path,
+		a.Other,
+	)
+}
+
+func (KeyTagCollisionError) Is(err error) bool {
+	return errors.Is(err, ErrKeyTagCollision)
+}
+
+// shadowedKeyPrefix prefixes the shadowed keys of a layer, so they match no
+// field.
+const shadowedKeyPrefix = "\x00"
+
+// shadowOption shadows the keys derived from the path of the tagged fields.
+type shadowOption map[string]struct{}
+
+func (o shadowOption) apply(opts *internalOpts) error {
+	opts.shadowed = o
+	return nil
+}
+
+// keyTag describes how the keys of a key struct tag are bound by a layer.
+type keyTag struct {
+	// name is the struct tag name.
+	name string
+
+	// key returns the provider key of a tagged key.
+	key func(tagged string) string
+
+	// generated returns the provider key of the field located at path
+	// without tag.
+	generated func(path string) string
+}
+
+// flagKeyTag returns the key tag of command line layers, --db or db.
+func flagKeyTag() keyTag {
+	return keyTag{
+		name: model.FlagTag,
+		key: func(tagged string) string {
+			return strings.TrimLeft(tagged, "-")
+		},
+		generated: convert,
+	}
+}
+
+// fileKeyTag returns the key tag of configuration file layers, db.url.
+func fileKeyTag() keyTag {
+	return keyTag{
+		name: model.FileTag,
+		key: func(tagged string) string {
+			segments := strings.Split(tagged, ".")
+
+			for i, segment := range segments {
+				segments[i] = file.NormalizeKey(segment)
+			}
+
+			return strings.Join(segments, "-")
+		},
+		generated: convert,
+	}
+}
+
+// envKeyTag returns the key tag of environment layers, whose keys are
+// variable names, whatever the layer prefix.
+func envKeyTag(formatter KeyFormatter) keyTag {
+	return keyTag{
+		name: model.EnvTag,
+		key: func(tagged string) string {
+			return tagged
+		},
+		generated: func(path string) string {
+			return formatter.FormatKey(convert(path))
+		},
+	}
+}
+
+// bind returns the field paths by provider key of the tagged fields of the
+// model, once checked that keys don't collide with each other nor with the
+// key of another field. The model is nil when unknown.
+func (t keyTag) bind(mdl ModelInterface) (map[string]string, error) {
+	if mdl == nil {
+		return nil, nil //nolint:nilnil // no model, no tags
+	}
+
+	tagged := mdl.TaggedKeys(t.name)
+	if len(tagged) == 0 {
+		return nil, nil //nolint:nilnil // no tags
+	}
+
+	aliases, err := collectAliases(mdl)
+	if err != nil {
+		return nil, err
+	}
+
+	owners := make(map[string]string, len(aliases))
+	for path := range aliases {
+		owners[t.generated(path)] = path
+	}
+
+	paths := make([]string, 0, len(tagged))
+	for path := range tagged {
+		paths = append(paths, path)
+	}
+
+	sort.Strings(paths)
+
+	bound := make(map[string]string, len(tagged))
+
+	for _, path := range paths {
+		key := t.key(tagged[path])
+
+		other, found := bound[key]
+		if !found {
+			other, found = owners[key]
+		}
+
+		if found && other != path {
+			return nil, KeyTagCollisionError{
+				Tag:   t.name,
+				Key:   tagged[path],
+				Path:  path,
+				Other: other,
+			}
+		}
+
+		bound[key] = path
+	}
+
+	return bound, nil
+}
+
+// keyTagAliases returns the options with the aliases binding the tagged
+// keys of the model merged in the WithAliases ones, the keys derived from
+// the path of the tagged fields being shadowed, and the user facing keys of
+// the tagged fields by path.
+func keyTagAliases(
+	mdl ModelInterface,
+	tag keyTag,
+	formatter KeyFormatter,
+	options []Option,
+) ([]Option, map[string]string, error) {
+	bound, err := tag.bind(mdl)
+	if err != nil || len(bound) == 0 {
+		return options, nil, err
+	}
+
+	aliases, others := splitAliases(options)
+
+	merged := make(AliasesOption, len(aliases)+len(bound))
+	for key, target := range aliases {
+		merged[key] = target
+	}
+
+	keys := make(map[string]string, len(bound))
+	shadowed := make(shadowOption, len(bound))
+
+	for key, path := range bound {
+		target := convert(path)
+		keys[path] = formatter.FormatKey(key)
+
+		if key == target {
+			continue
+		}
+
+		shadowed[target] = struct{}{}
+
+		if previous, found := merged[key]; found && previous != target {
+			return nil, nil, KeyTagCollisionError{
+				Tag:   tag.name,
+				Key:   mdl.TaggedKeys(tag.name)[path],
+				Path:  path,
+				Other: "alias " + key,
+			}
+		}
+
+		merged[key] = target
+	}
+
+	if len(merged) > 0 {
+		others = append(others, merged, shadowed)
+	}
+
+	return others, keys, nil
+}
+
+// splitAliases separates the WithAliases option from the other options.
+func splitAliases(options []Option) (AliasesOption, []Option) {
+	var (
+		aliases AliasesOption
+		others  = make([]Option, 0, len(options)+2)
+	)
+
+	for _, option := range options {
+		if ao, ok := option.(AliasesOption); ok {
+			// like applyOptions, the last aliases option wins
+			aliases = ao
+			continue
+		}
+
+		others = append(others, option)
+	}
+
+	return aliases, others
+}
+
+// envTagAliases returns the provider keys of the variables named by env
+// tags and the options with the aliases binding them merged in the
+// WithAliases ones, the variables generated from the path of the tagged
+// fields being shadowed. Tagged variables are read under a prefixed key, so
+// they never mix with the generated ones.
+func envTagAliases(
+	bound map[string]string,
+	options []Option,
+) (map[string]string, []Option) {
+	if len(bound) == 0 {
+		return nil, options
+	}
+
+	aliases, others := splitAliases(options)
+
+	merged := make(AliasesOption, len(aliases)+len(bound))
+	for key, target := range aliases {
+		merged[key] = target
+	}
+
+	names := make(map[string]string, len(bound))
+	shadowed := make(shadowOption, len(bound))
+
+	for name, path := range bound {
+		key := shadowedKeyPrefix + name
+		names[name] = key
+		merged[key] = convert(path)
+		shadowed[convert(path)] = struct{}{}
+	}
+
+	return names, append(others, merged, shadowed)
+}
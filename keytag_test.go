@@ -0,0 +1,246 @@
+package dsco
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type keyTagDatabase struct {
+	URL      *string `env:"DATABASE_URL" flag:"db" file:"db.url"`
+	MaxConns *int    `flag:"-c"`
+}
+
+type keyTagConfig struct {
+	Database *keyTagDatabase
+	Port     *int `env:"PORT"`
+}
+
+func TestFill_keyTags(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "app.yaml")
+
+	require.NoError(
+		t,
+		os.WriteFile(path, []byte("db:\n  url: postgres://file\n"), 0o600),
+	)
+
+	var cfg *keyTagConfig
+
+	locations, err := Fill(
+		&cfg,
+		WithCmdlineLayer(WithArgs("-c", "10")),
+		WithEnvLayer(
+			"MYAPP",
+			WithEnviron(map[string]string{"PORT": "8080"}),
+		),
+		WithStrictFileLayer(path),
+	)
+	require.NoError(t, err)
+	require.Equal(t, "postgres://file", *cfg.Database.URL)
+	require.Equal(t, 10, *cfg.Database.MaxConns)
+	require.Equal(t, 8080, *cfg.Port)
+	require.Equal(
+		t,
+		"cmdline[-c]",
+		locationOf(t, locations, "Database.MaxConns").Location,
+	)
+	require.Equal(t, "env[PORT]", locationOf(t, locations, "Port").Location)
+
+	_, err = Fill(
+		&cfg,
+		WithCmdlineLayer(WithArgs("--db", "postgres://flag", "-c", "1")),
+		WithEnvLayer(
+			"MYAPP",
+			WithEnviron(
+				map[string]string{
+					"DATABASE_URL": "postgres://env",
+					"PORT":         "1",
+				},
+			),
+		),
+	)
+	require.NoError(t, err)
+	require.Equal(t, "postgres://flag", *cfg.Database.URL)
+
+	// generated keys of tagged fields are not bound anymore
+	_, err = Fill(
+		&cfg,
+		WithStrictCmdlineLayer(
+			WithArgs("--database-url=x", "--db=y", "-c=1", "--port=1"),
+		),
+	)
+	require.ErrorContains(t, err, "unbounded location cmdline[--database-url]")
+}
+
+func TestFill_envKeyTags(t *testing.T) {
+	t.Parallel()
+
+	environ := map[string]string{
+		"DATABASE_URL":             "postgres://tagged",
+		"MYAPP-DATABASE-URL":       "postgres://generated",
+		"MYAPP-DATABASE-MAX_CONNS": "10",
+		"PORT":                     "8080",
+	}
+
+	var cfg *keyTagConfig
+
+	locations, err := Fill(
+		&cfg,
+		WithEnvLayer("MYAPP", WithEnviron(environ)),
+	)
+	require.NoError(t, err)
+	require.Equal(t, "postgres://tagged", *cfg.Database.URL)
+	require.Equal(
+		t,
+		"env[DATABASE_URL]",
+		locationOf(t, locations, "Database.URL").Location,
+	)
+
+	// the generated variable of a tagged field doesn't fill it
+	_, err = Fill(
+		&cfg,
+		WithEnvLayer(
+			"MYAPP",
+			WithEnviron(
+				map[string]string{
+					"MYAPP-DATABASE-URL":       "postgres://generated",
+					"MYAPP-DATABASE-MAX_CONNS": "10",
+					"PORT":                     "8080",
+				},
+			),
+		),
+	)
+	require.ErrorContains(t, err, "Database.URL-[*string]: uninitialized key")
+	require.NotContains(t, err.Error(), "unbounded")
+
+	_, err = Fill(
+		&cfg,
+		WithStrictEnvLayer("MYAPP", WithEnviron(environ)),
+	)
+	require.ErrorContains(
+		t,
+		err,
+		"unbounded location env[MYAPP-DATABASE-URL]",
+	)
+	require.NotContains(t, err.Error(), "ambiguous")
+}
+
+func TestFill_keyTagCollisions(t *testing.T) {
+	t.Parallel()
+
+	type Duplicated struct {
+		A *string `flag:"x"`
+		B *string `flag:"--x"`
+	}
+
+	var dup *Duplicated
+
+	_, err := Fill(&dup, WithCmdlineLayer(WithArgs()))
+	require.ErrorContains(
+		t,
+		err,
+		`flag key "--x" of B collides with A`,
+	)
+
+	type Generated struct {
+		Host    *string `env:"MYAPP-PORT"`
+		Port    *int
+		Timeout *string `file:"port"`
+	}
+
+	var gen *Generated
+
+	_, err = Fill(&gen, WithEnvLayer("MYAPP", WithEnviron(nil)))
+	require.ErrorContains(
+		t,
+		err,
+		`env key "MYAPP-PORT" of Host collides with Port`,
+	)
+
+	type Aliased struct {
+		A *string `flag:"x"`
+		B *string
+	}
+
+	var aliased *Aliased
+
+	_, err = Fill(
+		&aliased,
+		WithCmdlineLayer(
+			WithArgs(),
+			WithAliases(map[string]string{"x": "b"}),
+		),
+	)
+	require.ErrorContains(t, err, `flag key "x" of A collides with alias x`)
+
+	var keyErr KeyTagCollisionError
+
+	layers := Layers{WithCmdlineLayer(WithArgs())}
+	mdl, err := buildModel(dup)
+	require.NoError(t, err)
+
+	_, err = layers.GetPolicies(mdl)
+	require.ErrorAs(t, err, &keyErr)
+	require.ErrorIs(t, keyErr, ErrKeyTagCollision)
+	require.Equal(t, "A", keyErr.Other)
+}
+
+func TestStringBasedBuilder_ReportInventory_keyTags(t *testing.T) {
+	t.Parallel()
+
+	var cfg *keyTagConfig
+
+	mdl, err := buildModel(cfg)
+	require.NoError(t, err)
+
+	policies, err := Layers{
+		WithCmdlineLayer(WithArgs()),
+		WithEnvLayer("MYAPP", WithEnviron(nil)),
+	}.GetPolicies(mdl)
+	require.NoError(t, err)
+
+	keys := make(map[string]map[string]string)
+
+	for _, policy := range policies {
+		builder, ok := policy.getFieldValuesGetter().(*StringBasedBuilder)
+		require.True(t, ok)
+
+		inv, err := builder.ReportInventory(mdl)
+		require.NoError(t, err)
+
+		keys[inv.Name] = make(map[string]string)
+		for _, provision := range inv.Provides {
+			keys[inv.Name][provision.FieldUID] = provision.Key
+		}
+	}
+
+	require.Equal(
+		t,
+		map[string]map[string]string{
+			"cmdline": {
+				"Database.URL":      "--db=",
+				"Database.MaxConns": "--c=",
+				"Port":              "--port=",
+			},
+			"env:MYAPP": {
+				"Database.URL":      "DATABASE_URL",
+				"Database.MaxConns": "MYAPP-DATABASE-MAX_CONNS",
+				"Port":              "PORT",
+			},
+		},
+		keys,
+	)
+
+	var buf bytes.Buffer
+
+	require.NoError(
+		t,
+		Usage(&buf, &cfg, WithEnvLayer("MYAPP", WithEnviron(nil))),
+	)
+	require.Contains(t, buf.String(), "  --db=<string>\n        (env: DATABASE_URL)")
+}
@@ -0,0 +1,71 @@
+package dsco
+
+import (
+	"errors"
+
+	"github.com/byte4ever/dsco/internal/kfile"
+)
+
+// ErrKFileOnlyOption represents an error where a kfile option is used by a
+// layer that is not a kfile layer.
+var ErrKFileOnlyOption = errors.New("option is only supported by kfile layers")
+
+// KFileOption is a processing option only supported by kfile layers.
+type KFileOption interface {
+	Option
+	kfileOption() kfile.Option
+}
+
+type kfileOption kfile.Option
+
+func (kfileOption) apply(*internalOpts) error {
+	return ErrKFileOnlyOption
+}
+
+func (o kfileOption) kfileOption() kfile.Option {
+	return kfile.Option(o)
+}
+
+// WithSilentFileErrors ignores errors reported while walking the kfile
+// directory tree.
+func WithSilentFileErrors() KFileOption {
+	return kfileOption(kfile.WithSilentFileErrors())
+}
+
+// WithTrimTrailingNewlines removes trailing new lines from kfile contents,
+// as most secrets are written by tools appending one.
+func WithTrimTrailingNewlines() KFileOption {
+	return kfileOption(kfile.WithTrimTrailingNewlines())
+}
+
+// WithFollowSymlinks supports the symlink layout of Kubernetes projected
+// volumes (..data and timestamped directories are skipped and the links are
+// followed).
+func WithFollowSymlinks() KFileOption {
+	return kfileOption(kfile.WithFollowSymlinks())
+}
+
+// WithMaxFileSize rejects kfiles larger than size bytes.
+func WithMaxFileSize(size int64) KFileOption {
+	return kfileOption(kfile.WithMaxFileSize(size))
+}
+
+// splitKFileOptions separates kfile provider options from string based
+// builder options.
+func splitKFileOptions(options []Option) ([]kfile.Option, []Option) {
+	var (
+		kfileOptions   []kfile.Option
+		builderOptions []Option
+	)
+
+	for _, option := range options {
+		if ko, ok := option.(KFileOption); ok {
+			kfileOptions = append(kfileOptions, ko.kfileOption())
+			continue
+		}
+
+		builderOptions = append(builderOptions, option)
+	}
+
+	return kfileOptions, builderOptions
+}
@@ -0,0 +1,146 @@
+package dsco
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestKFileOption_apply(t *testing.T) {
+	t.Parallel()
+
+	for _, option := range []KFileOption{
+		WithSilentFileErrors(),
+		WithTrimTrailingNewlines(),
+		WithFollowSymlinks(),
+		WithMaxFileSize(10),
+	} {
+		require.ErrorIs(
+			t,
+			option.apply(&internalOpts{}),
+			ErrKFileOnlyOption,
+		)
+		require.NotNil(t, option.kfileOption())
+	}
+}
+
+func Test_splitKFileOptions(t *testing.T) {
+	t.Parallel()
+
+	aliases := WithAliases(map[string]string{"a": "b"})
+
+	kfileOptions, builderOptions := splitKFileOptions(
+		[]Option{
+			WithTrimTrailingNewlines(),
+			aliases,
+			WithMaxFileSize(10),
+		},
+	)
+
+	require.Len(t, kfileOptions, 2)
+	require.Equal(t, []Option{aliases}, builderOptions)
+}
+
+func TestKFileOption_usedByOtherLayer(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.yaml")
+
+	require.NoError(t, os.WriteFile(path, []byte("a: 1\n"), 0o600))
+
+	err := WithFileLayer(path, WithTrimTrailingNewlines()).
+		register(newLayerBuilder(1))
+
+	require.ErrorIs(t, err, ErrKFileOnlyOption)
+}
+
+func TestFill_kfileLayer(t *testing.T) {
+	t.Parallel()
+
+	type Database struct {
+		Password *string
+		Port     *int
+	}
+
+	type Root struct {
+		Database *Database
+	}
+
+	dir := t.TempDir()
+
+	require.NoError(
+		t,
+		os.WriteFile(
+			filepath.Join(dir, "DATABASE-PASSWORD"),
+			[]byte("s3cr3t\n"),
+			0o600,
+		),
+	)
+	require.NoError(
+		t,
+		os.WriteFile(
+			filepath.Join(dir, "UNKNOWN"),
+			[]byte("value"),
+			0o600,
+		),
+	)
+
+	t.Run(
+		"normal",
+		func(t *testing.T) {
+			t.Parallel()
+
+			var pp *Root
+
+			locations, err := Fill(
+				&pp,
+				WithKFileLayer(dir, WithTrimTrailingNewlines()),
+				WithStructLayer(
+					&Root{
+						Database: &Database{
+							Port: R(5432),
+						},
+					},
+					"defaults",
+				),
+			)
+			require.NoError(t, err)
+			require.Equal(t, "s3cr3t", *pp.Database.Password)
+			require.Equal(
+				t,
+				"kfile["+dir+"]:DATABASE-PASSWORD",
+				locations[0].Location,
+			)
+		},
+	)
+
+	t.Run(
+		"strict",
+		func(t *testing.T) {
+			t.Parallel()
+
+			var pp *Root
+
+			_, err := Fill(
+				&pp,
+				WithStrictKFileLayer(dir),
+				WithStructLayer(
+					&Root{
+						Database: &Database{
+							Port: R(5432),
+						},
+					},
+					"defaults",
+				),
+			)
+
+			var ue UnboundedLocationError
+
+			require.ErrorAs(t, err, &ue)
+			require.Equal(t, "kfile["+dir+"]:UNKNOWN", ue.Location)
+		},
+	)
+}
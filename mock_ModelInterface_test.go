@@ -3,11 +3,17 @@
 package dsco
 
 import (
+	hash "hash"
+
+	hit "github.com/byte4ever/dsco/hit"
+
 	internal "github.com/byte4ever/dsco/internal"
 	fvalue "github.com/byte4ever/dsco/internal/fvalue"
 
 	mock "github.com/stretchr/testify/mock"
 
+	model "github.com/byte4ever/dsco/internal/model"
+
 	plocation "github.com/byte4ever/dsco/internal/plocation"
 
 	reflect "reflect"
@@ -84,6 +90,52 @@ func (_c *MockModelInterface_ApplyOn_Call) RunAndReturn(run func(internal.ValueG
 	return _c
 }
 
+// Description provides a mock function with given fields: path
+func (_m *MockModelInterface) Description(path string) string {
+	ret := _m.Called(path)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Description")
+	}
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(string) string); ok {
+		r0 = rf(path)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// MockModelInterface_Description_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Description'
+type MockModelInterface_Description_Call struct {
+	*mock.Call
+}
+
+// Description is a helper method to define mock.On call
+//   - path string
+func (_e *MockModelInterface_Expecter) Description(path interface{}) *MockModelInterface_Description_Call {
+	return &MockModelInterface_Description_Call{Call: _e.mock.On("Description", path)}
+}
+
+func (_c *MockModelInterface_Description_Call) Run(run func(path string)) *MockModelInterface_Description_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *MockModelInterface_Description_Call) Return(_a0 string) *MockModelInterface_Description_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockModelInterface_Description_Call) RunAndReturn(run func(string) string) *MockModelInterface_Description_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Expand provides a mock function with given fields: g
 func (_m *MockModelInterface) Expand(g internal.StructExpander) error {
 	ret := _m.Called(g)
@@ -189,6 +241,55 @@ func (_c *MockModelInterface_Fill_Call) RunAndReturn(run func(reflect.Value, []f
 	return _c
 }
 
+// Fingerprint provides a mock function with given fields: hashProvider, value
+func (_m *MockModelInterface) Fingerprint(hashProvider hit.HashProvider[hash.Hash], value reflect.Value) map[string][]byte {
+	ret := _m.Called(hashProvider, value)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Fingerprint")
+	}
+
+	var r0 map[string][]byte
+	if rf, ok := ret.Get(0).(func(hit.HashProvider[hash.Hash], reflect.Value) map[string][]byte); ok {
+		r0 = rf(hashProvider, value)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string][]byte)
+		}
+	}
+
+	return r0
+}
+
+// MockModelInterface_Fingerprint_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Fingerprint'
+type MockModelInterface_Fingerprint_Call struct {
+	*mock.Call
+}
+
+// Fingerprint is a helper method to define mock.On call
+//   - hashProvider hit.HashProvider[hash.Hash]
+//   - value reflect.Value
+func (_e *MockModelInterface_Expecter) Fingerprint(hashProvider interface{}, value interface{}) *MockModelInterface_Fingerprint_Call {
+	return &MockModelInterface_Fingerprint_Call{Call: _e.mock.On("Fingerprint", hashProvider, value)}
+}
+
+func (_c *MockModelInterface_Fingerprint_Call) Run(run func(hashProvider hit.HashProvider[hash.Hash], value reflect.Value)) *MockModelInterface_Fingerprint_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(hit.HashProvider[hash.Hash]), args[1].(reflect.Value))
+	})
+	return _c
+}
+
+func (_c *MockModelInterface_Fingerprint_Call) Return(_a0 map[string][]byte) *MockModelInterface_Fingerprint_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockModelInterface_Fingerprint_Call) RunAndReturn(run func(hit.HashProvider[hash.Hash], reflect.Value) map[string][]byte) *MockModelInterface_Fingerprint_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetFieldValuesFor provides a mock function with given fields: id, v
 func (_m *MockModelInterface) GetFieldValuesFor(id string, v reflect.Value) fvalue.Values {
 	ret := _m.Called(id, v)
@@ -238,6 +339,192 @@ func (_c *MockModelInterface_GetFieldValuesFor_Call) RunAndReturn(run func(strin
 	return _c
 }
 
+// IsDeprecated provides a mock function with given fields: path
+func (_m *MockModelInterface) IsDeprecated(path string) bool {
+	ret := _m.Called(path)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IsDeprecated")
+	}
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(string) bool); ok {
+		r0 = rf(path)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// MockModelInterface_IsDeprecated_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IsDeprecated'
+type MockModelInterface_IsDeprecated_Call struct {
+	*mock.Call
+}
+
+// IsDeprecated is a helper method to define mock.On call
+//   - path string
+func (_e *MockModelInterface_Expecter) IsDeprecated(path interface{}) *MockModelInterface_IsDeprecated_Call {
+	return &MockModelInterface_IsDeprecated_Call{Call: _e.mock.On("IsDeprecated", path)}
+}
+
+func (_c *MockModelInterface_IsDeprecated_Call) Run(run func(path string)) *MockModelInterface_IsDeprecated_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *MockModelInterface_IsDeprecated_Call) Return(_a0 bool) *MockModelInterface_IsDeprecated_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockModelInterface_IsDeprecated_Call) RunAndReturn(run func(string) bool) *MockModelInterface_IsDeprecated_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// IsOptional provides a mock function with given fields: path
+func (_m *MockModelInterface) IsOptional(path string) bool {
+	ret := _m.Called(path)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IsOptional")
+	}
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(string) bool); ok {
+		r0 = rf(path)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// MockModelInterface_IsOptional_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IsOptional'
+type MockModelInterface_IsOptional_Call struct {
+	*mock.Call
+}
+
+// IsOptional is a helper method to define mock.On call
+//   - path string
+func (_e *MockModelInterface_Expecter) IsOptional(path interface{}) *MockModelInterface_IsOptional_Call {
+	return &MockModelInterface_IsOptional_Call{Call: _e.mock.On("IsOptional", path)}
+}
+
+func (_c *MockModelInterface_IsOptional_Call) Run(run func(path string)) *MockModelInterface_IsOptional_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *MockModelInterface_IsOptional_Call) Return(_a0 bool) *MockModelInterface_IsOptional_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockModelInterface_IsOptional_Call) RunAndReturn(run func(string) bool) *MockModelInterface_IsOptional_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// IsSecret provides a mock function with given fields: path
+func (_m *MockModelInterface) IsSecret(path string) bool {
+	ret := _m.Called(path)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IsSecret")
+	}
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(string) bool); ok {
+		r0 = rf(path)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// MockModelInterface_IsSecret_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IsSecret'
+type MockModelInterface_IsSecret_Call struct {
+	*mock.Call
+}
+
+// IsSecret is a helper method to define mock.On call
+//   - path string
+func (_e *MockModelInterface_Expecter) IsSecret(path interface{}) *MockModelInterface_IsSecret_Call {
+	return &MockModelInterface_IsSecret_Call{Call: _e.mock.On("IsSecret", path)}
+}
+
+func (_c *MockModelInterface_IsSecret_Call) Run(run func(path string)) *MockModelInterface_IsSecret_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *MockModelInterface_IsSecret_Call) Return(_a0 bool) *MockModelInterface_IsSecret_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockModelInterface_IsSecret_Call) RunAndReturn(run func(string) bool) *MockModelInterface_IsSecret_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// TaggedKeys provides a mock function with given fields: tag
+func (_m *MockModelInterface) TaggedKeys(tag string) map[string]string {
+	ret := _m.Called(tag)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TaggedKeys")
+	}
+
+	var r0 map[string]string
+	if rf, ok := ret.Get(0).(func(string) map[string]string); ok {
+		r0 = rf(tag)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]string)
+		}
+	}
+
+	return r0
+}
+
+// MockModelInterface_TaggedKeys_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'TaggedKeys'
+type MockModelInterface_TaggedKeys_Call struct {
+	*mock.Call
+}
+
+// TaggedKeys is a helper method to define mock.On call
+//   - tag string
+func (_e *MockModelInterface_Expecter) TaggedKeys(tag interface{}) *MockModelInterface_TaggedKeys_Call {
+	return &MockModelInterface_TaggedKeys_Call{Call: _e.mock.On("TaggedKeys", tag)}
+}
+
+func (_c *MockModelInterface_TaggedKeys_Call) Run(run func(tag string)) *MockModelInterface_TaggedKeys_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *MockModelInterface_TaggedKeys_Call) Return(_a0 map[string]string) *MockModelInterface_TaggedKeys_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockModelInterface_TaggedKeys_Call) RunAndReturn(run func(string) map[string]string) *MockModelInterface_TaggedKeys_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // TypeName provides a mock function with no fields
 func (_m *MockModelInterface) TypeName() string {
 	ret := _m.Called()
@@ -283,6 +570,54 @@ func (_c *MockModelInterface_TypeName_Call) RunAndReturn(run func() string) *Moc
 	return _c
 }
 
+// Validate provides a mock function with given fields: inputModelValue
+func (_m *MockModelInterface) Validate(inputModelValue reflect.Value) []model.Violation {
+	ret := _m.Called(inputModelValue)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Validate")
+	}
+
+	var r0 []model.Violation
+	if rf, ok := ret.Get(0).(func(reflect.Value) []model.Violation); ok {
+		r0 = rf(inputModelValue)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Violation)
+		}
+	}
+
+	return r0
+}
+
+// MockModelInterface_Validate_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Validate'
+type MockModelInterface_Validate_Call struct {
+	*mock.Call
+}
+
+// Validate is a helper method to define mock.On call
+//   - inputModelValue reflect.Value
+func (_e *MockModelInterface_Expecter) Validate(inputModelValue interface{}) *MockModelInterface_Validate_Call {
+	return &MockModelInterface_Validate_Call{Call: _e.mock.On("Validate", inputModelValue)}
+}
+
+func (_c *MockModelInterface_Validate_Call) Run(run func(inputModelValue reflect.Value)) *MockModelInterface_Validate_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(reflect.Value))
+	})
+	return _c
+}
+
+func (_c *MockModelInterface_Validate_Call) Return(_a0 []model.Violation) *MockModelInterface_Validate_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockModelInterface_Validate_Call) RunAndReturn(run func(reflect.Value) []model.Violation) *MockModelInterface_Validate_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewMockModelInterface creates a new instance of MockModelInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewMockModelInterface(t interface {
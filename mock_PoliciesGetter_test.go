@@ -2,7 +2,11 @@
 
 package dsco
 
-import mock "github.com/stretchr/testify/mock"
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
 
 // MockPoliciesGetter is an autogenerated mock type for the PoliciesGetter type
 type MockPoliciesGetter struct {
@@ -17,29 +21,29 @@ func (_m *MockPoliciesGetter) EXPECT() *MockPoliciesGetter_Expecter {
 	return &MockPoliciesGetter_Expecter{mock: &_m.Mock}
 }
 
-// GetPolicies provides a mock function with no fields
-func (_m *MockPoliciesGetter) GetPolicies() (constraintLayerPolicies, error) {
-	ret := _m.Called()
+// GetPoliciesContext provides a mock function with given fields: ctx, model
+func (_m *MockPoliciesGetter) GetPoliciesContext(ctx context.Context, model ModelInterface) (constraintLayerPolicies, error) {
+	ret := _m.Called(ctx, model)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetPolicies")
+		panic("no return value specified for GetPoliciesContext")
 	}
 
 	var r0 constraintLayerPolicies
 	var r1 error
-	if rf, ok := ret.Get(0).(func() (constraintLayerPolicies, error)); ok {
-		return rf()
+	if rf, ok := ret.Get(0).(func(context.Context, ModelInterface) (constraintLayerPolicies, error)); ok {
+		return rf(ctx, model)
 	}
-	if rf, ok := ret.Get(0).(func() constraintLayerPolicies); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(context.Context, ModelInterface) constraintLayerPolicies); ok {
+		r0 = rf(ctx, model)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(constraintLayerPolicies)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(context.Context, ModelInterface) error); ok {
+		r1 = rf(ctx, model)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -47,29 +51,31 @@ func (_m *MockPoliciesGetter) GetPolicies() (constraintLayerPolicies, error) {
 	return r0, r1
 }
 
-// MockPoliciesGetter_GetPolicies_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetPolicies'
-type MockPoliciesGetter_GetPolicies_Call struct {
+// MockPoliciesGetter_GetPoliciesContext_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetPoliciesContext'
+type MockPoliciesGetter_GetPoliciesContext_Call struct {
 	*mock.Call
 }
 
-// GetPolicies is a helper method to define mock.On call
-func (_e *MockPoliciesGetter_Expecter) GetPolicies() *MockPoliciesGetter_GetPolicies_Call {
-	return &MockPoliciesGetter_GetPolicies_Call{Call: _e.mock.On("GetPolicies")}
+// GetPoliciesContext is a helper method to define mock.On call
+//   - ctx context.Context
+//   - model ModelInterface
+func (_e *MockPoliciesGetter_Expecter) GetPoliciesContext(ctx interface{}, model interface{}) *MockPoliciesGetter_GetPoliciesContext_Call {
+	return &MockPoliciesGetter_GetPoliciesContext_Call{Call: _e.mock.On("GetPoliciesContext", ctx, model)}
 }
 
-func (_c *MockPoliciesGetter_GetPolicies_Call) Run(run func()) *MockPoliciesGetter_GetPolicies_Call {
+func (_c *MockPoliciesGetter_GetPoliciesContext_Call) Run(run func(ctx context.Context, model ModelInterface)) *MockPoliciesGetter_GetPoliciesContext_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run()
+		run(args[0].(context.Context), args[1].(ModelInterface))
 	})
 	return _c
 }
 
-func (_c *MockPoliciesGetter_GetPolicies_Call) Return(_a0 constraintLayerPolicies, _a1 error) *MockPoliciesGetter_GetPolicies_Call {
+func (_c *MockPoliciesGetter_GetPoliciesContext_Call) Return(_a0 constraintLayerPolicies, _a1 error) *MockPoliciesGetter_GetPoliciesContext_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *MockPoliciesGetter_GetPolicies_Call) RunAndReturn(run func() (constraintLayerPolicies, error)) *MockPoliciesGetter_GetPolicies_Call {
+func (_c *MockPoliciesGetter_GetPoliciesContext_Call) RunAndReturn(run func(context.Context, ModelInterface) (constraintLayerPolicies, error)) *MockPoliciesGetter_GetPoliciesContext_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -1,10 +1,13 @@
 package dsco
 
 import (
+	"hash"
 	"reflect"
 
+	"github.com/byte4ever/dsco/hit"
 	"github.com/byte4ever/dsco/internal"
 	"github.com/byte4ever/dsco/internal/fvalue"
+	"github.com/byte4ever/dsco/internal/model"
 	"github.com/byte4ever/dsco/internal/plocation"
 )
 
@@ -46,4 +49,36 @@ type ModelInterface interface {
 		inputModelValue reflect.Value,
 		layers []fvalue.Values,
 	) (plocation.Locations, error)
+
+	// IsOptional returns true when the field located at path may be left
+	// nil by Fill, being optional or part of an optional struct.
+	IsOptional(path string) bool
+
+	// IsSecret returns true when the value of the field located at path
+	// must be redacted, being secret or part of a secret struct. Paths of
+	// collection element fields (i.e. Backends[eu].Password) are supported.
+	IsSecret(path string) bool
+
+	// IsDeprecated returns true when the field located at path is
+	// deprecated, being tagged deprecated or part of a deprecated struct.
+	IsDeprecated(path string) bool
+
+	// Description returns the desc tag of the field located at path, or an
+	// empty string.
+	Description(path string) string
+
+	// TaggedKeys returns the keys set by a key struct tag (model.EnvTag,
+	// model.FlagTag or model.FileTag) by field path.
+	TaggedKeys(tag string) map[string]string
+
+	// Validate checks the filled struct against the field rules and the
+	// Validate methods of its structs. Returns every violation found.
+	Validate(inputModelValue reflect.Value) []model.Violation
+
+	// Fingerprint returns the Merkle tree hashes of the filled struct by
+	// path, the root hash being at the empty path.
+	Fingerprint(
+		hashProvider hit.HashProvider[hash.Hash],
+		value reflect.Value,
+	) map[string][]byte
 }
@@ -0,0 +1,433 @@
+package dsco
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/byte4ever/dsco/registry"
+)
+
+// ErrParse represents an error indicating that a value cannot be parsed.
+var ErrParse = errors.New("parse error")
+
+// ParseError represents an error where the value of the field located at
+// Path can't be parsed as Type.
+type ParseError struct {
+	// Err is the cause, the decoder or YAML error, unless the value is
+	// secret.
+	Err error
+
+	Path     string
+	Type     reflect.Type
+	Location string
+
+	// Value is the offending raw value, SecretMask when it is secret.
+	Value string
+
+	// Hint tells what was wrong, i.e. "expected integer, got 'eighty'".
+	Hint string
+
+	// Line and Column locate the offending value inside a YAML blob,
+	// starting at 1, zero when unknown.
+	Line   int
+	Column int
+}
+
+func (a ParseError) Error() string {
+	var sb strings.Builder
+
+	_, _ = fmt.Fprintf(
+		&sb,
+		"parse error on %s-<%s> %s",
+		a.Path,
+		registry.LongTypeName(a.Type),
+		a.Location,
+	)
+
+	if a.Line > 0 {
+		_, _ = fmt.Fprintf(&sb, " at line %d", a.Line)
+
+		if a.Column > 0 {
+			_, _ = fmt.Fprintf(&sb, ", column %d", a.Column)
+		}
+	}
+
+	switch {
+	case a.Hint != "":
+		sb.WriteString(": " + a.Hint)
+	case a.Err != nil:
+		sb.WriteString(": " + a.Err.Error())
+	}
+
+	return sb.String()
+}
+
+func (ParseError) Is(err error) bool {
+	return errors.Is(err, ErrParse)
+}
+
+func (a ParseError) Unwrap() error {
+	return a.Err
+}
+
+// errSecretReason replaces the cause of a secret value parse error, which
+// may hold the value.
+var errSecretReason = errors.New("reason redacted, the value is secret")
+
+// yamlLine matches the line of YAML syntax errors.
+var yamlLine = regexp.MustCompile(`^yaml: line (\d+): `)
+
+//nolint:gochecknoglobals // immutable
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+	timeType     = reflect.TypeOf(time.Time{})
+)
+
+// decodeValue decodes text into the value pointed by target with the
+// decoder registered for its type, its encoding.TextUnmarshaler
+// implementation or YAML unmarshalling, in this order. The items of a slice
+// whose element type has a registered decoder are decoded with it. viaYAML
+// is true when the error is a YAML one.
+func decodeValue(text string, target reflect.Value) (bool, error) {
+	if decode, found := registry.DecoderFor(target.Type()); found {
+		return false, decode(text, target)
+	}
+
+	if u, found := registry.TextUnmarshaler(target); found {
+		return false, u.UnmarshalText([]byte(text))
+	}
+
+	if target.Elem().Kind() == reflect.Slice {
+		decode, found := registry.DecoderFor(
+			reflect.PointerTo(target.Type().Elem().Elem()),
+		)
+		if found {
+			return decodeSequence(text, target, decode)
+		}
+	}
+
+	return true, yaml.Unmarshal([]byte(text), target.Interface())
+}
+
+// decodeSequence decodes text, a YAML sequence, into the slice pointed by
+// target, decoding its items with decode. viaYAML is true when the error
+// is a YAML one.
+func decodeSequence(
+	text string,
+	target reflect.Value,
+	decode registry.DecodeFunc,
+) (bool, error) {
+	var items []string
+
+	if err := yaml.Unmarshal([]byte(text), &items); err != nil {
+		return true, err //nolint:wrapcheck // reported as a parse error
+	}
+
+	if items == nil {
+		return false, nil
+	}
+
+	slice := reflect.MakeSlice(target.Type().Elem(), len(items), len(items))
+
+	for i, item := range items {
+		if err := decode(item, slice.Index(i).Addr()); err != nil {
+			return false, fmt.Errorf("item %d: %w", i, err)
+		}
+	}
+
+	target.Elem().Set(slice)
+
+	return false, nil
+}
+
+// valueParseError returns the ParseError of text, the value of the field
+// located at path. The hint holds the reason of a decoder error, YAML ones
+// being too terse.
+func (s *StringBasedBuilder) valueParseError(
+	path string,
+	_type reflect.Type,
+	location, text string,
+	cause error,
+	viaYAML bool,
+) ParseError {
+	if s.isSecret(path) {
+		text, cause, viaYAML = SecretMask, errSecretReason, true
+	}
+
+	hint := expectedHint(_type, "'"+text+"'")
+	if !viaYAML {
+		hint += ": " + cause.Error()
+	}
+
+	parseError := ParseError{
+		Err:      cause,
+		Path:     path,
+		Type:     _type,
+		Location: location,
+		Value:    text,
+		Hint:     hint,
+	}
+
+	if node, found := s.nodes[convert(path)]; found {
+		parseError.Line, parseError.Column = node.Line, node.Column
+	}
+
+	return parseError
+}
+
+// blobParseError returns the ParseError of text, the YAML blob of the
+// collection or struct located at path, locating the offending value
+// inside the blob, or inside the collection blob it was spread from.
+func (s *StringBasedBuilder) blobParseError(
+	path string,
+	_type reflect.Type,
+	location, text string,
+	cause error,
+) ParseError {
+	doc, err := s.blobNode(path, text)
+	if err != nil {
+		parseError := ParseError{
+			Err:      err,
+			Path:     path,
+			Type:     _type,
+			Location: location,
+			Hint:     err.Error(),
+		}
+
+		if match := yamlLine.FindStringSubmatch(err.Error()); match != nil {
+			parseError.Line, _ = strconv.Atoi(match[1])
+			parseError.Hint = err.Error()[len(match[0]):]
+		}
+
+		return parseError
+	}
+
+	node, nodeType, nodePath := locateYAMLError(doc, _type, path)
+	if node == nil {
+		return ParseError{
+			Err:      cause,
+			Path:     path,
+			Type:     _type,
+			Location: location,
+		}
+	}
+
+	return s.nodeParseError(
+		path,
+		_type,
+		location,
+		node,
+		nodeType,
+		nodePath,
+		cause,
+	)
+}
+
+// blobNode returns the YAML node of text, the blob of the field located at
+// path, or the node it was spread from.
+func (s *StringBasedBuilder) blobNode(
+	path, text string,
+) (*yaml.Node, error) {
+	if node, found := s.nodes[convert(path)]; found {
+		return node, nil
+	}
+
+	var doc yaml.Node
+
+	if err := yaml.Unmarshal([]byte(text), &doc); err != nil {
+		return nil, err //nolint:wrapcheck // reported as a parse error
+	}
+
+	return &doc, nil
+}
+
+// nodeParseError returns the ParseError of node, the offending node of
+// type nodeType located at nodePath in the YAML blob of the field located
+// at path.
+func (s *StringBasedBuilder) nodeParseError(
+	path string,
+	_type reflect.Type,
+	location string,
+	node *yaml.Node,
+	nodeType reflect.Type,
+	nodePath string,
+	cause error,
+) ParseError {
+	parseError := ParseError{
+		Err:      cause,
+		Path:     path,
+		Type:     _type,
+		Location: location,
+		Line:     node.Line,
+		Column:   node.Column,
+	}
+
+	got := nodeDescription(node)
+
+	if node.Kind == yaml.ScalarNode {
+		parseError.Value = node.Value
+
+		if s.isSecret(nodePath) {
+			parseError.Err = errSecretReason
+			parseError.Value = SecretMask
+			got = "'" + SecretMask + "'"
+		}
+	}
+
+	parseError.Hint = expectedHint(nodeType, got)
+
+	return parseError
+}
+
+// locateYAMLError returns the innermost node of the YAML node that can't be
+// decoded as _type, with its type and the path of its field, path being
+// the path of the node. It returns a nil node when the node decodes.
+func locateYAMLError(
+	node *yaml.Node,
+	_type reflect.Type,
+	path string,
+) (*yaml.Node, reflect.Type, string) {
+	if node.Decode(reflect.New(_type).Interface()) == nil {
+		return nil, nil, ""
+	}
+
+	elemType := _type
+	for elemType.Kind() == reflect.Pointer {
+		elemType = elemType.Elem()
+	}
+
+	switch {
+	case node.Kind == yaml.DocumentNode && len(node.Content) > 0:
+		return locateYAMLError(node.Content[0], _type, path)
+
+	case node.Kind == yaml.AliasNode && node.Alias != nil:
+		return locateYAMLError(node.Alias, _type, path)
+
+	case node.Kind == yaml.MappingNode && elemType.Kind() == reflect.Struct &&
+		!registry.TypeIsDecodable(reflect.PointerTo(elemType)):
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			field, found := yamlField(elemType, node.Content[i].Value)
+			if !found {
+				continue
+			}
+
+			if n, t, p := locateYAMLError(
+				node.Content[i+1],
+				field.Type,
+				path+"."+field.Name,
+			); n != nil {
+				return n, t, p
+			}
+		}
+
+	case node.Kind == yaml.MappingNode && elemType.Kind() == reflect.Map:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if n, t, p := locateYAMLError(
+				node.Content[i+1],
+				elemType.Elem(),
+				path+"["+node.Content[i].Value+"]",
+			); n != nil {
+				return n, t, p
+			}
+		}
+
+	case node.Kind == yaml.SequenceNode &&
+		(elemType.Kind() == reflect.Slice || elemType.Kind() == reflect.Array):
+		for i, item := range node.Content {
+			if n, t, p := locateYAMLError(
+				item,
+				elemType.Elem(),
+				path+"["+strconv.Itoa(i)+"]",
+			); n != nil {
+				return n, t, p
+			}
+		}
+	}
+
+	return node, _type, path
+}
+
+// yamlField returns the field of the struct type _type decoded from the
+// YAML key, named by its yaml tag or its lower cased name.
+func yamlField(_type reflect.Type, key string) (reflect.StructField, bool) {
+	for i := 0; i < _type.NumField(); i++ {
+		field := _type.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+
+		if name == key {
+			return field, true
+		}
+	}
+
+	return reflect.StructField{}, false
+}
+
+// expectedHint returns the hint of a value described by got that can't be
+// parsed as _type.
+func expectedHint(_type reflect.Type, got string) string {
+	return fmt.Sprintf("expected %s, got %s", typeDescription(_type), got)
+}
+
+// typeDescription returns the human description of the values of _type.
+func typeDescription(_type reflect.Type) string {
+	for _type.Kind() == reflect.Pointer {
+		_type = _type.Elem()
+	}
+
+	switch {
+	case _type == durationType:
+		return "duration"
+	case _type == timeType:
+		return "time"
+	case registry.TypeIsDecodable(reflect.PointerTo(_type)) &&
+		_type.PkgPath() != "":
+		return _type.String()
+	}
+
+	switch _type.Kind() { //nolint:exhaustive // other kinds use the type name
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Int64:
+		return "integer"
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64:
+		return "unsigned integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.String:
+		return "string"
+	case reflect.Slice, reflect.Array:
+		return "list"
+	case reflect.Map, reflect.Struct:
+		return "mapping"
+	default:
+		return _type.String()
+	}
+}
+
+// nodeDescription returns the human description of a YAML node value.
+func nodeDescription(node *yaml.Node) string {
+	switch node.Kind { //nolint:exhaustive // other kinds are quoted
+	case yaml.MappingNode:
+		return "a mapping"
+	case yaml.SequenceNode:
+		return "a list"
+	default:
+		return "'" + node.Value + "'"
+	}
+}
@@ -0,0 +1,213 @@
+package dsco
+
+import (
+	"net/netip"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/byte4ever/dsco/svalue"
+)
+
+func TestParseError_Error_position(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(
+		t,
+		"parse error on P-<int> loc at line 2, column 4: expected integer, "+
+			"got 'x'",
+		ParseError{
+			Path:     "P",
+			Type:     reflect.TypeOf(0),
+			Location: "loc",
+			Hint:     "expected integer, got 'x'",
+			Line:     2,
+			Column:   4,
+		}.Error(),
+	)
+	require.Equal(
+		t,
+		"parse error on P-<int> loc at line 2: did not find expected key",
+		ParseError{
+			Path:     "P",
+			Type:     reflect.TypeOf(0),
+			Location: "loc",
+			Hint:     "did not find expected key",
+			Line:     2,
+		}.Error(),
+	)
+}
+
+func Test_typeDescription(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range []struct {
+		value any
+		want  string
+	}{
+		{R(0), "integer"},
+		{R(uint8(0)), "unsigned integer"},
+		{R(1.5), "number"},
+		{R(true), "boolean"},
+		{R(""), "string"},
+		{R(time.Second), "duration"},
+		{&time.Time{}, "time"},
+		{&netip.Prefix{}, "netip.Prefix"},
+		{[]string{}, "list"},
+		{map[string]string{}, "mapping"},
+		{&struct{ A *int }{}, "mapping"},
+		{R(make(chan int, 1)), "chan int"},
+	} {
+		require.Equal(t, tt.want, typeDescription(reflect.TypeOf(tt.value)))
+	}
+}
+
+func TestStringBasedBuilder_parseErrors(t *testing.T) {
+	t.Parallel()
+
+	type Backend struct {
+		URL     *string        `yaml:"url"`
+		Timeout *time.Duration `yaml:"timeout"`
+	}
+
+	type Root struct {
+		Backends map[string]*Backend
+		Weights  map[string]*int
+		Ports    []int
+		Password *string `dsco:"secret"`
+		Secrets  *struct {
+			Token *int
+		} `dsco:"secret"`
+	}
+
+	mdl, err := buildModel(&Root{})
+	require.NoError(t, err)
+
+	t.Run(
+		"blob", func(t *testing.T) {
+			t.Parallel()
+
+			sb := StringBasedBuilder{
+				values: svalue.Values{
+					"backends": {
+						Location: "env[APP-BACKENDS]",
+						Value:    "eu:\n  url: u\n  timeout: soon\n",
+					},
+				},
+			}
+
+			_, err := sb.GetFieldValuesFrom(mdl)
+			require.ErrorContains(
+				t,
+				err,
+				"parse error on Backends[eu]-"+
+					"<*github.com/byte4ever/dsco/dsco.Backend> "+
+					"env[APP-BACKENDS] at line 3, column 12: "+
+					"expected duration, got 'soon'",
+			)
+		},
+	)
+
+	t.Run(
+		"blob entry", func(t *testing.T) {
+			t.Parallel()
+
+			sb := StringBasedBuilder{
+				values: svalue.Values{
+					"weights": {
+						Location: "env[APP-WEIGHTS]",
+						Value:    "{eu: 1, us: heavy}",
+					},
+				},
+			}
+
+			_, err := sb.GetFieldValuesFrom(mdl)
+			require.ErrorContains(
+				t,
+				err,
+				"parse error on Weights[us]-<*int> env[APP-WEIGHTS] "+
+					"at line 1, column 13: expected integer, got 'heavy'",
+			)
+		},
+	)
+
+	t.Run(
+		"list item", func(t *testing.T) {
+			t.Parallel()
+
+			sb := StringBasedBuilder{
+				values: svalue.Values{
+					"ports": {
+						Location: "cmdline[--ports]",
+						Value:    "[80, eighty]",
+					},
+				},
+			}
+
+			_, err := sb.GetFieldValuesFrom(mdl)
+
+			var parseError ParseError
+
+			require.ErrorAs(t, err, &parseError)
+			require.Equal(t, "eighty", parseError.Value)
+			require.Equal(t, "expected integer, got 'eighty'", parseError.Hint)
+			require.Equal(t, 1, parseError.Line)
+			require.Equal(t, 6, parseError.Column)
+		},
+	)
+
+	t.Run(
+		"syntax", func(t *testing.T) {
+			t.Parallel()
+
+			sb := StringBasedBuilder{}
+
+			parseError := sb.blobParseError(
+				"Ports",
+				reflect.TypeOf([]int{}),
+				"loc",
+				"[1, 2\n3",
+				nil,
+			)
+			require.Error(t, parseError.Err)
+			require.Equal(t, 2, parseError.Line)
+			require.Zero(t, parseError.Column)
+			require.NotContains(t, parseError.Hint, "yaml:")
+		},
+	)
+
+	t.Run(
+		"secret", func(t *testing.T) {
+			t.Parallel()
+
+			sb := StringBasedBuilder{model: mdl}
+
+			parseError := sb.valueParseError(
+				"Password",
+				reflect.TypeOf(R("")),
+				"loc",
+				"hunter2",
+				errMocked1,
+				false,
+			)
+			require.ErrorIs(t, parseError, errSecretReason)
+			require.Equal(t, SecretMask, parseError.Value)
+			require.NotContains(t, parseError.Error(), "hunter2")
+
+			parseError = sb.blobParseError(
+				"Secrets",
+				reflect.TypeOf(&struct{ Token *int }{}),
+				"loc",
+				"token: hunter2",
+				errMocked1,
+			)
+			require.ErrorIs(t, parseError, errSecretReason)
+			require.Equal(t, SecretMask, parseError.Value)
+			require.Equal(t, 1, parseError.Line)
+			require.Equal(t, 8, parseError.Column)
+			require.NotContains(t, parseError.Error(), "hunter2")
+		},
+	)
+}
@@ -0,0 +1,79 @@
+package registry
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// DecodeFunc decodes the text of a string value into the value pointed by
+// target, a pointer of the type it is registered for.
+type DecodeFunc func(text string, target reflect.Value) error
+
+var nameToDecoder sync.Map //nolint:gochecknoglobals // required for registration
+
+//nolint:gochecknoglobals // immutable
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+// RegisterDecoder registers the type T, when not registered yet, and decode
+// as the decoder of its string values, replacing YAML unmarshalling. A
+// second decoder registration for the same type panics.
+func RegisterDecoder[T any](decode func(text string) (T, error)) {
+	valueType := reflect.TypeOf((*T)(nil))
+	longName := LongTypeName(valueType)
+
+	decoder := DecodeFunc(
+		func(text string, target reflect.Value) error {
+			value, err := decode(text)
+			if err != nil {
+				return err
+			}
+
+			target.Elem().Set(reflect.ValueOf(&value).Elem())
+
+			return nil
+		},
+	)
+
+	if _, dup := nameToDecoder.LoadOrStore(longName, decoder); dup {
+		panic(
+			fmt.Sprintf(
+				"dsco: %q duplicate decoder registration",
+				longName,
+			),
+		)
+	}
+
+	nameToType.LoadOrStore(longName, valueType)
+}
+
+// DecoderFor returns the decoder registered for the pointer type t.
+func DecoderFor(t reflect.Type) (DecodeFunc, bool) {
+	decoder, found := nameToDecoder.Load(LongTypeName(t))
+	if !found {
+		return nil, false
+	}
+
+	return decoder.(DecodeFunc), true //nolint:forcetypeassert // always a DecodeFunc
+}
+
+// TypeIsDecodable returns true when the string values of the pointer type t
+// can be decoded: t is registered or implements encoding.TextUnmarshaler.
+func TypeIsDecodable(t reflect.Type) bool {
+	return TypeIsRegistered(t) ||
+		(t.Kind() == reflect.Pointer && t.Implements(textUnmarshalerType))
+}
+
+// TextUnmarshaler returns the encoding.TextUnmarshaler implementation of
+// target, a pointer, when its type is not registered: the values of
+// registered types are YAML unmarshalled.
+func TextUnmarshaler(target reflect.Value) (encoding.TextUnmarshaler, bool) {
+	if TypeIsRegistered(target.Type()) {
+		return nil, false
+	}
+
+	u, ok := target.Interface().(encoding.TextUnmarshaler)
+
+	return u, ok
+}
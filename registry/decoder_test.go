@@ -0,0 +1,80 @@
+package registry
+
+import (
+	"errors"
+	"net/netip"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type upper string
+
+type notDecodable struct{}
+
+var errEmpty = errors.New("empty")
+
+func TestRegisterDecoder(t *testing.T) {
+	t.Parallel()
+
+	decode := func(text string) (upper, error) {
+		if text == "" {
+			return "", errEmpty
+		}
+
+		return upper(strings.ToUpper(text)), nil
+	}
+
+	pointerType := reflect.TypeOf((*upper)(nil))
+
+	_, found := DecoderFor(pointerType)
+	require.False(t, found)
+	require.False(t, TypeIsRegistered(pointerType))
+
+	RegisterDecoder(decode)
+
+	require.True(t, TypeIsRegistered(pointerType))
+	require.True(t, TypeIsDecodable(pointerType))
+
+	decoder, found := DecoderFor(pointerType)
+	require.True(t, found)
+
+	target := reflect.New(pointerType.Elem())
+	require.NoError(t, decoder("abc", target))
+	require.Equal(t, upper("ABC"), target.Elem().Interface())
+	require.ErrorIs(t, decoder("", target), errEmpty)
+
+	// duplicated registration MUST panic
+	require.Panics(
+		t, func() {
+			RegisterDecoder(decode)
+		},
+	)
+}
+
+func TestTypeIsDecodable(t *testing.T) {
+	t.Parallel()
+
+	require.True(t, TypeIsDecodable(reflect.TypeOf(&time.Time{})))
+	require.True(t, TypeIsDecodable(reflect.TypeOf(&netip.Prefix{})))
+	require.False(t, TypeIsDecodable(reflect.TypeOf(netip.Prefix{})))
+	require.False(t, TypeIsDecodable(reflect.TypeOf(&notDecodable{})))
+}
+
+func TestTextUnmarshaler(t *testing.T) {
+	t.Parallel()
+
+	u, found := TextUnmarshaler(reflect.ValueOf(&netip.Prefix{}))
+	require.True(t, found)
+	require.NoError(t, u.UnmarshalText([]byte("10.0.0.0/8")))
+
+	// registered types are YAML unmarshalled
+	_, found = TextUnmarshaler(reflect.ValueOf(&time.Time{}))
+	require.False(t, found)
+
+	_, found = TextUnmarshaler(reflect.ValueOf(&notDecodable{}))
+	require.False(t, found)
+}
@@ -53,6 +53,21 @@ This format is ideal for:
 - CLI output where brevity is important
 - API responses that include type information
 
+# Decoders
+
+Register makes a type a leaf of configuration models, its string values
+being YAML unmarshalled. RegisterDecoder registers a type with its own
+string decoder instead:
+
+	registry.RegisterDecoder(func(text string) (ByteSize, error) {
+		return parseByteSize(text)
+	})
+
+DecoderFor returns the decoder of a pointer type. Types implementing
+encoding.TextUnmarshaler are leaves without registration (TypeIsDecodable)
+and TextUnmarshaler returns their implementation, used to decode their
+values.
+
 # Type Support
 
 The package handles all Go type categories:
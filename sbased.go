@@ -3,8 +3,12 @@ package dsco
 import (
 	"errors"
 	"fmt"
+	"maps"
+	"os"
 	"reflect"
 	"sort"
+	"strconv"
+	"strings"
 
 	"gopkg.in/yaml.v3"
 
@@ -19,28 +23,6 @@ import (
 // ErrInvalidType represent an error where ....
 var ErrInvalidType = errors.New("invalid type")
 
-// ErrParse represents an error indicating that a value cannot be parsed.
-var ErrParse = errors.New("parse error")
-
-type ParseError struct {
-	Path     string
-	Type     reflect.Type
-	Location string
-}
-
-func (a ParseError) Error() string {
-	return fmt.Sprintf(
-		"parse error on %s-<%s> %s",
-		a.Path,
-		registry.LongTypeName(a.Type),
-		a.Location,
-	)
-}
-
-func (ParseError) Is(err error) bool {
-	return errors.Is(err, ErrParse)
-}
-
 // ErrAliasCollision represents an error indicating that an alias is colliding
 // with an actual key in the structure.
 var ErrAliasCollision = errors.New("alias collision")
@@ -78,10 +60,14 @@ func (u UnboundedLocationErrors) Swap(i, j int) {
 
 type UnboundedLocationError struct {
 	Location string
+
+	// Suggestions holds the closest keys of the model, formatted like the
+	// layer keys (i.e. "APP-DATABASE-HOST"), nil when none is close.
+	Suggestions []string
 }
 
 func (a UnboundedLocationError) Error() string {
-	return "unbounded location " + a.Location
+	return "unbounded location " + a.Location + didYouMean(a.Suggestions)
 }
 
 func (UnboundedLocationError) Is(err error) bool {
@@ -111,6 +97,9 @@ func (OverriddenKeyError) Is(err error) bool {
 	return errors.Is(err, ErrOverriddenKey)
 }
 
+// yamlNullTag is the tag of YAML null values.
+const yamlNullTag = "!!null"
+
 // ErrNilProvider is shitty...
 var ErrNilProvider = errors.New("nil provider")
 
@@ -120,6 +109,41 @@ type StringBasedBuilder struct {
 	values         svalue.Values
 	expandedValues map[string]*fvalue.Value
 	keyFormatter   KeyFormatter
+
+	// ignoreUnbounded drops keys that are not bound to the model instead
+	// of reporting them as UnboundedLocationError.
+	ignoreUnbounded bool
+
+	// raw holds every provided value, consumed or not, for references.
+	raw svalue.Values
+
+	// references resolves the references of values when interpolating.
+	references *referenceResolver
+
+	// helpRequested is true when the command line asks for the usage text.
+	helpRequested bool
+
+	// scope restricts a command line layer to the arguments of a command.
+	scope *cmdlineScope
+
+	// taggedKeys holds the user facing keys set by key struct tags by
+	// field path.
+	taggedKeys map[string]string
+
+	// secret marks every value of the layer as secret.
+	secret bool
+
+	// stale is the fetch error of a remote source whose values come from
+	// its cache.
+	stale error
+
+	// model is the model values are got for, nil until
+	// GetFieldValuesFrom is called.
+	model ModelInterface
+
+	// nodes holds the YAML nodes of the entries spread from collection
+	// blobs by key, to locate parse errors in the blobs.
+	nodes map[string]*yaml.Node
 }
 
 // ErrNoAliasesProvided represent an error where no aliases map was
@@ -133,6 +157,15 @@ type Option interface {
 
 type internalOpts struct {
 	aliases map[string]string
+
+	// shadowed keys are never bound, as their field is bound to the key
+	// set by its key struct tag.
+	shadowed    map[string]struct{}
+	interpolate bool
+
+	// environ returns the entries ${env:NAME} references are read from,
+	// the process environment when nil.
+	environ func() []string
 }
 
 // AliasesOption defines keys aliasing.
@@ -187,10 +220,12 @@ func NewStringBasedBuilder(
 
 	values := provider.GetStringValues()
 
-	if len(internalOptions.aliases) == 0 {
+	if len(internalOptions.aliases) == 0 && len(internalOptions.shadowed) == 0 {
+		// values are consumed, the provider ones are left untouched
 		return &StringBasedBuilder{
 			internalOpts:   internalOptions,
-			values:         values,
+			values:         maps.Clone(values),
+			raw:            maps.Clone(values),
 			expandedValues: make(map[string]*fvalue.Value),
 		}, nil
 	}
@@ -203,16 +238,32 @@ func NewStringBasedBuilder(
 			continue
 		}
 
+		if _, found := internalOptions.shadowed[n]; found {
+			// kept to be reported as unbounded
+			converted[shadowedKeyPrefix+n] = value
+			continue
+		}
+
 		converted[n] = value
 	}
 
 	return &StringBasedBuilder{
 		internalOpts:   internalOptions,
 		values:         converted,
+		raw:            maps.Clone(converted),
 		expandedValues: make(map[string]*fvalue.Value),
 	}, nil
 }
 
+// dropShadowed drops the values of the shadowed keys, so they are not
+// reported as unbounded.
+func (s *StringBasedBuilder) dropShadowed() {
+	for key := range s.shadowed {
+		delete(s.values, shadowedKeyPrefix+key)
+		delete(s.raw, shadowedKeyPrefix+key)
+	}
+}
+
 // newStringBasedBuilderWithFormatter is an internal constructor that
 // behaves like NewStringBasedBuilder but records the KeyFormatter used to
 // render the layer's keys in inventory reports.
@@ -252,17 +303,26 @@ func (s *StringBasedBuilder) ExpandStruct(
 
 	delete(s.values, convertedPath)
 
+	text, references, err := s.interpolateValue(path, entryToExpand)
+	if err != nil {
+		return err
+	}
+
 	tp := reflect.New(_type.Elem())
 
 	// parse yaml struct
 	if err = yaml.Unmarshal(
-		[]byte(entryToExpand.Value), tp.Interface(),
+		[]byte(text), tp.Interface(),
 	); err != nil {
-		return &ParseError{
+		parseError := s.blobParseError(
 			path,
 			_type,
 			entryToExpand.Location,
-		}
+			text,
+			err,
+		)
+
+		return &parseError
 	}
 
 	extractedModel, err := model.NewModel(_type)
@@ -274,6 +334,7 @@ func (s *StringBasedBuilder) ExpandStruct(
 		entryToExpand.Location,
 		tp,
 	) {
+		value.References = references
 		s.expandedValues[path+"."+value.Path] = value
 	}
 
@@ -307,45 +368,78 @@ func (s *StringBasedBuilder) Get(
 		return nil, nil //nolint:nilnil // required when nothing is found
 	}
 
-	switch _type.Kind() { //nolint:exhaustive // it's expected
-	case reflect.Pointer:
+	switch {
+	case _type.Kind() == reflect.Map || model.IsIndexedSlice(_type):
+		delete(s.values, convertedPath)
+
+		return s.spreadEntries(path, convertedPath, _type, entry)
+
+	case _type.Kind() == reflect.Pointer:
 		tp := reflect.New(_type.Elem())
 
 		delete(s.values, convertedPath)
 
-		if err := yaml.Unmarshal(
-			[]byte(entry.Value), tp.Interface(),
-		); err != nil {
-			return nil, ParseError{
+		text, references, err := s.interpolateValue(path, entry)
+		if err != nil {
+			return nil, err
+		}
+
+		if viaYAML, err := decodeValue(text, tp); err != nil {
+			return nil, s.valueParseError(
 				path,
 				_type,
 				entry.Location,
-			}
+				text,
+				err,
+				viaYAML,
+			)
 		}
 
 		return &fvalue.Value{
-			Value:    tp,
-			Location: entry.Location,
+			Value:      tp,
+			Location:   entry.Location,
+			Secret:     s.secret,
+			References: references,
 		}, nil
 
-	case reflect.Slice:
+	case _type.Kind() == reflect.Slice ||
+		registry.TypeIsDecodable(reflect.PointerTo(_type)):
 		tp := reflect.New(_type)
 
 		delete(s.values, convertedPath)
 
-		if err := yaml.Unmarshal(
-			[]byte(entry.Value), tp.Interface(),
-		); err != nil {
-			return nil, ParseError{
+		text, references, err := s.interpolateValue(path, entry)
+		if err != nil {
+			return nil, err
+		}
+
+		if viaYAML, err := decodeValue(text, tp); err != nil {
+			if viaYAML && _type.Kind() == reflect.Slice {
+				// points at the offending item rather than the whole list
+				return nil, s.blobParseError(
+					path,
+					_type,
+					entry.Location,
+					text,
+					err,
+				)
+			}
+
+			return nil, s.valueParseError(
 				path,
 				_type,
 				entry.Location,
-			}
+				text,
+				err,
+				viaYAML,
+			)
 		}
 
 		return &fvalue.Value{
-			Value:    tp.Elem(),
-			Location: entry.Location,
+			Value:      tp.Elem(),
+			Location:   entry.Location,
+			Secret:     s.secret,
+			References: references,
 		}, nil
 
 	default:
@@ -357,6 +451,215 @@ func (s *StringBasedBuilder) Get(
 	}
 }
 
+// isSecret returns true when the value of the field located at path must be
+// redacted: the layer is secret, or the field, or the collection element
+// field, is secret.
+func (s *StringBasedBuilder) isSecret(path string) bool {
+	if s.secret {
+		return true
+	}
+
+	return s.model != nil && s.model.IsSecret(path)
+}
+
+// interpolateValue returns the text of entry, the raw value of the field
+// located at path, with its references expanded when the layer
+// interpolates. Without a resolver set by Fill, field references are
+// resolved against the layer values only.
+func (s *StringBasedBuilder) interpolateValue(
+	path string,
+	entry *svalue.Value,
+) (string, []string, error) {
+	if !s.interpolate {
+		return entry.Value, nil, nil
+	}
+
+	if s.references == nil {
+		s.references = &referenceResolver{
+			layers: []FieldValuesGetter{s},
+		}
+	}
+
+	return s.references.interpolate(path, entry, s.lookupEnv)
+}
+
+// lookupEnv returns the value of the variable name of the environment the
+// references of the layer are read from.
+func (s *StringBasedBuilder) lookupEnv(name string) (string, bool) {
+	if s.environ == nil {
+		return os.LookupEnv(name)
+	}
+
+	for _, entry := range s.environ() {
+		if key, value, found := strings.Cut(entry, "="); found && key == name {
+			return value, true
+		}
+	}
+
+	return "", false
+}
+
+// spreadEntries splits a whole collection value (a YAML mapping for maps,
+// a YAML sequence for slices) into one value per entry, so that entries are
+// processed like individually provided ones. Individually provided entries
+// take precedence.
+func (s *StringBasedBuilder) spreadEntries(
+	path, convertedPath string,
+	_type reflect.Type,
+	entry *svalue.Value,
+) (*fvalue.Value, error) {
+	var doc yaml.Node
+
+	if err := yaml.Unmarshal([]byte(entry.Value), &doc); err != nil {
+		return nil, s.blobParseError(
+			path,
+			_type,
+			entry.Location,
+			entry.Value,
+			err,
+		)
+	}
+
+	entries := make(map[string]*yaml.Node)
+
+	if len(doc.Content) > 0 {
+		root := doc.Content[0]
+
+		switch {
+		case root.Kind == yaml.ScalarNode && root.Tag == yamlNullTag:
+		case _type.Kind() == reflect.Map && root.Kind == yaml.MappingNode:
+			for i := 0; i+1 < len(root.Content); i += 2 {
+				entries[root.Content[i].Value] = root.Content[i+1]
+			}
+		case _type.Kind() == reflect.Slice && root.Kind == yaml.SequenceNode:
+			for i, node := range root.Content {
+				entries[strconv.Itoa(i)] = node
+			}
+		default:
+			return nil, s.nodeParseError(
+				path,
+				_type,
+				entry.Location,
+				root,
+				_type,
+				path,
+				nil,
+			)
+		}
+	}
+
+	for key, node := range entries {
+		if node.Tag == yamlNullTag {
+			continue
+		}
+
+		entryKey := convertedPath + "-" + key
+		if _, found := s.values[entryKey]; found {
+			continue
+		}
+
+		// keep the node to locate parse errors in the whole blob
+		if s.nodes == nil {
+			s.nodes = make(map[string]*yaml.Node)
+		}
+
+		s.nodes[entryKey] = node
+
+		raw := node.Value
+		if node.Kind != yaml.ScalarNode {
+			out, err := yaml.Marshal(node)
+			if err != nil {
+				return nil, ParseError{
+					Err:      err,
+					Path:     path,
+					Type:     _type,
+					Location: entry.Location,
+				}
+			}
+
+			raw = string(out)
+		}
+
+		s.values[entryKey] = &svalue.Value{
+			Location: entry.Location,
+			Value:    raw,
+		}
+	}
+
+	return &fvalue.Value{
+		Location: entry.Location,
+	}, nil
+}
+
+// GetEntryKeys implements internal.EntriesGetter. Keys are found by
+// removing the map prefix and the longest element relative path from the
+// available keys, so entry keys may contain dashes.
+func (s *StringBasedBuilder) GetEntryKeys(
+	path string,
+	leaves, prefixes []string,
+) []string {
+	mapPrefix := convert(path) + "-"
+
+	leafSet := make(map[string]struct{}, len(leaves))
+	for _, leaf := range leaves {
+		leafSet[convert(leaf)] = struct{}{}
+	}
+
+	convertedPrefixes := make([]string, 0, len(prefixes))
+	for _, prefix := range prefixes {
+		convertedPrefixes = append(convertedPrefixes, convert(prefix))
+	}
+
+	dedup := make(map[string]struct{})
+
+	for name := range s.values {
+		rest, found := strings.CutPrefix(name, mapPrefix)
+		if !found || rest == "" {
+			continue
+		}
+
+		dedup[entryKey(rest, leafSet, convertedPrefixes)] = struct{}{}
+	}
+
+	keys := make([]string, 0, len(dedup))
+	for key := range dedup {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+// entryKey returns the entry key of rest, the shortest dash separated
+// prefix followed by an element relative path. The whole rest is the key
+// when no element path matches.
+func entryKey(
+	rest string,
+	leaves map[string]struct{},
+	prefixes []string,
+) string {
+	for i := 1; i < len(rest)-1; i++ {
+		if rest[i] != '-' {
+			continue
+		}
+
+		tail := rest[i+1:]
+
+		if _, found := leaves[tail]; found {
+			return rest[:i]
+		}
+
+		for _, prefix := range prefixes {
+			if prefix == "" || strings.HasPrefix(tail, prefix+"-") {
+				return rest[:i]
+			}
+		}
+	}
+
+	return rest
+}
+
 // ErrNilKeyFormatter indicates that ReportInventory was called on a
 // StringBasedBuilder with no KeyFormatter set.
 var ErrNilKeyFormatter = errors.New("nil key formatter")
@@ -387,10 +690,18 @@ func (s *StringBasedBuilder) ReportInventory(
 	}
 
 	provides := make([]FieldProvision, 0, len(aliases))
-	for fieldUID, aliasPath := range aliases {
+	for fieldUID := range aliases {
+		key := s.formatKey(fieldUID)
+
+		// fields without key can't be provided by the layer (i.e. the ones
+		// mapped to no secret)
+		if key == "" && s.keyFormatter.LayerKind() != "" {
+			continue
+		}
+
 		provides = append(provides, FieldProvision{
 			FieldUID: fieldUID,
-			Key:      s.keyFormatter.FormatKey(aliasPath),
+			Key:      key,
 		})
 	}
 
@@ -410,6 +721,16 @@ func (s *StringBasedBuilder) ReportInventory(
 	return inv, nil
 }
 
+// formatKey returns the user facing key of the field located at path, the
+// one set by its key struct tag or the one derived from the path.
+func (s *StringBasedBuilder) formatKey(path string) string {
+	if key, found := s.taggedKeys[path]; found {
+		return key
+	}
+
+	return s.keyFormatter.FormatKey(convert(path))
+}
+
 // aliasRecorder implements internal.ValueGetter to capture each leaf
 // field's (FieldUID, alias-path) without producing any value.
 type aliasRecorder struct {
@@ -452,9 +773,10 @@ func collectAliases(mdl ModelInterface) (map[string]string, error) {
 // exercise ReportInventory without going through the layer wrappers in
 // builders.go.
 //
-// kind must be one of "env", "cmdline", "file", or "" (nil formatter
-// for custom-provider behaviour). For "env" / "file", metaOrPrefix is
-// the prefix or file id.
+// kind must be one of "env", "cmdline", "file", "kfile", "http", or ""
+// (nil formatter for custom-provider behaviour). For "env" / "file" /
+// "kfile" / "http", metaOrPrefix is the prefix, the file path, the kfile
+// directory or the url.
 func NewStringBasedBuilderForTest(
 	provider StringValuesProvider,
 	kind, metaOrPrefix string,
@@ -466,6 +788,12 @@ func NewStringBasedBuilderForTest(
 		kf = newEnvKeyFormatter(metaOrPrefix)
 	case "cmdline":
 		kf = newCmdlineKeyFormatter()
+	case "file":
+		kf = newFileKeyFormatter(metaOrPrefix)
+	case "kfile":
+		kf = newKFileKeyFormatter(metaOrPrefix)
+	case "http":
+		kf = newHTTPKeyFormatter(metaOrPrefix)
 	case "":
 		kf = newNilKeyFormatter(metaOrPrefix)
 	default:
@@ -496,6 +824,8 @@ func (s *StringBasedBuilder) GetFieldValuesFrom(
 ) {
 	var errs GetError
 
+	s.model = _model
+
 	if err := _model.Expand(s); err != nil {
 		errs.Add(err)
 	}
@@ -505,37 +835,88 @@ func (s *StringBasedBuilder) GetFieldValuesFrom(
 		errs.Add(e)
 	}
 
+	if !s.ignoreUnbounded {
+		for _, e2 := range s.unboundedLocationErrors() {
+			errs.Add(e2)
+		}
+	}
+
+	if errs.None() {
+		return result, nil
+	}
+
+	return nil, errs
+}
+
+// unboundedLocationErrors returns the sorted list of values that were not
+// consumed by the model, with the closest keys of the model as suggestions.
+func (s *StringBasedBuilder) unboundedLocationErrors() UnboundedLocationErrors {
 	var e2s UnboundedLocationErrors
 
-	for _, v := range s.values {
+	suggest := s.keySuggester()
+
+	for k, v := range s.values {
 		e2s = append(
 			e2s,
 			UnboundedLocationError{
-				Location: v.Location,
+				Location:    v.Location,
+				Suggestions: suggest(k),
 			},
 		)
 	}
 
-	for _, v := range s.expandedValues {
+	for k, v := range s.expandedValues {
 		e2s = append(
 			e2s,
 			UnboundedLocationError{
-				Location: v.Location,
+				Location:    v.Location,
+				Suggestions: suggest(k),
 			},
 		)
 	}
 
-	if len(e2s) > 0 {
-		sort.Sort(e2s)
+	sort.Sort(e2s)
 
-		for _, e2 := range e2s {
-			errs.Add(e2)
+	return e2s
+}
+
+// keySuggester returns the function giving the closest user facing keys of
+// the model to the alias of an unbounded value. It suggests nothing when
+// the keys of the layer can't be enumerated (custom providers) or when there
+// is no value to suggest a key for.
+func (s *StringBasedBuilder) keySuggester() func(alias string) []string {
+	noSuggestion := func(string) []string { return nil }
+
+	if s.keyFormatter == nil || s.keyFormatter.LayerKind() == "" ||
+		s.model == nil || len(s.values)+len(s.expandedValues) == 0 {
+		return noSuggestion
+	}
+
+	aliases, err := collectAliases(s.model)
+	if err != nil {
+		return noSuggestion
+	}
+
+	keys := make([]string, 0, len(aliases))
+
+	for path := range aliases {
+		// fields without key can't be provided by the layer
+		if key := s.formatKey(path); key != "" {
+			keys = append(keys, suggestedKey(key))
 		}
 	}
 
-	if errs.None() {
-		return result, nil
+	return func(alias string) []string {
+		return closestKeys(
+			suggestedKey(s.keyFormatter.FormatKey(alias)),
+			alias,
+			keys,
+		)
 	}
+}
 
-	return nil, errs
+// suggestedKey returns key as suggested to the user, without the value
+// separator ending cmdline keys.
+func suggestedKey(key string) string {
+	return strings.TrimSuffix(key, "=")
 }
@@ -230,15 +230,17 @@ s3:
 				"loc1",
 				gotFv.Location,
 			)
-			require.IsType(
+
+			// slices of structs are spread into indexed entries
+			require.Equal(
 				t,
-				[]*ComplexItem{
-					{
-						A: R(102),
-						B: R(3.14),
+				svalue.Values{
+					"some-path-0": {
+						Location: "loc1",
+						Value:    "{a: 123, 'b:123.32': ''}\n",
 					},
 				},
-				gotFv.Value.Interface(),
+				sb.values,
 			)
 		},
 	)
@@ -275,11 +277,15 @@ s3:
 				err,
 				&e,
 			)
+			require.ErrorContains(t, e.Err, "cannot unmarshal")
 			require.Equal(
 				t, ParseError{
+					Err:      e.Err,
 					Path:     "Some.Path",
 					Type:     vType,
 					Location: "loc1",
+					Value:    "asd",
+					Hint:     "expected integer, got 'asd'",
 				}, e,
 			)
 		},
@@ -317,11 +323,17 @@ s3:
 				err,
 				&e,
 			)
+			require.ErrorContains(t, e.Err, "cannot unmarshal")
 			require.Equal(
 				t, ParseError{
+					Err:      e.Err,
 					Path:     "Some.Path",
 					Type:     vType,
 					Location: "loc1",
+					Value:    "asd",
+					Hint:     "expected list, got 'asd'",
+					Line:     1,
+					Column:   1,
 				}, e,
 			)
 		},
@@ -340,7 +352,7 @@ s3:
 				},
 			}
 
-			var v int
+			var v complex64
 			pv := v
 			vType := reflect.TypeOf(pv)
 
@@ -712,6 +724,76 @@ func TestUnboundedLocationError_Error(t *testing.T) {
 			Location: "loc-a",
 		}.Error(),
 	)
+	require.Equal(
+		t,
+		"unbounded location env[APP-DATABSE-HOST], did you mean "+
+			"APP-DATABASE-HOST?",
+		UnboundedLocationError{
+			Location:    "env[APP-DATABSE-HOST]",
+			Suggestions: []string{"APP-DATABASE-HOST"},
+		}.Error(),
+	)
+}
+
+func TestStringBasedBuilder_GetFieldValuesFrom_suggestions(t *testing.T) {
+	t.Parallel()
+
+	type Root struct {
+		Database *struct {
+			Host *string
+			Port *int
+		}
+	}
+
+	var cfg *Root
+
+	_, err := Fill(
+		&cfg,
+		WithStrictEnvLayer(
+			"APP",
+			WithEnviron(
+				map[string]string{
+					"APP-DATABSE-HOST":  "localhost",
+					"APP-DATABASE-PORT": "5432",
+					"APP-TIMEOUT":       "1s",
+				},
+			),
+		),
+	)
+
+	var unboundErr UnboundedLocationError
+
+	require.ErrorAs(t, err, &unboundErr)
+	require.ErrorContains(
+		t,
+		err,
+		"unbounded location env[APP-DATABSE-HOST], did you mean "+
+			"APP-DATABASE-HOST?",
+	)
+	require.ErrorContains(t, err, "unbounded location env[APP-TIMEOUT]")
+	require.NotContains(t, err.Error(), "env[APP-TIMEOUT], did you mean")
+
+	_, err = Fill(
+		&cfg,
+		WithStrictCmdlineLayer(
+			WithArgs(
+				"--database-hots=localhost",
+				"--database-prot=5432",
+			),
+		),
+	)
+	require.ErrorContains(
+		t,
+		err,
+		"unbounded location cmdline[--database-hots], did you mean "+
+			"--database-host?",
+	)
+	require.ErrorContains(
+		t,
+		err,
+		"unbounded location cmdline[--database-prot], did you mean "+
+			"--database-port?",
+	)
 }
 
 func TestUnboundedLocationError_Is(t *testing.T) {
@@ -758,6 +840,25 @@ func TestParseError_Error(t *testing.T) {
 	)
 }
 
+func TestParseError_Unwrap(t *testing.T) {
+	t.Parallel()
+
+	err := ParseError{
+		Err:      errMocked1,
+		Path:     "some-path",
+		Type:     reflect.TypeOf(10),
+		Location: "loc-a",
+	}
+
+	require.ErrorIs(t, err, errMocked1)
+	require.ErrorIs(t, err, ErrParse)
+	require.Equal(
+		t,
+		"parse error on some-path-<int> loc-a: "+errMocked1.Error(),
+		err.Error(),
+	)
+}
+
 func TestAliasCollisionError_Is(t *testing.T) {
 	t.Parallel()
 
@@ -1217,6 +1318,16 @@ c:
 				parseErr.Type,
 			)
 			require.Empty(t, parseErr.Location)
+			require.Equal(t, "bad-int", parseErr.Value)
+			require.Equal(t, "expected integer, got 'bad-int'", parseErr.Hint)
+			require.Equal(t, 5, parseErr.Line)
+			require.Equal(t, 6, parseErr.Column)
+			require.Error(t, errors.Unwrap(parseErr))
+			require.Contains(
+				t,
+				parseErr.Error(),
+				"at line 5, column 6: expected integer, got 'bad-int'",
+			)
 		},
 	)
 
@@ -1415,3 +1526,236 @@ func TestNewStringBasedBuilderForTestCmdlineKind(t *testing.T) {
 	require.NotNil(t, sb)
 }
 
+// TestNewStringBasedBuilderForTestFileKind verifies that kind "file"
+// produces a builder reporting dotted file keys.
+func TestNewStringBasedBuilderForTestFileKind(t *testing.T) {
+	t.Parallel()
+
+	type Sub struct {
+		Host *string
+	}
+
+	type Root struct {
+		Database *Sub
+	}
+
+	p := NewMockStringValuesProvider(t)
+	p.EXPECT().GetStringValues().Return(svalue.Values{})
+
+	sb, err := NewStringBasedBuilderForTest(p, "file", "/etc/app.yaml")
+	require.NoError(t, err)
+
+	mdl, err := BuildModel(&Root{})
+	require.NoError(t, err)
+
+	inv, err := sb.ReportInventory(mdl)
+	require.NoError(t, err)
+	require.Equal(
+		t,
+		LayerInventory{
+			Name: "file:/etc/app.yaml",
+			Provides: []FieldProvision{
+				{
+					FieldUID: "Database.Host",
+					Key:      "database.host",
+				},
+			},
+		},
+		inv,
+	)
+}
+
+// TestStringBasedBuilder_GetFieldValuesFromIgnoreUnbounded verifies that
+// unbounded keys are dropped when the builder ignores them.
+func TestStringBasedBuilder_GetFieldValuesFromIgnoreUnbounded(t *testing.T) {
+	t.Parallel()
+
+	type Root struct {
+		A *int
+	}
+
+	mdl, err := BuildModel(&Root{})
+	require.NoError(t, err)
+
+	sb, err := NewStringBasedBuilder(
+		&stubValuesProvider{
+			values: svalue.Values{
+				"a": {Location: "loc-a", Value: "12"},
+				"b": {Location: "loc-b", Value: "13"},
+			},
+		},
+	)
+	require.NoError(t, err)
+
+	sb.ignoreUnbounded = true
+
+	values, err := sb.GetFieldValuesFrom(mdl)
+	require.NoError(t, err)
+	require.Len(t, values, 1)
+	require.Equal(t, "loc-a", values[0].Location)
+}
+
+type stubValuesProvider struct {
+	values svalue.Values
+	name   string
+}
+
+func (p *stubValuesProvider) GetName() string {
+	return p.name
+}
+
+func (p *stubValuesProvider) GetStringValues() svalue.Values {
+	return p.values
+}
+
+func TestStringBasedBuilder_GetEntryKeys(t *testing.T) {
+	t.Parallel()
+
+	sb := &StringBasedBuilder{
+		values: svalue.Values{
+			"backends-eu-url":           {},
+			"backends-eu-west-url":      {},
+			"backends-eu-west-tls-cert": {},
+			"backends-asia":             {},
+			"backends-us-labels-team":   {},
+			"other-url":                 {},
+			"backends":                  {},
+		},
+	}
+
+	require.Equal(
+		t,
+		[]string{"asia", "eu", "eu-west", "us"},
+		sb.GetEntryKeys(
+			"Backends",
+			[]string{"URL", "TLS", "TLS.Cert", "Labels"},
+			[]string{"Labels"},
+		),
+	)
+
+	require.Equal(
+		t,
+		[]string{"asia", "eu-url", "eu-west-tls-cert", "eu-west-url", "us-labels-team"},
+		sb.GetEntryKeys("Backends", nil, nil),
+	)
+
+	require.Equal(
+		t,
+		[]string{"asia", "eu", "us"},
+		sb.GetEntryKeys("Backends", nil, []string{""}),
+	)
+}
+
+func TestStringBasedBuilder_GetMap(t *testing.T) {
+	t.Parallel()
+
+	mapType := reflect.TypeOf(map[string]*int{})
+
+	t.Run(
+		"spread entries", func(t *testing.T) {
+			t.Parallel()
+
+			sb := &StringBasedBuilder{
+				values: svalue.Values{
+					"weights": {
+						Location: "loc-blob",
+						Value:    "{eu: 1, us: 2, asia: null, sub: {a: 1}}",
+					},
+					"weights-us": {
+						Location: "loc-us",
+						Value:    "3",
+					},
+				},
+			}
+
+			fv, err := sb.Get("Weights", mapType)
+			require.NoError(t, err)
+			require.Equal(
+				t,
+				&fvalue.Value{Location: "loc-blob"},
+				fv,
+			)
+			require.Equal(
+				t,
+				svalue.Values{
+					"weights-eu": {
+						Location: "loc-blob",
+						Value:    "1",
+					},
+					"weights-us": {
+						Location: "loc-us",
+						Value:    "3",
+					},
+					"weights-sub": {
+						Location: "loc-blob",
+						Value:    "{a: 1}\n",
+					},
+				},
+				sb.values,
+			)
+		},
+	)
+
+	t.Run(
+		"parse error", func(t *testing.T) {
+			t.Parallel()
+
+			sb := &StringBasedBuilder{
+				values: svalue.Values{
+					"weights": {
+						Location: "loc-blob",
+						Value:    "[1, 2]",
+					},
+				},
+			}
+
+			fv, err := sb.Get("Weights", mapType)
+			require.Nil(t, fv)
+			require.Equal(
+				t,
+				ParseError{
+					Path:     "Weights",
+					Type:     mapType,
+					Location: "loc-blob",
+					Hint:     "expected mapping, got a list",
+					Line:     1,
+					Column:   1,
+				},
+				err,
+			)
+		},
+	)
+
+	t.Run(
+		"not found", func(t *testing.T) {
+			t.Parallel()
+
+			sb := &StringBasedBuilder{
+				values: svalue.Values{},
+			}
+
+			fv, err := sb.Get("Weights", mapType)
+			require.NoError(t, err)
+			require.Nil(t, fv)
+		},
+	)
+}
+
+func TestStringBasedBuilder_GetPlainValue(t *testing.T) {
+	t.Parallel()
+
+	sb := &StringBasedBuilder{
+		values: svalue.Values{
+			"labels-team": {
+				Location: "loc1",
+				Value:    "core",
+			},
+		},
+	}
+
+	fv, err := sb.Get("Labels[team]", reflect.TypeOf(""))
+	require.NoError(t, err)
+	require.Equal(t, "core", fv.Value.Interface())
+	require.Equal(t, "loc1", fv.Location)
+	require.Empty(t, sb.values)
+}
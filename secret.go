@@ -0,0 +1,5 @@
+package dsco
+
+// SecretMask replaces the values of secret fields (tagged dsco:"secret") in
+// every output surface: inventory reports, dumps and error messages.
+const SecretMask = "******"
@@ -0,0 +1,328 @@
+package dsco
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/byte4ever/dsco/internal/model"
+	"github.com/byte4ever/dsco/svalue"
+)
+
+// ErrSecretOnlyOption represents an error where a secret option is used by a
+// layer that is not a secret layer.
+var ErrSecretOnlyOption = errors.New("option is only supported by secret layers")
+
+// ErrInvalidSecretRef represents an error where a secret reference is not in
+// the path#field form or maps no field.
+var ErrInvalidSecretRef = errors.New("invalid secret reference")
+
+// ErrDuplicateSecretID is the sentinel error for duplicate secret layer id.
+var ErrDuplicateSecretID = errors.New("duplicate secret layer id")
+
+// InvalidSecretRefError represents an error where the secret reference Ref
+// of the field located at Path is invalid.
+type InvalidSecretRefError struct {
+	Path   string
+	Ref    string
+	Reason string
+}
+
+func (e InvalidSecretRefError) Error() string {
+	return fmt.Sprintf("secret %q of %s: %s", e.Ref, e.Path, e.Reason)
+}
+
+func (InvalidSecretRefError) Is(err error) bool {
+	return errors.Is(err, ErrInvalidSecretRef)
+}
+
+// DuplicateSecretIDError represents an error where the same id is used by
+// multiple secret layers.
+type DuplicateSecretIDError struct {
+	ID    string
+	Index int
+}
+
+func (e DuplicateSecretIDError) Error() string {
+	return fmt.Sprintf(
+		"secret layer #%d is using same id=%q",
+		e.Index,
+		e.ID,
+	)
+}
+
+func (DuplicateSecretIDError) Is(err error) bool {
+	return errors.Is(err, ErrDuplicateSecretID)
+}
+
+// Secret is a secret of a KV secret store.
+type Secret struct {
+	// Data holds the secret fields by name.
+	Data map[string]string
+
+	// LeaseDuration is the time the secret stays valid once read, zero when
+	// it is not leased.
+	LeaseDuration time.Duration
+}
+
+// SecretClient reads secrets from a KV secret store (i.e. a Vault KV
+// engine). Implementations wrap the store client, an in-memory fake or a
+// local development server.
+type SecretClient interface {
+	// ReadSecrets returns the secrets located at paths by path, read in a
+	// single batch. Missing secrets are left out of the result.
+	ReadSecrets(ctx context.Context, paths []string) (map[string]Secret, error)
+}
+
+// StaticSecrets is an in-memory SecretClient holding secrets by path, for
+// tests and local development.
+type StaticSecrets map[string]Secret
+
+// ReadSecrets implements SecretClient.
+func (s StaticSecrets) ReadSecrets(
+	_ context.Context,
+	paths []string,
+) (map[string]Secret, error) {
+	result := make(map[string]Secret, len(paths))
+
+	for _, path := range paths {
+		if secret, found := s[path]; found {
+			result[path] = secret
+		}
+	}
+
+	return result, nil
+}
+
+// SecretOption is a processing option only supported by secret layers.
+type SecretOption interface {
+	Option
+	secretRefs() map[string]string
+}
+
+type secretPathsOption map[string]string
+
+func (secretPathsOption) apply(*internalOpts) error {
+	return ErrSecretOnlyOption
+}
+
+func (o secretPathsOption) secretRefs() map[string]string {
+	return o
+}
+
+// WithSecretPaths maps the fields located at the refs keys (i.e.
+// "Database.Password") to the path#field secret references of the refs
+// values, in addition to, and over, the secret struct tags.
+func WithSecretPaths(refs map[string]string) SecretOption {
+	return secretPathsOption(refs)
+}
+
+// splitSecretOptions separates the secret references set by options from
+// string based builder options.
+func splitSecretOptions(options []Option) (map[string]string, []Option) {
+	var (
+		refs           = make(map[string]string)
+		builderOptions []Option
+	)
+
+	for _, option := range options {
+		if so, ok := option.(SecretOption); ok {
+			for path, ref := range so.secretRefs() {
+				refs[path] = ref
+			}
+
+			continue
+		}
+
+		builderOptions = append(builderOptions, option)
+	}
+
+	return refs, builderOptions
+}
+
+// secretRefs returns the secret references of the model fields by field
+// path, the ones of the options overriding the struct tags. The model is nil
+// when unknown.
+func secretRefs(
+	mdl ModelInterface,
+	optionRefs map[string]string,
+) (map[string]string, error) {
+	refs := make(map[string]string, len(optionRefs))
+
+	if mdl != nil {
+		for path, ref := range mdl.TaggedKeys(model.SecretTag) {
+			refs[path] = ref
+		}
+
+		aliases, err := collectAliases(mdl)
+		if err != nil {
+			return nil, err
+		}
+
+		for path, ref := range optionRefs {
+			if _, found := aliases[path]; !found {
+				return nil, InvalidSecretRefError{
+					Path:   path,
+					Ref:    ref,
+					Reason: "no such field",
+				}
+			}
+		}
+	}
+
+	for path, ref := range optionRefs {
+		refs[path] = ref
+	}
+
+	for path, ref := range refs {
+		if _, _, ok := splitSecretRef(ref); !ok {
+			return nil, InvalidSecretRefError{
+				Path:   path,
+				Ref:    ref,
+				Reason: "not a path#field reference",
+			}
+		}
+	}
+
+	return refs, nil
+}
+
+// splitSecretRef splits a path#field secret reference.
+func splitSecretRef(ref string) (string, string, bool) {
+	idx := strings.LastIndexByte(ref, '#')
+	if idx <= 0 || idx == len(ref)-1 {
+		return "", "", false
+	}
+
+	return ref[:idx], ref[idx+1:], true
+}
+
+// readSecrets reads the secrets referenced by refs in a single batch and
+// returns a provider of their fields, and the time the first lease expires,
+// zero when no secret is leased.
+func readSecrets(
+	ctx context.Context,
+	client SecretClient,
+	id string,
+	refs map[string]string,
+) (*namedValues, time.Time, error) {
+	dedup := make(map[string]struct{}, len(refs))
+
+	for _, ref := range refs {
+		path, _, _ := splitSecretRef(ref)
+		dedup[path] = struct{}{}
+	}
+
+	paths := make([]string, 0, len(dedup))
+	for path := range dedup {
+		paths = append(paths, path)
+	}
+
+	sort.Strings(paths)
+
+	provider := &namedValues{
+		values: make(svalue.Values, len(refs)),
+		name:   fmt.Sprintf("secret(%s)", id),
+	}
+
+	if len(paths) == 0 {
+		return provider, time.Time{}, nil
+	}
+
+	secrets, err := client.ReadSecrets(ctx, paths)
+	if err != nil {
+		return nil, time.Time{}, err //nolint:wrapcheck // wrapped by caller
+	}
+
+	var (
+		now       = time.Now()
+		refreshAt time.Time
+	)
+
+	for fieldPath, ref := range refs {
+		path, field, _ := splitSecretRef(ref)
+
+		secret, found := secrets[path]
+		if !found {
+			continue
+		}
+
+		value, found := secret.Data[field]
+		if !found {
+			continue
+		}
+
+		provider.values[convert(fieldPath)] = &svalue.Value{
+			Location: fmt.Sprintf("secret[%s]:%s", id, ref),
+			Value:    value,
+		}
+
+		if secret.LeaseDuration <= 0 {
+			continue
+		}
+
+		if expiry := now.Add(secret.LeaseDuration); refreshAt.IsZero() ||
+			expiry.Before(refreshAt) {
+			refreshAt = expiry
+		}
+	}
+
+	return provider, refreshAt, nil
+}
+
+// Secret read retry backoff bounds, once a lease expired.
+const (
+	secretRetryMin = time.Second
+	secretRetryMax = time.Minute
+)
+
+// secretLease holds the time the first lease of the secrets read by a layer
+// expires, and the number of failed reads since it expired.
+type secretLease struct {
+	refreshAt time.Time
+	failures  int
+	mu        sync.Mutex
+}
+
+func (l *secretLease) set(refreshAt time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.refreshAt = refreshAt
+	l.failures = 0
+}
+
+// fail pushes the refresh time of a leased layer whose read failed back,
+// doubling the delay from secretRetryMin up to secretRetryMax on each
+// failure, so the store is not read again on every watch poll.
+func (l *secretLease) fail(now time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.refreshAt.IsZero() {
+		return
+	}
+
+	backoff := secretRetryMin
+	for i := 0; i < l.failures && backoff < secretRetryMax; i++ {
+		backoff *= 2
+	}
+
+	if backoff > secretRetryMax {
+		backoff = secretRetryMax
+	}
+
+	l.failures++
+	l.refreshAt = now.Add(backoff)
+}
+
+func (l *secretLease) get() time.Time {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.refreshAt
+}
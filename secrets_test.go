@@ -0,0 +1,387 @@
+package dsco
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// recordingSecrets is a SecretClient recording the batches it reads.
+type recordingSecrets struct {
+	secrets StaticSecrets
+	batches [][]string
+	err     error
+	mu      sync.Mutex
+}
+
+func (r *recordingSecrets) ReadSecrets(
+	ctx context.Context,
+	paths []string,
+) (map[string]Secret, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.batches = append(r.batches, paths)
+
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	return r.secrets.ReadSecrets(ctx, paths)
+}
+
+func (r *recordingSecrets) fail(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.err = err
+}
+
+func (r *recordingSecrets) reads() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return len(r.batches)
+}
+
+func (r *recordingSecrets) set(path string, secret Secret) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.secrets[path] = secret
+}
+
+type secretDatabase struct {
+	Host     *string
+	User     *string `secret:"db/creds#username"`
+	Password *string `secret:"db/creds#password"`
+}
+
+type secretConfig struct {
+	Database *secretDatabase
+	APIKey   *string
+}
+
+func TestSecretOption_apply(t *testing.T) {
+	t.Parallel()
+
+	option := WithSecretPaths(map[string]string{"A": "a#b"})
+
+	require.ErrorIs(t, option.apply(&internalOpts{}), ErrSecretOnlyOption)
+	require.Equal(t, map[string]string{"A": "a#b"}, option.secretRefs())
+
+	err := WithCmdlineLayer(WithArgs(), option).register(newLayerBuilder(1))
+	require.ErrorIs(t, err, ErrSecretOnlyOption)
+}
+
+func TestStaticSecrets_ReadSecrets(t *testing.T) {
+	t.Parallel()
+
+	secrets, err := StaticSecrets{
+		"a": {Data: map[string]string{"k": "v"}},
+	}.ReadSecrets(context.Background(), []string{"a", "b"})
+	require.NoError(t, err)
+	require.Equal(
+		t,
+		map[string]Secret{"a": {Data: map[string]string{"k": "v"}}},
+		secrets,
+	)
+}
+
+func TestFill_secretLayer(t *testing.T) {
+	t.Parallel()
+
+	client := &recordingSecrets{
+		secrets: StaticSecrets{
+			"db/creds": {
+				Data: map[string]string{
+					"username": "app",
+					"password": "s3cr3t",
+				},
+				LeaseDuration: time.Hour,
+			},
+			"api": {Data: map[string]string{"key": "k3y"}},
+		},
+	}
+
+	layer := WithSecretLayer(
+		client,
+		"vault",
+		WithSecretPaths(map[string]string{"APIKey": "api#key"}),
+	)
+
+	var cfg *secretConfig
+
+	locations, err := Fill(
+		&cfg,
+		WithCmdlineLayer(WithArgs("--database-host=db.local")),
+		layer,
+	)
+	require.NoError(t, err)
+	require.Equal(t, "db.local", *cfg.Database.Host)
+	require.Equal(t, "app", *cfg.Database.User)
+	require.Equal(t, "s3cr3t", *cfg.Database.Password)
+	require.Equal(t, "k3y", *cfg.APIKey)
+
+	// a single batch of unique paths
+	require.Equal(t, [][]string{{"api", "db/creds"}}, client.batches)
+
+	password := locationOf(t, locations, "Database.Password")
+	require.Equal(t, "secret[vault]:db/creds#password", password.Location)
+	require.True(t, password.Secret)
+	require.True(t, locationOf(t, locations, "APIKey").Secret)
+	require.False(t, locationOf(t, locations, "Database.Host").Secret)
+
+	refreshAt := layer.RefreshAt()
+	require.WithinDuration(t, time.Now().Add(time.Hour), refreshAt, time.Minute)
+
+	// earlier layers win over the secret layer
+	_, err = Fill(
+		&cfg,
+		WithCmdlineLayer(
+			WithArgs("--database-host=h", "--database-password=local"),
+		),
+		WithSecretLayer(client, "vault"),
+		WithStructLayer(&secretConfig{APIKey: R("default")}, "defaults"),
+	)
+	require.NoError(t, err)
+	require.Equal(t, "local", *cfg.Database.Password)
+	require.Equal(t, "default", *cfg.APIKey)
+
+	_, err = Fill(
+		&cfg,
+		WithCmdlineLayer(
+			WithArgs("--database-host=h", "--database-password=local"),
+		),
+		WithStrictSecretLayer(client, "vault"),
+		WithStructLayer(&secretConfig{APIKey: R("default")}, "defaults"),
+	)
+	require.ErrorContains(t, err, "secret[vault]:db/creds#password")
+}
+
+func TestFill_secretLayerErrors(t *testing.T) {
+	t.Parallel()
+
+	var cfg *secretConfig
+
+	_, err := Fill(
+		&cfg,
+		WithSecretLayer(
+			StaticSecrets{},
+			"vault",
+			WithSecretPaths(map[string]string{"APIKey": "api"}),
+		),
+	)
+	require.ErrorContains(
+		t,
+		err,
+		`secret "api" of APIKey: not a path#field reference`,
+	)
+
+	_, err = Fill(
+		&cfg,
+		WithSecretLayer(
+			StaticSecrets{},
+			"vault",
+			WithSecretPaths(map[string]string{"Unknown": "a#b"}),
+		),
+	)
+	require.ErrorContains(t, err, `secret "a#b" of Unknown: no such field`)
+
+	_, err = Fill(
+		&cfg,
+		WithSecretLayer(StaticSecrets{}, "vault"),
+		WithSecretLayer(StaticSecrets{}, "vault"),
+	)
+	require.ErrorContains(t, err, `secret layer #0 is using same id="vault"`)
+
+	errUnreachable := errors.New("store unreachable")
+
+	_, err = Fill(
+		&cfg,
+		WithSecretLayer(&recordingSecrets{err: errUnreachable}, "vault"),
+	)
+	require.ErrorContains(t, err, "secret builder: store unreachable")
+
+	var refErr InvalidSecretRefError
+
+	_, err = secretRefs(nil, map[string]string{"A": "#b"})
+	require.ErrorAs(t, err, &refErr)
+	require.ErrorIs(t, refErr, ErrInvalidSecretRef)
+}
+
+func TestStringBasedBuilder_ReportInventory_secret(t *testing.T) {
+	t.Parallel()
+
+	var cfg *secretConfig
+
+	mdl, err := buildModel(cfg)
+	require.NoError(t, err)
+
+	policies, err := Layers{
+		WithSecretLayer(StaticSecrets{}, "vault"),
+	}.GetPolicies(mdl)
+	require.NoError(t, err)
+
+	builder, ok := policies[0].getFieldValuesGetter().(*StringBasedBuilder)
+	require.True(t, ok)
+
+	inv, err := builder.ReportInventory(mdl)
+	require.NoError(t, err)
+	require.Equal(t, "secret:vault", inv.Name)
+	require.ElementsMatch(
+		t,
+		[]FieldProvision{
+			{FieldUID: "Database.User", Key: "db/creds#username"},
+			{FieldUID: "Database.Password", Key: "db/creds#password"},
+		},
+		inv.Provides,
+	)
+}
+
+func TestWatch_secretLease(t *testing.T) {
+	t.Parallel()
+
+	client := &recordingSecrets{
+		secrets: StaticSecrets{
+			"db/creds": {
+				Data: map[string]string{
+					"username": "app",
+					"password": "v1",
+				},
+				LeaseDuration: 50 * time.Millisecond,
+			},
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var cfg *secretConfig
+
+	w, err := WatchWithInterval(
+		ctx,
+		10*time.Millisecond,
+		&cfg,
+		WithSecretLayer(client, "vault"),
+		WithStructLayer(
+			&secretConfig{
+				Database: &secretDatabase{Host: R("h")},
+				APIKey:   R("k"),
+			},
+			"defaults",
+		),
+	)
+	require.NoError(t, err)
+
+	client.set(
+		"db/creds",
+		Secret{
+			Data: map[string]string{
+				"username": "app",
+				"password": "v2",
+			},
+			LeaseDuration: time.Hour,
+		},
+	)
+
+	select {
+	case event := <-w.Events():
+		require.NoError(t, event.Err)
+		require.Len(t, event.Changes, 1)
+		require.Equal(t, "Database.Password", event.Changes[0].Path)
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "no watch event")
+	}
+
+	require.Equal(t, "v2", *w.Config().Database.Password)
+}
+
+func Test_secretLease_fail(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+
+	lease := &secretLease{}
+	lease.fail(now)
+	require.Zero(t, lease.get(), "not leased")
+
+	lease.set(now)
+
+	for _, want := range []time.Duration{
+		time.Second,
+		2 * time.Second,
+		4 * time.Second,
+		8 * time.Second,
+		16 * time.Second,
+		32 * time.Second,
+		time.Minute,
+		time.Minute,
+	} {
+		lease.fail(now)
+		require.Equal(t, now.Add(want), lease.get())
+	}
+
+	lease.set(now)
+	lease.fail(now)
+	require.Equal(t, now.Add(time.Second), lease.get())
+}
+
+func TestWatch_secretReadFailure(t *testing.T) {
+	t.Parallel()
+
+	client := &recordingSecrets{
+		secrets: StaticSecrets{
+			"db/creds": {
+				Data: map[string]string{
+					"username": "app",
+					"password": "v1",
+				},
+				LeaseDuration: 20 * time.Millisecond,
+			},
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var cfg *secretConfig
+
+	layer := WithSecretLayer(client, "vault")
+
+	w, err := WatchWithInterval(
+		ctx,
+		5*time.Millisecond,
+		&cfg,
+		layer,
+		WithStructLayer(
+			&secretConfig{
+				Database: &secretDatabase{Host: R("h")},
+				APIKey:   R("k"),
+			},
+			"defaults",
+		),
+	)
+	require.NoError(t, err)
+
+	client.fail(errMocked1)
+
+	select {
+	case event := <-w.Events():
+		require.ErrorContains(t, event.Err, errMocked1.Error())
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "no watch event")
+	}
+
+	// the next read is backed off, the watch keeps the last configuration
+	time.Sleep(200 * time.Millisecond)
+
+	require.Equal(t, 2, client.reads())
+	require.Empty(t, w.Events())
+	require.True(t, layer.RefreshAt().After(time.Now().Add(500*time.Millisecond)))
+	require.Equal(t, "v1", *w.Config().Database.Password)
+}
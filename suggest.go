@@ -0,0 +1,118 @@
+package dsco
+
+import (
+	"sort"
+	"strings"
+)
+
+// maxSuggestions is the maximum number of keys suggested for an unbounded
+// key.
+const maxSuggestions = 3
+
+// closestKeys returns the keys closest to key by edit distance, ignoring
+// case, at most maxSuggestions of them. Only the keys at the smallest
+// distance are suggested, provided it is at most a third of the length of
+// alias, the key without its layer decoration (prefix, dashes...), and at
+// least 1.
+func closestKeys(key, alias string, keys []string) []string {
+	type candidate struct {
+		key      string
+		distance int
+	}
+
+	maxDistance := max(1, len([]rune(alias))/3)
+
+	var candidates []candidate
+
+	lowerKey := strings.ToLower(key)
+
+	for _, k := range keys {
+		distance := editDistance(lowerKey, strings.ToLower(k))
+		if distance == 0 || distance > maxDistance {
+			continue
+		}
+
+		candidates = append(candidates, candidate{key: k, distance: distance})
+	}
+
+	sort.Slice(
+		candidates, func(i, j int) bool {
+			if candidates[i].distance != candidates[j].distance {
+				return candidates[i].distance < candidates[j].distance
+			}
+
+			return candidates[i].key < candidates[j].key
+		},
+	)
+
+	var suggestions []string
+
+	for _, c := range candidates {
+		if c.distance > candidates[0].distance ||
+			len(suggestions) == maxSuggestions {
+			break
+		}
+
+		suggestions = append(suggestions, c.key)
+	}
+
+	return suggestions
+}
+
+// editDistance returns the optimal string alignment distance between a and
+// b: the Levenshtein distance where swapping two adjacent letters (i.e.
+// hots for host) is a single edit.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+
+	beforePrevious := make([]int, len(rb)+1)
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+
+			current[j] = min(
+				previous[j]+1,
+				current[j-1]+1,
+				previous[j-1]+cost,
+			)
+
+			if i > 1 && j > 1 &&
+				ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				current[j] = min(current[j], beforePrevious[j-2]+1)
+			}
+		}
+
+		beforePrevious, previous, current = previous, current, beforePrevious
+	}
+
+	return previous[len(rb)]
+}
+
+// didYouMean returns the ", did you mean A, B or C?" suffix of suggestions,
+// empty when there are none.
+func didYouMean(suggestions []string) string {
+	switch len(suggestions) {
+	case 0:
+		return ""
+	case 1:
+		return ", did you mean " + suggestions[0] + "?"
+	default:
+		last := len(suggestions) - 1
+
+		return ", did you mean " +
+			strings.Join(suggestions[:last], ", ") +
+			" or " + suggestions[last] + "?"
+	}
+}
@@ -0,0 +1,100 @@
+package dsco
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_editDistance(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"", "abc", 3},
+		{"abc", "", 3},
+		{"host", "host", 0},
+		{"databse", "database", 1},
+		{"kitten", "sitting", 3},
+		{"héllo", "hello", 1},
+		{"hots", "host", 1},
+		{"prot", "port", 1},
+		{"ca", "abc", 3},
+		{"databsae", "database", 1},
+	} {
+		require.Equal(t, tt.want, editDistance(tt.a, tt.b), tt.a+"/"+tt.b)
+	}
+}
+
+func Test_closestKeys(t *testing.T) {
+	t.Parallel()
+
+	keys := []string{
+		"APP-DATABASE-HOST",
+		"APP-DATABASE-PORT",
+		"APP-DATABASE-USER",
+		"APP-NAME",
+	}
+
+	require.Equal(
+		t,
+		[]string{"APP-DATABASE-HOST"},
+		closestKeys("APP-DATABSE-HOST", "databse-host", keys),
+	)
+	require.Equal(
+		t,
+		[]string{"APP-DATABASE-HOST", "APP-DATABASE-PORT"},
+		closestKeys("APP-DATABASE-HORT", "database-hort", keys),
+	)
+	require.Equal(
+		t,
+		[]string{"APP-NAME"},
+		closestKeys("app-nam", "nam", keys),
+	)
+	require.Equal(
+		t,
+		[]string{"APP-DATABASE-HOST"},
+		closestKeys("APP-DATABASE-HOTS", "database-hots", keys),
+	)
+	require.Equal(
+		t,
+		[]string{"--host"},
+		closestKeys("--hots", "hots", []string{"--host", "--port"}),
+	)
+	require.Equal(
+		t,
+		[]string{"--port"},
+		closestKeys("--prot", "prot", []string{"--host", "--port"}),
+	)
+	require.Nil(t, closestKeys("APP-TIMEOUT", "timeout", keys))
+	require.Nil(t, closestKeys("APP-NAME", "name", keys))
+	require.Equal(
+		t,
+		[]string{
+			"APP-DATABASE-HOST",
+			"APP-DATABASE-PORT",
+			"APP-DATABASE-USER",
+		},
+		closestKeys(
+			"APP-DATABASE-XXXX",
+			"database-xxxx",
+			append(keys, "APP-DATABASE-ZZZZ"),
+		),
+	)
+}
+
+func Test_didYouMean(t *testing.T) {
+	t.Parallel()
+
+	require.Empty(t, didYouMean(nil))
+	require.Equal(t, ", did you mean A?", didYouMean([]string{"A"}))
+	require.Equal(t, ", did you mean A or B?", didYouMean([]string{"A", "B"}))
+	require.Equal(
+		t,
+		", did you mean A, B or C?",
+		didYouMean([]string{"A", "B", "C"}),
+	)
+}
@@ -0,0 +1,316 @@
+package dsco
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/byte4ever/dsco/internal/fvalue"
+	"github.com/byte4ever/dsco/internal/model"
+	"github.com/byte4ever/dsco/registry"
+)
+
+//nolint:gochecknoglobals // replaced by tests
+var (
+	// helpOutput receives the usage text printed on --help.
+	helpOutput io.Writer = os.Stdout
+
+	// helpExit ends the program once the usage text is printed.
+	helpExit = os.Exit
+)
+
+const usageIndent = "        "
+
+// usageField is the usage text entry of a leaf field.
+type usageField struct {
+	defaultValue any
+	path         string
+	goType       string
+	flag         string
+	env          string
+	description  string
+	hasDefault   bool
+	optional     bool
+}
+
+// usageRecorder implements internal.ValueGetter to capture the path and
+// the type of every leaf field. No values are produced.
+type usageRecorder struct {
+	fields []usageField
+}
+
+// Get records the leaf field and returns (nil, nil) so the model treats
+// the field as unfilled.
+func (r *usageRecorder) Get(
+	path string,
+	fieldType reflect.Type,
+) (*fvalue.Value, error) {
+	if fieldType.Kind() == reflect.Pointer {
+		fieldType = fieldType.Elem()
+	}
+
+	r.fields = append(
+		r.fields,
+		usageField{
+			path:   path,
+			goType: registry.ShortTypeName(fieldType),
+		},
+	)
+
+	return nil, nil //nolint:nilnil // matches StringBasedBuilder.Get when nothing is found
+}
+
+// Usage writes the command line usage text of the configuration to
+// writer: every accepted --flag with its type, description (desc struct
+// tag), default value from the struct layers and environment variable from
+// the first env layer. cfg must be **T, like for Fill. Secret defaults are
+// masked.
+func Usage(writer io.Writer, cfg any, layers ...Layer) error {
+	const errCtx = "writing usage"
+
+	rv := reflect.ValueOf(cfg)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("%s: %w", errCtx, ErrCfgMustBePointer)
+	}
+
+	mdl, err := buildModel(rv.Elem().Interface())
+	if err != nil {
+		return fmt.Errorf("%s: %w", errCtx, err)
+	}
+
+	policies, err := Layers(layers).GetPolicies(mdl)
+	if err != nil {
+		return fmt.Errorf("%s: %w", errCtx, err)
+	}
+
+	if err := writeUsage(
+		writer,
+		programName(),
+		mdl,
+		policies,
+		nil,
+	); err != nil {
+		return fmt.Errorf("%s: %w", errCtx, err)
+	}
+
+	return nil
+}
+
+// showHelp prints the usage text and exits when the command line layer
+// asks for it.
+func (c *dscoContext) showHelp() {
+	if !c.err.None() {
+		return
+	}
+
+	for _, builder := range c.builders {
+		sb, ok := builder.getFieldValuesGetter().(*StringBasedBuilder)
+		if !ok || !sb.helpRequested {
+			continue
+		}
+
+		name, commands := programName(), []*Command(nil)
+		if sb.scope != nil {
+			name, commands = sb.scope.program, sb.scope.commands
+		}
+
+		if err := writeUsage(
+			helpOutput,
+			name,
+			c.model,
+			c.builders,
+			commands,
+		); err != nil {
+			c.err.Add(err)
+			return
+		}
+
+		helpExit(0)
+
+		return
+	}
+}
+
+// programName returns the name the program was invoked with.
+func programName() string {
+	return filepath.Base(os.Args[0])
+}
+
+// writeUsage writes the usage text of the model with the keys and the
+// defaults of the layers, followed by the commands when there are some.
+func writeUsage(
+	writer io.Writer,
+	name string,
+	mdl ModelInterface,
+	policies constraintLayerPolicies,
+	commands []*Command,
+) error {
+	fields, err := usageFields(mdl, policies)
+	if err != nil {
+		return err
+	}
+
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "Usage of %s:\n", name)
+
+	for _, field := range fields {
+		fmt.Fprintf(&sb, "  %s<%s>\n", field.flag, field.goType)
+
+		if line := field.line(); line != "" {
+			sb.WriteString(usageIndent + line + "\n")
+		}
+	}
+
+	sb.WriteString("  -h, --help\n" + usageIndent + "Show this help and exit\n")
+
+	writeCommands(&sb, commands)
+
+	if _, err := io.WriteString(writer, sb.String()); err != nil {
+		return fmt.Errorf("writing usage: %w", err)
+	}
+
+	return nil
+}
+
+// writeCommands writes the commands block of the usage text, in declaration
+// order.
+func writeCommands(sb *strings.Builder, commands []*Command) {
+	if len(commands) == 0 {
+		return
+	}
+
+	width := 0
+
+	for _, command := range commands {
+		if len(command.Name) > width {
+			width = len(command.Name)
+		}
+	}
+
+	sb.WriteString("\nCommands:\n")
+
+	for _, command := range commands {
+		fmt.Fprintf(
+			sb,
+			"  %s\n",
+			strings.TrimRight(
+				fmt.Sprintf("%-*s   %s", width, command.Name, command.Description),
+				" ",
+			),
+		)
+	}
+}
+
+// usageFields returns the usage entries of every leaf field of the model,
+// sorted by path.
+func usageFields(
+	mdl ModelInterface,
+	policies constraintLayerPolicies,
+) ([]usageField, error) {
+	rec := &usageRecorder{}
+	_, _ = mdl.ApplyOn(rec) //nolint:errcheck // recorder never errors
+
+	var (
+		envLayer *StringBasedBuilder
+		defaults []LayerInventory
+	)
+
+	for _, policy := range policies {
+		switch layer := policy.getFieldValuesGetter().(type) {
+		case *StringBasedBuilder:
+			if envLayer == nil && layer.keyFormatter != nil &&
+				layer.keyFormatter.LayerKind() == "env" {
+				envLayer = layer
+			}
+		case *StructBuilder:
+			inv, err := layer.ReportInventory(mdl)
+			if err != nil {
+				return nil, err
+			}
+
+			defaults = append(defaults, inv)
+		}
+	}
+
+	fields := rec.fields
+	flags := mdl.TaggedKeys(model.FlagTag)
+	cmdlineFormatter := newCmdlineKeyFormatter()
+
+	for i := range fields {
+		field := &fields[i]
+
+		field.description = mdl.Description(field.path)
+		field.optional = mdl.IsOptional(field.path)
+
+		key := convert(field.path)
+		if tagged, found := flags[field.path]; found {
+			key = flagKeyTag().key(tagged)
+		}
+
+		field.flag = cmdlineFormatter.FormatKey(key)
+
+		if envLayer != nil {
+			field.env = envLayer.formatKey(field.path)
+		}
+
+		// first struct layer wins, like Fill does
+		field.defaultValue, field.hasDefault = findDefault(defaults, field.path)
+		if field.hasDefault && mdl.IsSecret(field.path) {
+			field.defaultValue = SecretMask
+		}
+	}
+
+	sort.Slice(
+		fields, func(i, j int) bool {
+			return fields[i].path < fields[j].path
+		},
+	)
+
+	return fields, nil
+}
+
+// findDefault returns the value of the field located at path provided by
+// the first struct layer.
+func findDefault(defaults []LayerInventory, path string) (any, bool) {
+	for _, inv := range defaults {
+		for _, provision := range inv.Provides {
+			if provision.FieldUID == path && provision.Value != nil {
+				return provision.Value, true
+			}
+		}
+	}
+
+	return nil, false
+}
+
+// line returns the description line of the field: its description
+// followed by its default value, optional flag and environment variable.
+func (f *usageField) line() string {
+	var notes []string
+
+	if f.hasDefault {
+		notes = append(notes, fmt.Sprintf("default: %v", f.defaultValue))
+	} else if f.optional {
+		notes = append(notes, "optional")
+	}
+
+	if f.env != "" {
+		notes = append(notes, "env: "+f.env)
+	}
+
+	if len(notes) == 0 {
+		return f.description
+	}
+
+	line := "(" + strings.Join(notes, ", ") + ")"
+	if f.description == "" {
+		return line
+	}
+
+	return f.description + " " + line
+}
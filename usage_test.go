@@ -0,0 +1,152 @@
+package dsco
+
+import (
+	"bytes"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type usageDatabase struct {
+	Host     *string `desc:"Database host name"`
+	Port     *int
+	Password *string `dsco:"secret" desc:"Database password"`
+}
+
+type usageConfig struct {
+	Database *usageDatabase
+	Timeout  *time.Duration    `dsco:"optional" desc:"Request timeout"`
+	Backends map[string]string `dsco:"optional"`
+	Labels   map[string]string
+}
+
+const expectedUsage = `Usage of api:
+  --backends=<map[string]string>
+        (optional, env: API-BACKENDS)
+  --database-host=<string>
+        Database host name (default: localhost, env: API-DATABASE-HOST)
+  --database-password=<string>
+        Database password (default: ******, env: API-DATABASE-PASSWORD)
+  --database-port=<int>
+        (default: 5432, env: API-DATABASE-PORT)
+  --labels=<map[string]string>
+        (default: map[team:core], env: API-LABELS)
+  --timeout=<Duration>
+        Request timeout (optional, env: API-TIMEOUT)
+  -h, --help
+        Show this help and exit
+`
+
+func usageLayers() []Layer {
+	return []Layer{
+		WithCmdlineLayer(WithHelp()),
+		WithEnvLayer("API"),
+		WithEnvLayer("OTHER"),
+		WithStructLayer(
+			&usageConfig{
+				Database: &usageDatabase{
+					Host:     R("localhost"),
+					Password: R("hunter2"),
+				},
+			},
+			"overrides",
+		),
+		WithStructLayer(
+			&usageConfig{
+				Database: &usageDatabase{
+					Host: R("ignored"),
+					Port: R(5432),
+				},
+				Labels: map[string]string{"team": "core"},
+			},
+			"defaults",
+		),
+	}
+}
+
+//nolint:paralleltest // uses os.Args
+func Test_writeUsage(t *testing.T) {
+	os.Args = []string{"api"}
+
+	var cfg *usageConfig
+
+	mdl, err := buildModel(cfg)
+	require.NoError(t, err)
+
+	policies, err := Layers(usageLayers()).GetPolicies(mdl)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+
+	require.NoError(t, writeUsage(&buf, "api", mdl, policies, nil))
+	require.Equal(t, expectedUsage, buf.String())
+}
+
+//nolint:paralleltest // uses os.Args
+func TestUsage(t *testing.T) {
+	os.Args = []string{"/usr/bin/api"}
+
+	var (
+		cfg *usageConfig
+		buf bytes.Buffer
+	)
+
+	require.NoError(t, Usage(&buf, &cfg, usageLayers()...))
+	require.Equal(t, expectedUsage, buf.String())
+
+	require.ErrorIs(t, Usage(&buf, cfg), ErrCfgMustBePointer)
+
+	err := Usage(&buf, &cfg, WithEnvLayer("API"), WithEnvLayer("API"))
+	require.ErrorContains(t, err, "same prefix=API")
+}
+
+//nolint:paralleltest // replaces os.Args and the help output
+func TestFill_help(t *testing.T) {
+	var (
+		buf      bytes.Buffer
+		exitCode = -1
+	)
+
+	helpOutput, helpExit = &buf, func(code int) { exitCode = code }
+
+	t.Cleanup(
+		func() {
+			helpOutput, helpExit = os.Stdout, os.Exit
+		},
+	)
+
+	os.Args = []string{"api", "--database-port=1", "-h"}
+
+	var cfg *usageConfig
+
+	_, _ = Fill(&cfg, usageLayers()...)
+
+	require.Equal(t, 0, exitCode)
+	require.Equal(t, expectedUsage, buf.String())
+
+	buf.Reset()
+
+	exitCode = -1
+	os.Args = []string{"api", "--database-port=1"}
+
+	_, err := Fill(&cfg, usageLayers()...)
+	require.NoError(t, err)
+	require.Equal(t, -1, exitCode)
+	require.Empty(t, buf.String())
+	require.Equal(t, 1, *cfg.Database.Port)
+}
+
+//nolint:paralleltest // uses os.Args
+func TestWithHelp_notCmdline(t *testing.T) {
+	os.Args = []string{"api", "--help"}
+
+	var cfg *usageConfig
+
+	_, err := Fill(&cfg, WithEnvLayer("API", WithHelp()))
+	require.ErrorContains(t, err, ErrCmdlineOnlyOption.Error())
+
+	_, err = Fill(&cfg, WithCmdlineLayer())
+	require.ErrorContains(t, err, "unbounded location cmdline[--help]")
+}
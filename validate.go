@@ -0,0 +1,99 @@
+package dsco
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/byte4ever/dsco/internal/plocation"
+)
+
+// Validator is implemented by configuration structs providing their own
+// validation (i.e. cross-field checks). Validate is called once the struct
+// and its fields are filled and their tag rules checked.
+type Validator interface {
+	Validate() error
+}
+
+// ErrValidation represents an error indicating that a filled value does not
+// satisfy a dsco tag rule or a Validate method.
+var ErrValidation = errors.New("validation error")
+
+// ValidationError represents a validation failure of the value located at
+// Path. Location is the location of the winning value, it is empty for
+// Validate method failures of structs.
+type ValidationError struct {
+	Path     string
+	Location plocation.Location
+	Rule     string
+	Err      error
+}
+
+func (a ValidationError) Error() string {
+	var sb strings.Builder
+
+	sb.WriteString("validation error on ")
+
+	if a.Path == "" {
+		sb.WriteString("root")
+	} else {
+		sb.WriteString(a.Path)
+	}
+
+	if a.Location.Location != "" {
+		sb.WriteString(" from ")
+		sb.WriteString(a.Location.Location)
+	}
+
+	_, _ = fmt.Fprintf(&sb, " (%s): %v", a.Rule, a.Err)
+
+	return sb.String()
+}
+
+func (ValidationError) Is(err error) bool {
+	return errors.Is(err, ErrValidation)
+}
+
+func (a ValidationError) Unwrap() error {
+	return a.Err
+}
+
+func (c *dscoContext) validate() {
+	if c.err.None() {
+		v := reflect.ValueOf(c.inputModelRef).Elem()
+
+		for _, violation := range c.model.Validate(v) {
+			c.err.Add(
+				ValidationError{
+					Path:     violation.Path,
+					Location: c.winningLocation(violation.Path),
+					Rule:     violation.Rule,
+					Err:      violation.Err,
+				},
+			)
+		}
+	}
+}
+
+// winningLocation returns the location of the value filled for path. For
+// collections, it is the location of their first filled entry value.
+func (c *dscoContext) winningLocation(path string) plocation.Location {
+	for _, location := range c.pathLocations {
+		if location.Path == path {
+			return location
+		}
+	}
+
+	if path == "" {
+		return plocation.Location{}
+	}
+
+	for _, location := range c.pathLocations {
+		if strings.HasPrefix(location.Path, path+"[") {
+			return location
+		}
+	}
+
+	return plocation.Location{}
+}
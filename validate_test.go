@@ -0,0 +1,234 @@
+package dsco
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/byte4ever/dsco/internal/merror"
+	"github.com/byte4ever/dsco/internal/model"
+	"github.com/byte4ever/dsco/internal/plocation"
+	"github.com/byte4ever/dsco/svalue"
+)
+
+var errSameHost = errors.New("primary and backup hosts are the same")
+
+type validatedServer struct {
+	Host   *string
+	Backup *string
+	Port   *int    `dsco:"min=1,max=65535"`
+	Level  *string `dsco:"oneof=debug info warn"`
+}
+
+func (s *validatedServer) Validate() error {
+	if *s.Host == *s.Backup {
+		return errSameHost
+	}
+
+	return nil
+}
+
+type validatedRoot struct {
+	Server *validatedServer
+	Tags   map[string]string `dsco:"max=1"`
+}
+
+func Test_dscoContext_validate(t *testing.T) {
+	t.Parallel()
+
+	t.Run(
+		"skip step",
+		func(t *testing.T) {
+			t.Parallel()
+
+			c := &dscoContext{
+				err: FillerErrors{
+					MError: merror.MError{errMocked1},
+				},
+			}
+
+			c.validate()
+		},
+	)
+
+	t.Run(
+		"violations",
+		func(t *testing.T) {
+			t.Parallel()
+
+			type Root struct {
+				X *int
+				Y map[string]string
+			}
+
+			v := &Root{}
+			pv := &v
+
+			mdl := NewMockModelInterface(t)
+			mdl.EXPECT().
+				Validate(reflect.ValueOf(pv).Elem()).
+				Return(
+					[]model.Violation{
+						{Path: "X", Rule: "min=1", Err: errMocked1},
+						{Path: "Y", Rule: "max=1", Err: errMocked2},
+						{Path: "", Rule: "Validate()", Err: errSameHost},
+					},
+				).
+				Once()
+
+			c := &dscoContext{
+				inputModelRef: pv,
+				model:         mdl,
+				pathLocations: plocation.Locations{
+					{Path: "Y[a]", Location: "loc1", UID: 1},
+					{Path: "X", Location: "loc0"},
+				},
+			}
+
+			c.validate()
+			require.Equal(
+				t,
+				merror.MError{
+					ValidationError{
+						Path:     "X",
+						Location: plocation.Location{Path: "X", Location: "loc0"},
+						Rule:     "min=1",
+						Err:      errMocked1,
+					},
+					ValidationError{
+						Path: "Y",
+						Location: plocation.Location{
+							Path: "Y[a]", Location: "loc1", UID: 1,
+						},
+						Rule: "max=1",
+						Err:  errMocked2,
+					},
+					ValidationError{
+						Rule: "Validate()",
+						Err:  errSameHost,
+					},
+				},
+				c.err.MError,
+			)
+		},
+	)
+}
+
+func TestFill_validation(t *testing.T) {
+	t.Parallel()
+
+	defaults := &validatedRoot{
+		Server: &validatedServer{
+			Host:   R("a.local"),
+			Backup: R("b.local"),
+			Port:   R(8080),
+			Level:  R("info"),
+		},
+		Tags: map[string]string{"team": "core"},
+	}
+
+	t.Run(
+		"success", func(t *testing.T) {
+			t.Parallel()
+
+			var pp *validatedRoot
+
+			_, err := Fill(&pp, WithStructLayer(defaults, "defaults"))
+			require.NoError(t, err)
+		},
+	)
+
+	t.Run(
+		"violations", func(t *testing.T) {
+			t.Parallel()
+
+			var pp *validatedRoot
+
+			_, err := Fill(
+				&pp,
+				WithStringValueProvider(
+					&stubValuesProvider{
+						name: "env",
+						values: svalue.Values{
+							"server-port": {
+								Location: "env[APP-SERVER-PORT]",
+								Value:    "70000",
+							},
+							"server-backup": {
+								Location: "env[APP-SERVER-BACKUP]",
+								Value:    "a.local",
+							},
+							"tags-tier": {
+								Location: "env[APP-TAGS-TIER]",
+								Value:    "back",
+							},
+						},
+					},
+				),
+				WithStructLayer(defaults, "defaults"),
+			)
+			require.ErrorIs(t, err, ErrFiller)
+
+			var fillerErrors FillerErrors
+
+			require.ErrorAs(t, err, &fillerErrors)
+			require.Equal(t, 3, fillerErrors.Count())
+
+			for _, e := range fillerErrors.MError {
+				require.ErrorIs(t, e, ErrValidation)
+			}
+
+			var validationError ValidationError
+
+			require.ErrorAs(t, fillerErrors.MError[0], &validationError)
+			require.Equal(t, "Server.Port", validationError.Path)
+			require.Equal(t, "max=65535", validationError.Rule)
+			require.Equal(
+				t,
+				"env[APP-SERVER-PORT]",
+				validationError.Location.Location,
+			)
+
+			require.ErrorAs(t, fillerErrors.MError[1], &validationError)
+			require.Equal(t, "Server", validationError.Path)
+			require.ErrorIs(t, validationError, errSameHost)
+			require.Empty(t, validationError.Location)
+
+			require.ErrorAs(t, fillerErrors.MError[2], &validationError)
+			require.Equal(t, "Tags", validationError.Path)
+			require.Equal(t, "Tags[team]", validationError.Location.Path)
+		},
+	)
+}
+
+func TestValidationError(t *testing.T) {
+	t.Parallel()
+
+	err := ValidationError{
+		Path: "Server.Port",
+		Location: plocation.Location{
+			Path:     "Server.Port",
+			Location: "cmdline[--server-port]",
+		},
+		Rule: "max=65535",
+		Err:  errSameHost,
+	}
+
+	require.ErrorIs(t, err, ErrValidation)
+	require.ErrorIs(t, err, errSameHost)
+	require.Equal(
+		t,
+		"validation error on Server.Port from cmdline[--server-port] "+
+			"(max=65535): primary and backup hosts are the same",
+		err.Error(),
+	)
+
+	require.Equal(
+		t,
+		"validation error on root (Validate()): "+
+			"primary and backup hosts are the same",
+		ValidationError{Rule: "Validate()", Err: errSameHost}.Error(),
+	)
+}
@@ -0,0 +1,255 @@
+package dsco
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/byte4ever/dsco/internal/fvalue"
+	"github.com/byte4ever/dsco/internal/plocation"
+)
+
+// WarningKind is the category of a Warning.
+type WarningKind string
+
+const (
+	// WarningUnknownKey is a key of a normal layer bound to no field, i.e.
+	// a misspelled environment variable.
+	WarningUnknownKey WarningKind = "unknown_key"
+
+	// WarningShadowedKey is a value of a normal layer overridden by a
+	// higher-priority layer.
+	WarningShadowedKey WarningKind = "shadowed_key"
+
+	// WarningDeprecatedKey is a value provided for a dsco:"deprecated"
+	// field.
+	WarningDeprecatedKey WarningKind = "deprecated_key"
+
+	// WarningStaleSource is a remote layer whose values come from its cache
+	// file, the fetch having failed.
+	WarningStaleSource WarningKind = "stale_source"
+)
+
+// Warning is a non-fatal issue found while filling the structure.
+type Warning struct {
+	Kind     WarningKind        `json:"kind"`
+	Path     string             `json:"path,omitempty"`
+	Layer    DiagnosticLayerRef `json:"layer"`
+	Location string             `json:"location,omitempty"`
+
+	// OverrideLocation is the location of the value shadowing a
+	// WarningShadowedKey one.
+	OverrideLocation string `json:"override_location,omitempty"`
+
+	// Suggestions holds the closest valid keys of a WarningUnknownKey key.
+	Suggestions []string `json:"suggestions,omitempty"`
+
+	Message string `json:"message"`
+}
+
+func (w Warning) String() string {
+	return w.Message
+}
+
+// FillResult is the result of FillWithWarnings.
+type FillResult struct {
+	// Locations holds the location of every filled field, nil on error.
+	Locations plocation.Locations
+
+	// Warnings holds the non-fatal issues, grouped by layer in layer
+	// order.
+	Warnings []Warning
+}
+
+// FillWithWarnings fills the structure using the layers like FillContext
+// and reports the non-fatal issues of the layers as warnings:
+//
+//   - keys of normal layers that are bound to no field (strict layers
+//     report them as errors),
+//   - values of normal layers shadowed by higher-priority layers (strict
+//     layers report them as errors),
+//   - values provided for dsco:"deprecated" fields,
+//   - remote layers falling back on their cache file.
+//
+// Struct layers hold the program defaults and are never warned about. The
+// result is never nil: on error, it holds the warnings found before the
+// failure, which often explain it (i.e. a misspelled key leaving a field
+// uninitialized).
+func FillWithWarnings(
+	ctx context.Context,
+	inputModelRef any,
+	layers ...Layer,
+) (*FillResult, error) {
+	fillContext := newDSCOContext(ctx, inputModelRef, layers)
+	fillContext.warn = true
+
+	locations, err := fillContext.fillOrRestore()
+
+	// shadowed values are only known once the structure is filled
+	sort.SliceStable(
+		fillContext.warnings, func(i, j int) bool {
+			return fillContext.warnings[i].Layer.Index <
+				fillContext.warnings[j].Layer.Index
+		},
+	)
+
+	return &FillResult{
+		Locations: locations,
+		Warnings:  fillContext.warnings,
+	}, err
+}
+
+// collectLayerWarnings collects the warnings of the values got from the
+// layer of policy at position idx.
+func (c *dscoContext) collectLayerWarnings(
+	idx int,
+	policy constraintLayerPolicy,
+	values fvalue.Values,
+) {
+	if !c.warn {
+		return
+	}
+
+	builder, ok := policy.getFieldValuesGetter().(*StringBasedBuilder)
+	if !ok {
+		return
+	}
+
+	layer := DiagnosticLayerRef{Index: idx, Name: layerName(policy)}
+
+	if builder.stale != nil {
+		c.warnings = append(
+			c.warnings,
+			Warning{
+				Kind:  WarningStaleSource,
+				Layer: layer,
+				Message: fmt.Sprintf(
+					"%s values come from the cache file: %v",
+					layerLabel(layer),
+					builder.stale,
+				),
+			},
+		)
+	}
+
+	var warnings []Warning
+
+	for _, value := range values {
+		if !c.model.IsDeprecated(value.Path) {
+			continue
+		}
+
+		warnings = append(
+			warnings,
+			Warning{
+				Kind:     WarningDeprecatedKey,
+				Path:     value.Path,
+				Layer:    layer,
+				Location: value.Location,
+				Message: fmt.Sprintf(
+					"deprecated key %s set by %s",
+					value.Path,
+					value.Location,
+				),
+			},
+		)
+	}
+
+	if builder.ignoreUnbounded {
+		for _, unbounded := range builder.unboundedLocationErrors() {
+			warnings = append(
+				warnings,
+				Warning{
+					Kind:        WarningUnknownKey,
+					Layer:       layer,
+					Location:    unbounded.Location,
+					Suggestions: unbounded.Suggestions,
+					Message: "unknown key " + unbounded.Location +
+						didYouMean(unbounded.Suggestions),
+				},
+			)
+		}
+	}
+
+	c.addWarnings(warnings)
+}
+
+// collectShadowed collects the warnings of the values of normal string
+// based layers that were not used to fill the structure.
+func (c *dscoContext) collectShadowed() {
+	if !c.warn || !c.err.None() {
+		return
+	}
+
+	for idx, values := range c.layerFieldValues {
+		policy := c.builders[idx]
+
+		if policy.isStrict() {
+			continue
+		}
+
+		if _, ok := policy.getFieldValuesGetter().(*StringBasedBuilder); !ok {
+			continue
+		}
+
+		layer := DiagnosticLayerRef{Index: idx, Name: layerName(policy)}
+
+		var warnings []Warning
+
+		for uid, value := range values {
+			c.reportOverridden(
+				uid,
+				value,
+				func(err OverriddenKeyError) {
+					warnings = append(
+						warnings,
+						Warning{
+							Kind:             WarningShadowedKey,
+							Path:             err.Path,
+							Layer:            layer,
+							Location:         err.Location,
+							OverrideLocation: err.OverrideLocation,
+							Message: fmt.Sprintf(
+								"value of %s set by %s is shadowed by %s",
+								err.Path,
+								err.Location,
+								err.OverrideLocation,
+							),
+						},
+					)
+				},
+			)
+		}
+
+		c.addWarnings(warnings)
+	}
+}
+
+// addWarnings appends warnings sorted by location.
+func (c *dscoContext) addWarnings(warnings []Warning) {
+	sort.SliceStable(
+		warnings, func(i, j int) bool {
+			if warnings[i].Location != warnings[j].Location {
+				return warnings[i].Location < warnings[j].Location
+			}
+
+			return warnings[i].Kind < warnings[j].Kind
+		},
+	)
+
+	c.warnings = append(c.warnings, warnings...)
+}
+
+// layerLabel returns the label of layer in messages.
+func layerLabel(layer DiagnosticLayerRef) string {
+	var sb strings.Builder
+
+	_, _ = fmt.Fprintf(&sb, "layer #%d", layer.Index)
+
+	if layer.Name != "" {
+		sb.WriteString(" " + layer.Name)
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,233 @@
+package dsco
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFillWithWarnings(t *testing.T) {
+	t.Parallel()
+
+	type Database struct {
+		Host *string
+		Port *int
+	}
+
+	type Root struct {
+		Database *Database
+		Timeout  *time.Duration
+		Legacy   *string `dsco:"deprecated,optional"`
+	}
+
+	writeConfig := func(t *testing.T, content string) string {
+		t.Helper()
+
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+		return path
+	}
+
+	t.Run(
+		"warnings", func(t *testing.T) {
+			t.Parallel()
+
+			var cfg *Root
+
+			path := writeConfig(
+				t,
+				"database:\n  hots: db.local\n  port: 5432\nlegacy: x\n",
+			)
+
+			result, err := FillWithWarnings(
+				context.Background(),
+				&cfg,
+				WithCmdlineLayer(WithArgs("--database-port=5433")),
+				WithFileLayer(path),
+				WithStructLayer(
+					&Root{
+						Database: &Database{Host: R("localhost"), Port: R(1)},
+						Timeout:  R(time.Second),
+					},
+					"defaults",
+				),
+			)
+			require.NoError(t, err)
+			require.Equal(t, 5433, *cfg.Database.Port)
+			require.Equal(t, "localhost", *cfg.Database.Host)
+			require.NotEmpty(t, result.Locations)
+
+			file := DiagnosticLayerRef{Index: 1, Name: "file:" + path}
+			location := "file[" + path + "]:"
+
+			require.Equal(
+				t,
+				[]Warning{
+					{
+						Kind:        WarningUnknownKey,
+						Layer:       file,
+						Location:    location + "2:3",
+						Suggestions: []string{"database.host"},
+						Message: "unknown key " + location + "2:3, " +
+							"did you mean database.host?",
+					},
+					{
+						Kind:     WarningDeprecatedKey,
+						Path:     "Legacy",
+						Layer:    file,
+						Location: location + "4:1",
+						Message: "deprecated key Legacy set by " +
+							location + "4:1",
+					},
+					{
+						Kind:             WarningShadowedKey,
+						Path:             "Database.Port",
+						Layer:            file,
+						Location:         location + "3:3",
+						OverrideLocation: "cmdline[--database-port]",
+						Message: "value of Database.Port set by " +
+							location + "3:3 is shadowed by " +
+							"cmdline[--database-port]",
+					},
+				},
+				result.Warnings,
+			)
+			require.Equal(
+				t,
+				result.Warnings[0].Message,
+				result.Warnings[0].String(),
+			)
+		},
+	)
+
+	t.Run(
+		"explaining an error", func(t *testing.T) {
+			t.Parallel()
+
+			var cfg *Root
+
+			result, err := FillWithWarnings(
+				context.Background(),
+				&cfg,
+				WithFileLayer(
+					writeConfig(
+						t,
+						"database: {host: db.local, port: 5432}\n"+
+							"timeoutt: 1s\n",
+					),
+				),
+			)
+			require.ErrorContains(t, err, "Timeout")
+			require.Nil(t, cfg)
+			require.Nil(t, result.Locations)
+			require.Len(t, result.Warnings, 1)
+			require.Equal(t, WarningUnknownKey, result.Warnings[0].Kind)
+			require.Equal(
+				t,
+				[]string{"timeout"},
+				result.Warnings[0].Suggestions,
+			)
+		},
+	)
+
+	t.Run(
+		"strict layers", func(t *testing.T) {
+			t.Parallel()
+
+			var cfg *Root
+
+			result, err := FillWithWarnings(
+				context.Background(),
+				&cfg,
+				WithStrictEnvLayer(
+					"APP",
+					WithEnviron(
+						map[string]string{
+							"APP-DATABASE-HOST": "db.local",
+							"APP-DATABASE-PORT": "5432",
+							"APP-TIMEOUT":       "1s",
+							"APP-LEGACY":        "x",
+						},
+					),
+				),
+			)
+			require.NoError(t, err)
+			require.Len(t, result.Warnings, 1)
+			require.Equal(t, WarningDeprecatedKey, result.Warnings[0].Kind)
+		},
+	)
+
+	t.Run(
+		"stale source", func(t *testing.T) {
+			t.Parallel()
+
+			var down atomic.Bool
+
+			server := httptest.NewServer(
+				http.HandlerFunc(
+					func(w http.ResponseWriter, _ *http.Request) {
+						if down.Load() {
+							w.WriteHeader(http.StatusServiceUnavailable)
+							return
+						}
+
+						w.Header().Set("Content-Type", "application/json")
+						_, _ = w.Write(
+							[]byte(`{
+								"database": {"host": "db.local", "port": 1},
+								"timeout": "5s"
+							}`),
+						)
+					},
+				),
+			)
+			defer server.Close()
+
+			cacheFile := filepath.Join(t.TempDir(), "cache.json")
+
+			var cfg *Root
+
+			result, err := FillWithWarnings(
+				context.Background(),
+				&cfg,
+				WithHTTPLayer(server.URL, WithHTTPCacheFile(cacheFile)),
+			)
+			require.NoError(t, err)
+			require.Empty(t, result.Warnings)
+
+			down.Store(true)
+
+			result, err = FillWithWarnings(
+				context.Background(),
+				&cfg,
+				WithHTTPLayer(server.URL, WithHTTPCacheFile(cacheFile)),
+			)
+			require.NoError(t, err)
+			require.Len(t, result.Warnings, 1)
+			require.Equal(t, WarningStaleSource, result.Warnings[0].Kind)
+			require.Equal(
+				t,
+				DiagnosticLayerRef{Index: 0, Name: "http:" + server.URL},
+				result.Warnings[0].Layer,
+			)
+			require.Contains(t, result.Warnings[0].Message, "status 503")
+		},
+	)
+}
+
+func TestFill_noWarnings(t *testing.T) {
+	t.Parallel()
+
+	c := newDSCOContext(context.Background(), nil, nil)
+	c.collectLayerWarnings(0, nil, nil)
+	c.collectShadowed()
+	require.Nil(t, c.warnings)
+}
@@ -0,0 +1,393 @@
+package dsco
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	"github.com/byte4ever/dsco/internal/fvalue"
+	"github.com/byte4ever/dsco/internal/plocation"
+)
+
+// DefaultWatchInterval is the polling interval of Watch.
+const DefaultWatchInterval = 2 * time.Second
+
+// ErrInvalidWatchInterval represents an error where the polling interval of
+// a watch is not positive.
+var ErrInvalidWatchInterval = errors.New("invalid watch interval")
+
+// Change is the change of the value located at Path between two fills.
+// Old is zero for added values and New is zero for removed ones.
+type Change struct {
+	Path string
+	Old  plocation.Location
+	New  plocation.Location
+}
+
+// ChangeEvent is delivered by a Watcher after a reload. On success Changes
+// lists the changed paths and Locations is the new fill report. When the
+// reload failed, Err holds the Fill error and the current configuration is
+// kept.
+type ChangeEvent struct {
+	Err       error
+	Changes   []Change
+	Locations plocation.Locations
+}
+
+// Watcher holds a configuration reloaded when a watched layer source
+// changes.
+type Watcher[T any] struct {
+	current   atomic.Pointer[T]
+	events    chan ChangeEvent
+	layers    []Layer
+	paths     []string
+	model     ModelInterface
+	locations plocation.Locations
+}
+
+// watchedLayer is implemented by layers reading files, whose changes
+// trigger a reload.
+type watchedLayer interface {
+	watchedPaths() []string
+}
+
+func (o *FileLayer) watchedPaths() []string {
+	return []string{o.path}
+}
+
+func (o *StrictFileLayer) watchedPaths() []string {
+	return []string{o.path}
+}
+
+func (o *KFileLayer) watchedPaths() []string {
+	return []string{o.dir}
+}
+
+func (o *StrictKFileLayer) watchedPaths() []string {
+	return []string{o.dir}
+}
+
+// leasedLayer is implemented by layers reading leased values, whose
+// expiry triggers a reload.
+type leasedLayer interface {
+	RefreshAt() time.Time
+}
+
+// leaseExpired returns true when the lease of a layer value expired.
+func (w *Watcher[T]) leaseExpired() bool {
+	now := time.Now()
+
+	for _, layer := range w.layers {
+		ll, ok := layer.(leasedLayer)
+		if !ok {
+			continue
+		}
+
+		if refreshAt := ll.RefreshAt(); !refreshAt.IsZero() &&
+			!now.Before(refreshAt) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Watch fills inputModelRef like FillContext, then polls the files and kfile
+// directories of the layers every DefaultWatchInterval. When one of them
+// changes, or when the lease of a secret layer value expires, the whole
+// layer pipeline runs again and the new configuration replaces the current
+// one only if Fill succeeds. Polling stops and the
+// events channel is closed when ctx is done.
+//
+// inputModelRef receives the initial configuration only, use
+// Watcher.Config to get the current one.
+func Watch[T any](
+	ctx context.Context,
+	inputModelRef **T,
+	layers ...Layer,
+) (*Watcher[T], error) {
+	return WatchWithInterval(ctx, DefaultWatchInterval, inputModelRef, layers...)
+}
+
+// WatchWithInterval is Watch with a custom polling interval.
+func WatchWithInterval[T any](
+	ctx context.Context,
+	interval time.Duration,
+	inputModelRef **T,
+	layers ...Layer,
+) (*Watcher[T], error) {
+	if interval <= 0 {
+		return nil, fmt.Errorf("%s: %w", interval, ErrInvalidWatchInterval)
+	}
+
+	var paths []string
+
+	for _, layer := range layers {
+		if wl, ok := layer.(watchedLayer); ok {
+			paths = append(paths, wl.watchedPaths()...)
+		}
+	}
+
+	// stamped before the first fill, so no change is missed
+	stamps := stampPaths(paths)
+
+	locations, err := FillContext(ctx, inputModelRef, layers...)
+	if err != nil {
+		return nil, err
+	}
+
+	mdl, err := buildModel(*inputModelRef)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &Watcher[T]{
+		events:    make(chan ChangeEvent, 1),
+		layers:    layers,
+		paths:     paths,
+		model:     mdl,
+		locations: locations,
+	}
+
+	w.current.Store(*inputModelRef)
+
+	go w.run(ctx, interval, stamps)
+
+	return w, nil
+}
+
+// Config returns the current configuration.
+func (w *Watcher[T]) Config() *T {
+	return w.current.Load()
+}
+
+// Events returns the channel of reload events, closed when the watch
+// context is done. Reading it is optional: the configuration keeps being
+// reloaded, and an event not read before the next reload is replaced by
+// the latest one.
+func (w *Watcher[T]) Events() <-chan ChangeEvent {
+	return w.events
+}
+
+func (w *Watcher[T]) run(
+	ctx context.Context,
+	interval time.Duration,
+	stamps []string,
+) {
+	defer close(w.events)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		newStamps := stampPaths(w.paths)
+		if reflect.DeepEqual(stamps, newStamps) && !w.leaseExpired() {
+			continue
+		}
+
+		stamps = newStamps
+
+		event, changed := w.reload(ctx)
+
+		// a reload cut short by the end of the watch is not reported
+		if !changed || ctx.Err() != nil {
+			continue
+		}
+
+		w.publish(event)
+	}
+}
+
+// publish delivers event without blocking the reloads: an event not read
+// yet is replaced by the latest one.
+func (w *Watcher[T]) publish(event ChangeEvent) {
+	for {
+		select {
+		case w.events <- event:
+			return
+		default:
+		}
+
+		// run is the only sender, so the channel has room once drained
+		select {
+		case <-w.events:
+		default:
+		}
+	}
+}
+
+// reload fills a new configuration and swaps it in on success. It returns
+// false when the effective configuration did not change.
+func (w *Watcher[T]) reload(ctx context.Context) (ChangeEvent, bool) {
+	var next *T
+
+	locations, err := FillContext(ctx, &next, w.layers...)
+	if err != nil {
+		return ChangeEvent{Err: err}, true
+	}
+
+	changes := diffLeaves(
+		leafValues(w.model, reflect.ValueOf(w.current.Load())),
+		w.locations,
+		leafValues(w.model, reflect.ValueOf(next)),
+		locations,
+	)
+
+	w.current.Store(next)
+	w.locations = locations
+
+	if len(changes) == 0 {
+		return ChangeEvent{}, false
+	}
+
+	return ChangeEvent{
+		Changes:   changes,
+		Locations: locations,
+	}, true
+}
+
+// leafValues returns the values of the filled struct value by leaf path,
+// pointers being dereferenced.
+func leafValues(mdl ModelInterface, value reflect.Value) map[string]any {
+	result := make(map[string]any)
+
+	collectLeafValues(mdl.GetFieldValuesFor("", value), result)
+
+	return result
+}
+
+func collectLeafValues(values fvalue.Values, result map[string]any) {
+	for _, fieldValue := range values {
+		if fieldValue.Entries == nil {
+			result[fieldValue.Path] = reflect.Indirect(fieldValue.Value).Interface()
+			continue
+		}
+
+		for _, entryValues := range fieldValue.Entries {
+			collectLeafValues(entryValues, result)
+		}
+	}
+}
+
+// diffLeaves returns the sorted changes between two sets of leaf values and
+// their locations.
+func diffLeaves(
+	oldValues map[string]any,
+	oldLocations plocation.Locations,
+	newValues map[string]any,
+	newLocations plocation.Locations,
+) []Change {
+	oldByPath := locationsByPath(oldLocations)
+	newByPath := locationsByPath(newLocations)
+
+	paths := make(map[string]struct{}, len(oldValues)+len(newValues))
+	for path := range oldValues {
+		paths[path] = struct{}{}
+	}
+
+	for path := range newValues {
+		paths[path] = struct{}{}
+	}
+
+	var changes []Change
+
+	for path := range paths {
+		oldValue, oldFound := oldValues[path]
+		newValue, newFound := newValues[path]
+
+		if oldFound && newFound &&
+			reflect.DeepEqual(oldValue, newValue) &&
+			oldByPath[path].Location == newByPath[path].Location {
+			continue
+		}
+
+		changes = append(
+			changes,
+			Change{
+				Path: path,
+				Old:  oldByPath[path],
+				New:  newByPath[path],
+			},
+		)
+	}
+
+	sort.Slice(
+		changes, func(i, j int) bool {
+			return changes[i].Path < changes[j].Path
+		},
+	)
+
+	return changes
+}
+
+func locationsByPath(locations plocation.Locations) map[string]plocation.Location {
+	result := make(map[string]plocation.Location, len(locations))
+
+	for _, location := range locations {
+		result[location.Path] = location
+	}
+
+	return result
+}
+
+// stampPaths returns a content stamp of every path, a file or a directory
+// tree. Unreadable paths get a stamp of their error.
+func stampPaths(paths []string) []string {
+	stamps := make([]string, 0, len(paths))
+
+	for _, path := range paths {
+		stamps = append(stamps, stampPath(path))
+	}
+
+	return stamps
+}
+
+func stampPath(path string) string {
+	hash := sha256.New()
+
+	err := filepath.WalkDir(
+		path,
+		func(name string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			if d.IsDir() {
+				return nil
+			}
+
+			_, _ = io.WriteString(hash, name+"\x00")
+
+			// links are followed, so rotated kfile contents are seen
+			content, err := os.ReadFile(name)
+			if err != nil {
+				_, _ = io.WriteString(hash, err.Error())
+				return nil //nolint:nilerr // unreadable files are stamped
+			}
+
+			_, _ = hash.Write(content)
+
+			return nil
+		},
+	)
+	if err != nil {
+		return err.Error()
+	}
+
+	return fmt.Sprintf("%x", hash.Sum(nil))
+}
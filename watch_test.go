@@ -0,0 +1,292 @@
+package dsco
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/byte4ever/dsco/internal/plocation"
+	"github.com/byte4ever/dsco/svalue"
+)
+
+type watchedConfig struct {
+	Host    *string
+	Port    *int `dsco:"max=65535"`
+	Timeout *time.Duration
+}
+
+func nextEvent(t *testing.T, w *Watcher[watchedConfig]) ChangeEvent {
+	t.Helper()
+
+	select {
+	case event, ok := <-w.Events():
+		require.True(t, ok)
+
+		return event
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "no watch event")
+	}
+
+	return ChangeEvent{}
+}
+
+func TestWatch(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.yaml")
+
+	writeConfig := func(content string) {
+		// the stamp is content based, a rewrite within the same clock
+		// tick is seen
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	}
+
+	writeConfig("host: a.local\nport: 80\n")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var cfg *watchedConfig
+
+	w, err := WatchWithInterval(
+		ctx,
+		10*time.Millisecond,
+		&cfg,
+		WithFileLayer(path),
+		WithStructLayer(
+			&watchedConfig{Timeout: R(time.Second)},
+			"defaults",
+		),
+	)
+	require.NoError(t, err)
+	require.Equal(t, "a.local", *cfg.Host)
+	require.Same(t, cfg, w.Config())
+
+	writeConfig("host: b.local\nport: 80\n")
+
+	event := nextEvent(t, w)
+	require.NoError(t, event.Err)
+	require.Equal(
+		t,
+		[]Change{
+			{
+				Path: "Host",
+				Old: plocation.Location{
+					Path:     "Host",
+					Location: "file[" + path + "]:1:1",
+				},
+				New: plocation.Location{
+					Path:     "Host",
+					Location: "file[" + path + "]:1:1",
+				},
+			},
+		},
+		event.Changes,
+	)
+	require.Len(t, event.Locations, 3)
+	require.Equal(t, "b.local", *w.Config().Host)
+	require.Equal(t, "a.local", *cfg.Host)
+
+	writeConfig("host: b.local\nport: 70000\n")
+
+	event = nextEvent(t, w)
+
+	var validationError ValidationError
+
+	require.ErrorAs(t, event.Err, &validationError)
+	require.Equal(t, "Port", validationError.Path)
+	require.Equal(t, 80, *w.Config().Port)
+
+	writeConfig("host: b.local\n")
+
+	event = nextEvent(t, w)
+	require.ErrorContains(t, event.Err, "Port-[*int]: uninitialized key")
+
+	writeConfig("host: b.local\ntimeout: 3s\nport: 81\n")
+
+	event = nextEvent(t, w)
+	require.NoError(t, event.Err)
+	require.Equal(t, []string{"Port", "Timeout"}, changedPaths(event))
+	require.Equal(t, "struct[defaults]:Timeout", event.Changes[1].Old.Location)
+	require.Equal(t, 3*time.Second, *w.Config().Timeout)
+
+	cancel()
+
+	for range w.Events() { //nolint:revive // drain until closed
+	}
+}
+
+func changedPaths(event ChangeEvent) []string {
+	paths := make([]string, 0, len(event.Changes))
+
+	for _, change := range event.Changes {
+		paths = append(paths, change.Path)
+	}
+
+	return paths
+}
+
+func TestWatch_eventsNotRead(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "app.yaml")
+
+	writePort := func(port int) {
+		require.NoError(
+			t,
+			os.WriteFile(
+				path,
+				[]byte(fmt.Sprintf("host: a.local\nport: %d\n", port)),
+				0o600,
+			),
+		)
+	}
+
+	writePort(1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var cfg *watchedConfig
+
+	w, err := WatchWithInterval(
+		ctx,
+		10*time.Millisecond,
+		&cfg,
+		WithFileLayer(path),
+		WithStructLayer(
+			&watchedConfig{Timeout: R(time.Second)},
+			"defaults",
+		),
+	)
+	require.NoError(t, err)
+
+	// Events is never read, reloads go on
+	for port := 2; port <= 5; port++ {
+		writePort(port)
+
+		require.Eventually(
+			t,
+			func() bool {
+				return *w.Config().Port == port
+			},
+			5*time.Second,
+			5*time.Millisecond,
+		)
+	}
+
+	// the pending event is the latest one
+	event := nextEvent(t, w)
+	require.NoError(t, event.Err)
+	require.Equal(t, "5", fmt.Sprint(*w.Config().Port))
+}
+
+func TestWatch_context(t *testing.T) {
+	t.Parallel()
+
+	type ctxKey struct{}
+
+	path := filepath.Join(t.TempDir(), "app.yaml")
+
+	require.NoError(t, os.WriteFile(path, []byte("port: 80\n"), 0o600))
+
+	ctx, cancel := context.WithCancel(
+		context.WithValue(context.Background(), ctxKey{}, 1),
+	)
+	defer cancel()
+
+	provider := &ctxProvider{
+		values: svalue.Values{
+			"host": {Location: "ctx[host]", Value: "a.local"},
+		},
+	}
+
+	var cfg *watchedConfig
+
+	w, err := WatchWithInterval(
+		ctx,
+		10*time.Millisecond,
+		&cfg,
+		WithFileLayer(path),
+		WithContextProvider(provider),
+		WithStructLayer(
+			&watchedConfig{Timeout: R(time.Second)},
+			"defaults",
+		),
+	)
+	require.NoError(t, err)
+	require.Equal(t, 1, provider.ctx.Value(ctxKey{}))
+
+	provider.ctx = nil
+
+	require.NoError(t, os.WriteFile(path, []byte("port: 81\n"), 0o600))
+
+	event := nextEvent(t, w)
+	require.NoError(t, event.Err)
+	require.Equal(t, 1, provider.ctx.Value(ctxKey{}))
+
+	cancel()
+
+	for range w.Events() { //nolint:revive // drain until closed
+	}
+}
+
+func TestWatchWithInterval_errors(t *testing.T) {
+	t.Parallel()
+
+	var cfg *watchedConfig
+
+	_, err := WatchWithInterval(context.Background(), 0, &cfg)
+	require.ErrorIs(t, err, ErrInvalidWatchInterval)
+
+	_, err = Watch(context.Background(), &cfg)
+	require.ErrorIs(t, err, ErrFiller)
+}
+
+func Test_diffLeaves(t *testing.T) {
+	t.Parallel()
+
+	loc := func(path, location string) plocation.Location {
+		return plocation.Location{Path: path, Location: location}
+	}
+
+	changes := diffLeaves(
+		map[string]any{"A": R(1), "B": R(2), "C": R(3)},
+		plocation.Locations{loc("A", "l1"), loc("B", "l1"), loc("C", "l1")},
+		map[string]any{"A": R(1), "B": R(2), "D": R(4)},
+		plocation.Locations{loc("A", "l1"), loc("B", "l2"), loc("D", "l2")},
+	)
+
+	require.Equal(
+		t,
+		[]Change{
+			{Path: "B", Old: loc("B", "l1"), New: loc("B", "l2")},
+			{Path: "C", Old: loc("C", "l1")},
+			{Path: "D", New: loc("D", "l2")},
+		},
+		changes,
+	)
+}
+
+func Test_stampPath(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	name := filepath.Join(dir, "SECRET")
+
+	require.NoError(t, os.WriteFile(name, []byte("a"), 0o600))
+
+	stamp := stampPath(dir)
+	require.Equal(t, stamp, stampPath(dir))
+
+	require.NoError(t, os.WriteFile(name, []byte("b"), 0o600))
+	require.NotEqual(t, stamp, stampPath(dir))
+
+	require.NotEqual(t, stamp, stampPath(filepath.Join(dir, "missing")))
+}